@@ -0,0 +1,77 @@
+// Comando requeue: vuelve a encolar el procesamiento de videos desde la terminal.
+// Escribe las tareas en el outbox, así que la API debe estar corriendo para que el
+// relay las publique en SQS.
+//
+//	go run ./cmd/requeue -status failed -dry-run
+//	go run ./cmd/requeue -pipeline-version anb-default@v1 -rate 1
+//	go run ./cmd/requeue -video 42
+package main
+
+import (
+	"anb-app/src/config"
+	"anb-app/src/database"
+	"anb-app/src/task"
+	"anb-app/src/video"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	videoID := flag.Uint("video", 0, "ID de un video concreto")
+	status := flag.String("status", "", "estado de los videos (uploaded, processed, failed)")
+	from := flag.String("from", "", "subidos desde esta fecha (YYYY-MM-DD, inclusive)")
+	to := flag.String("to", "", "subidos antes de esta fecha (YYYY-MM-DD, exclusiva)")
+	pipelineVersion := flag.String("pipeline-version", "", "versión del pipeline que generó el video")
+	limit := flag.Int("limit", video.DefaultRequeueLimit, "máximo de videos a encolar")
+	rate := flag.Float64("rate", video.DefaultRequeueRate, "tareas por segundo que se liberan a la cola")
+	force := flag.Bool("force", false, "incluir videos con una tarea activa")
+	dryRun := flag.Bool("dry-run", false, "solo listar los videos que se reprocesarían")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "archivo YAML opcional con la configuración")
+	flag.Parse()
+
+	filter := video.RequeueFilter{
+		VideoID:         *videoID,
+		Status:          *status,
+		PipelineVersion: *pipelineVersion,
+		Force:           *force,
+		Limit:           *limit,
+	}
+	filter.UploadedFrom = parseDate("from", *from)
+	filter.UploadedTo = parseDate("to", *to)
+
+	// El comando solo necesita la base de datos
+	var cfg struct {
+		Database config.Database `yaml:"database"`
+	}
+	if _, err := config.Load(&cfg, *configFile); err != nil {
+		log.Fatal(err)
+	}
+	db := database.ConnectDB(cfg.Database)
+	requeuer := video.NewRequeuer(video.NewVideoRepository(db), task.NewTaskRepository(db))
+
+	result, err := requeuer.Requeue(filter, video.RequeueOptions{DryRun: *dryRun, RatePerSecond: *rate})
+	if err != nil {
+		log.Fatalf("Requeue failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(result)
+	if result.Error != "" {
+		os.Exit(1)
+	}
+}
+
+func parseDate(name, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		log.Fatalf("Invalid -%s date %q: %v", name, value, err)
+	}
+	return &date
+}
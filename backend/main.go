@@ -1,82 +1,127 @@
 package main
 
 import (
+	"anb-app/src/apperr"
 	"anb-app/src/auth"
+	"anb-app/src/config"
 	"anb-app/src/database"
+	"anb-app/src/events"
+	"anb-app/src/health"
+	"anb-app/src/i18n"
+	"anb-app/src/media"
+	"anb-app/src/openapi"
+	"anb-app/src/outbox"
+	"anb-app/src/pagination"
 	"anb-app/src/queue"
+	"anb-app/src/security"
 	"anb-app/src/storage"
+	"anb-app/src/task"
 	"anb-app/src/user"
 	"anb-app/src/video"
 	"anb-app/src/vote"
+	"anb-app/src/webhook"
+	"cmp"
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 var ctx = context.Background()
 
 func main() {
-	err := godotenv.Load()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "archivo YAML opcional con la configuración")
+	printConfig := flag.Bool("print-config", false, "mostrar la configuración efectiva (sin secretos) y salir")
+	flag.Parse()
+
+	// Variables de entorno > .env > YAML > valores por defecto
+	var cfg config.API
+	sources, err := config.Load(&cfg, *configFile)
+	if *printConfig {
+		config.Print(os.Stdout, &cfg, sources)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err != nil {
-		log.Println("Warning: File .env could'nt be found")
+		log.Fatal(err)
 	}
 
-	db := database.ConnectDB()
+	db := database.ConnectDB(cfg.Database)
 
 	database.MigrateTables(db)
-
-	jwtSecret := os.Getenv("JWT_SECRET")
-	serverPort := os.Getenv("SERVER_PORT")
+	database.PromoteAdmins(db, cfg.AdminEmails)
 
 	// Inicializar SQS Client
-	sqsQueueURL := os.Getenv("SQS_QUEUE_URL")
-	if sqsQueueURL == "" {
-		log.Fatal("SQS_QUEUE_URL environment variable is required")
-	}
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	queueClient, err := queue.NewSQSClient(ctx, sqsQueueURL, awsRegion)
+	queueClient, err := queue.NewSQSClient(ctx, cfg.AWS.SQSQueueURL, cfg.AWS.Region)
 	if err != nil {
 		log.Fatalf("Failed to initialize SQS client: %v", err)
 	}
 	defer queueClient.Close()
 
-	log.Printf("SQS Client connected: %s", sqsQueueURL)
+	log.Printf("SQS Client connected: %s", cfg.AWS.SQSQueueURL)
 
 	// Auth
-	authSvc := auth.NewAuthService(jwtSecret)
+	authSvc := auth.NewAuthService(cfg.JWTSecret)
 	authMiddleware := authSvc.AuthMiddleware()
 
 	// User
 	userRepo := user.NewUserRepository(db)
 	userSvc := user.NewUserService(userRepo, authSvc)
 	userController := user.NewUserController(userSvc)
+	adminMiddleware := user.RequireAdmin(userRepo)
 
 	// Video - Initialize S3 Storage
-	s3Bucket := os.Getenv("S3_BUCKET_NAME")
-	if s3Bucket == "" {
-		log.Fatal("S3_BUCKET_NAME environment variable is required")
-	}
-	region := os.Getenv("AWS_REGION")
-	if region == "" {
-		region = "us-east-1" // Default region
+	storageSvc, err := storage.NewS3StorageService(cfg.AWS.S3Bucket, cfg.AWS.Region)
+	if err != nil {
+		log.Fatalf("Failed to initialize S3 storage: %v", err)
 	}
+	log.Printf("S3 Storage initialized: bucket=%s, region=%s", cfg.AWS.S3Bucket, cfg.AWS.Region)
 
-	storageSvc, err := storage.NewS3StorageService(s3Bucket, region)
+	urlSigner, mediaCookies, err := newURLSigner(storageSvc, cfg.Media)
 	if err != nil {
-		log.Fatalf("Failed to initialize S3 storage: %v", err)
+		log.Fatalf("Failed to initialize URL signer: %v", err)
 	}
-	log.Printf("S3 Storage initialized: bucket=%s, region=%s", s3Bucket, region)
+
+	// Video events (SSE alimentado por LISTEN/NOTIFY)
+	eventRepo := events.NewEventRepository(db)
+	eventBroker := events.NewBroker(cfg.Database.DSN(), eventRepo)
+	go eventBroker.Run(ctx)
+	eventController := events.NewEventController(eventBroker, eventRepo)
+
+	// Task
+	taskRepo := task.NewTaskRepository(db)
+	taskSvc := task.NewTaskService(taskRepo)
+	taskController := task.NewTaskController(taskSvc)
 
 	videoRepo := video.NewVideoRepository(db)
-	videoSvc := video.NewVideoService(videoRepo, queueClient, storageSvc)
+	videoSvc := video.NewVideoService(videoRepo, storageSvc, taskRepo, urlSigner)
 	videoController := video.NewVideoController(videoSvc)
+	videoController.SetDownloadRate(int64(cfg.DownloadRateKBps) * 1024)
+	videoAdminController := video.NewAdminController(video.NewRequeuer(videoRepo, taskRepo))
+	// Borra de S3 los videos que superan el plazo de la papelera
+	go video.NewPurger(videoRepo, storageSvc).Run(ctx)
+
+	// Webhooks: los eventos llegan por el outbox y el dispatcher los entrega
+	webhookRepo := webhook.NewWebhookRepository(db)
+	webhookSvc := webhook.NewWebhookService(webhookRepo)
+	webhookController := webhook.NewWebhookController(webhookSvc)
+	go webhook.NewDispatcher(db).Run(ctx)
+
+	// Outbox: publica en SQS las tareas y reparte los eventos de webhooks
+	// registrados junto con cada cambio
+	outboxRelay := outbox.NewRelay(db)
+	outboxRelay.Handle(queue.TopicTasks, queue.OutboxHandler(queueClient))
+	outboxRelay.Handle(webhook.TopicWebhook, webhook.FanOut)
+	go outboxRelay.Run(ctx)
 
 	// Vote
 	voteRepo := vote.NewVoteRepository(db)
@@ -87,24 +132,37 @@ func main() {
 
 	// No longer serving static files - videos are served from S3 via presigned URLs
 
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	// CORS y headers de seguridad según CORS_* y HSTS_*
+	corsMiddleware, headersMiddleware, err := newSecurityMiddlewares(router, cfg.Security)
+	if err != nil {
+		log.Fatalf("Invalid CORS or security headers configuration: %v", err)
+	}
+	router.Use(headersMiddleware, corsMiddleware)
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Idioma de los mensajes según Accept-Language (es por defecto)
+	router.Use(i18n.Middleware())
+	// Documento OpenAPI generado de las rutas y los DTO. En modo test (GIN_MODE=test) cada
+	// petición y respuesta se compara con el documento y las diferencias quedan en el log.
+	// Va antes que apperr para ver también las respuestas problem+json.
+	apiSpec := openapi.New("ANB API", "v1")
+	if gin.Mode() == gin.TestMode {
+		router.Use(apiSpec.ValidationMiddleware(func(c *gin.Context, err error) {
+			log.Printf("openapi: %v", err)
+		}))
+	}
 
-		c.Next()
-	})
+	// Los errores que registran los handlers salen como problem+json (RFC 7807)
+	router.Use(apperr.Middleware())
 
 	apiV1 := router.Group("/api/v1")
 	{
 		user.SignUpUserRoutes(apiV1, userController)
 		video.SignUpVideoRoutes(apiV1, videoController, authMiddleware)
 		vote.SignUpVoteRoutes(apiV1, voteController, authMiddleware)
+		task.SignUpTaskRoutes(apiV1, taskController, authMiddleware)
+		events.SignUpEventRoutes(apiV1, eventController, authMiddleware)
+		video.SignUpVideoAdminRoutes(apiV1, videoAdminController, authMiddleware, adminMiddleware)
+		webhook.SignUpWebhookRoutes(apiV1, webhookController, authMiddleware, adminMiddleware)
 	}
 
 	// Backwards-compatible public endpoint without version: /api/public/videos
@@ -113,16 +171,178 @@ func main() {
 		videoController.ListPublicVideos(c)
 	})
 
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":   "ok",
-			"message":  "ANB API is running",
-			"database": "connected",
-			"sqs":      "connected",
-		})
+	apiSpec.Add("/api/v1", user.Operations...)
+	apiSpec.Add("/api/v1", video.Operations...)
+	apiSpec.Add("/api/v1", video.AdminOperations...)
+	apiSpec.Add("/api/v1", vote.Operations...)
+	apiSpec.Add("/api/v1", task.Operations...)
+	apiSpec.Add("/api/v1", events.Operations...)
+	apiSpec.Add("/api/v1", webhook.Operations...)
+	apiSpec.Add("/api", openapi.Operation{
+		Method: http.MethodGet, Path: "/public/videos", Tags: []string{"public"}, Deprecated: true,
+		Summary:  "Alias sin versión de /api/v1/public/videos",
+		Response: pagination.Page[video.PublicVideoResponse]{},
+		Errors:   []int{http.StatusBadRequest},
 	})
 
-	if err := router.Run(":" + serverPort); err != nil {
+	// Con cookies firmadas el cliente pide las cookies antes de reproducir
+	if mediaCookies != nil {
+		media.SignUpMediaRoutes(apiV1, media.NewMediaController(mediaCookies))
+		apiSpec.Add("/api/v1", media.Operations...)
+	}
+	// Sustituto local del CDN: sirve /media/* desde disco verificando firmas y cookies
+	if dir := cfg.Media.LocalDir; dir != "" {
+		localStore, err := storage.NewLocalStorageService(dir)
+		if err != nil {
+			log.Fatalf("Failed to initialize local media dir: %v", err)
+		}
+		mediaServer, err := media.NewServer(localStore, []byte(cfg.Media.SigningSecret))
+		if err != nil {
+			log.Fatalf("Failed to initialize local media server: %v", err)
+		}
+		media.MountServer(router, "/media", mediaServer)
+		log.Printf("Serving local media from %s at /media", dir)
+	}
+
+	apiDoc, err := apiSpec.Build(router.Routes(), "/api/")
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+	router.GET("/api/openapi.json", openapi.Handler(apiDoc))
+
+	// Liveness y readiness: /readyz comprueba de verdad Postgres, S3 y SQS
+	healthChecker := health.NewChecker(health.DefaultTimeout, health.DefaultCacheTTL,
+		health.Postgres(db),
+		health.Dependency{Name: "s3", Check: storageSvc.Ping},
+		health.Dependency{Name: "sqs", Check: queueClient.Ping},
+	)
+	router.GET("/livez", gin.WrapF(healthChecker.LiveHandler()))
+	router.GET("/readyz", gin.WrapF(healthChecker.ReadyHandler()))
+	// /health se mantiene por compatibilidad con los target groups existentes
+	router.GET("/health", gin.WrapF(healthChecker.ReadyHandler()))
+
+	if err := router.Run(":" + strconv.Itoa(cfg.Port)); err != nil {
 		log.Fatalf("Error, server couldn't start: %v", err)
 	}
 }
+
+// newURLSigner elige cómo se firman las URLs de los archivos: URL_SIGNER=s3 (por defecto)
+// o cloudfront. Con MEDIA_DELIVERY los archivos públicos (processed/) se entregan con URLs
+// estables por el CDN y los originales siguen usando URL_SIGNER. Las firmas se guardan en
+// caché y se renuevan al quedar un cuarto de su vigencia.
+func newURLSigner(storageSvc storage.StorageService, cfg config.Media) (storage.URLSigner, media.CookieIssuer, error) {
+	ttl := cfg.SignedURLTTL
+
+	var signer storage.URLSigner
+	switch cfg.URLSigner {
+	case "s3":
+		signer = storage.NewS3URLSigner(storageSvc, ttl)
+	case "cloudfront":
+		privateKey, err := readCloudFrontKey(cfg.CloudFront)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, err = storage.NewCloudFrontURLSigner(cfg.CloudFront.Domain, cfg.CloudFront.KeyPairID, privateKey, ttl)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown URL_SIGNER %q (expected s3 or cloudfront)", cfg.URLSigner)
+	}
+	log.Printf("URL signer initialized: %s, ttl=%s", cfg.URLSigner, ttl)
+
+	mediaSigner, cookies, err := newMediaDelivery(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mediaSigner != nil {
+		signer = storage.NewPrefixURLSigner(media.PublicPrefix, mediaSigner, signer)
+	}
+
+	return storage.NewCachingURLSigner(signer, ttl/4), cookies, nil
+}
+
+// newMediaDelivery arma la entrega por CDN según MEDIA_DELIVERY: signed-path (firma
+// de larga duración en la ruta), signed-cookie (URLs estables y cookie HMAC) o
+// cloudfront-cookie (URLs estables y cookies firmadas de CloudFront). Vacío la desactiva.
+func newMediaDelivery(cfg config.Media) (storage.URLSigner, media.CookieIssuer, error) {
+	mode := cfg.Delivery
+	if mode == "" {
+		return nil, nil, nil
+	}
+
+	ttl, rotation := cfg.URLTTL, cfg.URLRotation
+	baseURL := cfg.BaseURL
+	secret := []byte(cfg.SigningSecret)
+	cookieDomain := cfg.CookieDomain
+
+	log.Printf("Media delivery initialized: %s, ttl=%s", mode, ttl)
+	switch mode {
+	case "signed-path":
+		signer, err := media.NewPathSigner(baseURL, secret, ttl, rotation)
+		if err != nil {
+			return nil, nil, err
+		}
+		return signer, nil, nil
+	case "signed-cookie":
+		signer, err := media.NewCookieSigner(baseURL, secret, cookieDomain, ttl, rotation)
+		if err != nil {
+			return nil, nil, err
+		}
+		return signer, signer, nil
+	case "cloudfront-cookie":
+		privateKey, err := readCloudFrontKey(cfg.CloudFront)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, err := storage.NewCloudFrontCookieSigner(cfg.CloudFront.Domain, cfg.CloudFront.KeyPairID,
+			privateKey, media.PublicPrefix, cookieDomain, ttl)
+		if err != nil {
+			return nil, nil, err
+		}
+		return signer, signer, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown MEDIA_DELIVERY %q (expected signed-path, signed-cookie or cloudfront-cookie)", mode)
+	}
+}
+
+// newSecurityMiddlewares arma CORS y los headers de seguridad. Sin CORS_ALLOWED_ORIGINS
+// se acepta cualquier origen sin credenciales, como antes de que fuera configurable.
+func newSecurityMiddlewares(router *gin.Engine, cfg config.Security) (gin.HandlerFunc, gin.HandlerFunc, error) {
+	origins := cfg.AllowedOrigins
+	if len(origins) == 0 {
+		log.Println("Warning: CORS_ALLOWED_ORIGINS is not set, any origin can call the API")
+		origins = []string{"*"}
+	}
+	exposed := cfg.ExposedHeaders
+	if len(exposed) == 0 {
+		exposed = security.DefaultExposedHeaders
+	}
+	cors, err := security.CORS(router, security.CORSConfig{
+		AllowedOrigins:   origins,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+		AllowedHeaders:   security.DefaultAllowedHeaders,
+		ExposedHeaders:   exposed,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("CORS initialized: origins=%s", strings.Join(origins, ","))
+
+	headers := security.SecurityHeaders(security.HeadersConfig{
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		FrameDeny:             true,
+		ContentSecurityPolicy: cmp.Or(cfg.ContentSecurityPolicy, security.DefaultContentSecurityPolicy),
+	})
+	return cors, headers, nil
+}
+
+func readCloudFrontKey(cfg config.CloudFront) ([]byte, error) {
+	privateKey, err := os.ReadFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CLOUDFRONT_PRIVATE_KEY_PATH: %w", err)
+	}
+	return privateKey, nil
+}
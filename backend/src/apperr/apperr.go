@@ -0,0 +1,148 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Kind clasifica el error y decide el código HTTP
+type Kind string
+
+const (
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindGone         Kind = "gone"
+	KindRateLimited  Kind = "rate_limited"
+	KindInternal     Kind = "internal"
+)
+
+// Status es el código HTTP de cada tipo de error
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindGone:
+		return http.StatusGone
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldError es el detalle de un campo inválido en un error de validación. Si Code es un
+// tag con mensaje en el catálogo de i18n, Message se traduce con Field y Param.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"-"`
+}
+
+// Error es un error de la aplicación con un código estable para los clientes (p. ej.
+// "video_not_found"). Message se muestra al cliente; Err es la causa y solo se registra.
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string
+	Fields     []FieldError
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is compara por código, para usar los errores de cada paquete como centinelas:
+// errors.Is(err, video.ErrVideoNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap devuelve una copia con la causa
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// WithMessage devuelve una copia con otro mensaje y el mismo código
+func (e *Error) WithMessage(message string) *Error {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+func Gone(code, message string) *Error {
+	return newError(KindGone, code, message)
+}
+
+// Validation admite el detalle de los campos inválidos
+func Validation(code, message string, fields ...FieldError) *Error {
+	e := newError(KindValidation, code, message)
+	e.Fields = fields
+	return e
+}
+
+// RateLimited indica cuándo se puede reintentar (header Retry-After)
+func RateLimited(code, message string, retryAfter time.Duration) *Error {
+	e := newError(KindRateLimited, code, message)
+	e.RetryAfter = retryAfter
+	return e
+}
+
+// As devuelve el *Error de la cadena, si lo hay
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// KindOf devuelve el tipo del error; cualquier error sin tipo es interno
+func KindOf(err error) Kind {
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
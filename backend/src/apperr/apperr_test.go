@@ -0,0 +1,140 @@
+package apperr
+
+import (
+	"anb-app/src/i18n"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errThingNotFound = NotFound("thing_not_found", "thing not found")
+
+func TestError(t *testing.T) {
+	t.Run("IsMatchesByCode", func(t *testing.T) {
+		wrapped := fmt.Errorf("loading: %w", errThingNotFound.WithMessage("thing 4 not found"))
+
+		assert.ErrorIs(t, wrapped, errThingNotFound)
+		assert.NotErrorIs(t, wrapped, NotFound("other_not_found", "thing not found"))
+		assert.Equal(t, KindNotFound, KindOf(wrapped))
+	})
+
+	t.Run("WrapKeepsCauseWithoutChangingSentinel", func(t *testing.T) {
+		cause := errors.New("connection reset")
+		err := errThingNotFound.Wrap(cause)
+
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, "thing not found: connection reset", err.Error())
+		assert.Nil(t, errThingNotFound.Err)
+	})
+
+	t.Run("UntypedErrorsAreInternal", func(t *testing.T) {
+		assert.Equal(t, KindInternal, KindOf(errors.New("boom")))
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(Middleware())
+		router.GET("/things/:id", handler)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/things/4", nil)
+		req.Header.Set("Accept-Language", "en")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) Problem {
+		t.Helper()
+		assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		return problem
+	}
+
+	t.Run("RendersTypedError", func(t *testing.T) {
+		w := serve(func(c *gin.Context) { c.Error(errThingNotFound) })
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, Problem{
+			Type:     "urn:anb:problem:thing_not_found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "thing not found",
+			Instance: "/things/4",
+			Code:     "thing_not_found",
+		}, decode(t, w))
+	})
+
+	t.Run("HidesUntypedErrors", func(t *testing.T) {
+		w := serve(func(c *gin.Context) { c.Error(errors.New("pq: password authentication failed")) })
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		problem := decode(t, w)
+		assert.Equal(t, "internal_error", problem.Code)
+		assert.NotContains(t, w.Body.String(), "pq:")
+	})
+
+	t.Run("ValidationFields", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Error(Validation("invalid_thing", "invalid thing", FieldError{Field: "name", Code: "required", Message: "name is required"}))
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []FieldError{{Field: "name", Code: "required", Message: "name is required"}}, decode(t, w).Errors)
+	})
+
+	t.Run("RateLimitedSetsRetryAfter", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Error(RateLimited("too_many_uploads", "too many uploads", 1500*time.Millisecond))
+		})
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("KeepsWrittenResponse", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Error(errThingNotFound)
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+}
+
+func TestFromValidation(t *testing.T) {
+	type signUp struct {
+		Email     string `json:"email"     validate:"required,email"`
+		Password  string `json:"password"  validate:"required,min=8"`
+		Password2 string `json:"password2" validate:"required,eqfield=Password"`
+	}
+
+	err := FromValidation(NewValidator().Struct(signUp{Email: "ana", Password: "12345678", Password2: "87654321"}))
+
+	appErr, ok := As(err)
+	require.True(t, ok)
+	assert.Equal(t, "validation_failed", appErr.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "email", Code: "email", Message: "email must be a valid email address"},
+		{Field: "password2", Code: "eqfield", Message: "password2 must match password", Param: "password"},
+	}, appErr.Fields)
+
+	problem := NewProblem(err, "/api/v1/auth/signup").Localize(i18n.ES)
+	assert.Equal(t, "Uno o más campos no son válidos", problem.Detail)
+	assert.Equal(t, "password2 debe coincidir con password", problem.Errors[1].Message)
+	assert.Equal(t, "password2 must match password", appErr.Fields[1].Message, "Localize must not modify the error")
+
+	other := errors.New("not a validation error")
+	assert.Same(t, other, FromValidation(other))
+}
@@ -0,0 +1,35 @@
+// Package apperrtest reúne los helpers de los tests de controladores que revisan
+// respuestas problem+json.
+package apperrtest
+
+import (
+	"anb-app/src/apperr"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Handle ejecuta el handler seguido del middleware de errores, como en el router
+func Handle(handler gin.HandlerFunc, c *gin.Context) {
+	handler(c)
+	apperr.Middleware()(c)
+}
+
+// Decode lee una respuesta problem+json. Falla el test si el Content-Type no es el de
+// un problema o si el cuerpo no es un JSON válido.
+func Decode(t testing.TB, w *httptest.ResponseRecorder) apperr.Problem {
+	t.Helper()
+	require.Equal(t, apperr.ContentType, w.Header().Get("Content-Type"))
+	var problem apperr.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem), "body: %s", w.Body.String())
+	return problem
+}
+
+// ProblemCode devuelve el código estable de una respuesta problem+json
+func ProblemCode(t testing.TB, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	return Decode(t, w).Code
+}
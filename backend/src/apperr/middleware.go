@@ -0,0 +1,96 @@
+package apperr
+
+import (
+	"anb-app/src/i18n"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType es el tipo de las respuestas de error (RFC 7807)
+const ContentType = "application/problem+json"
+
+// TypePrefix arma el URI "type" de cada problema a partir de su código estable
+const TypePrefix = "urn:anb:problem:"
+
+// Problem es el cuerpo de una respuesta de error según RFC 7807, con el código estable
+// y el detalle de los campos como miembros de extensión
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem arma el problema de un error. Los errores sin tipo se reportan como
+// internos sin exponer su mensaje.
+func NewProblem(err error, instance string) Problem {
+	appErr, ok := As(err)
+	if !ok {
+		appErr = &Error{Kind: KindInternal, Code: "internal_error", Message: "an unexpected error occurred"}
+	}
+	status := appErr.Kind.Status()
+	return Problem{
+		Type:     TypePrefix + appErr.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Code,
+		Errors:   appErr.Fields,
+	}
+}
+
+// Middleware responde con problem+json el último error que los handlers registraron
+// con c.Error, si todavía no escribieron la respuesta
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		Render(c, err)
+	}
+}
+
+// Localize traduce el detalle y los mensajes de los campos que tienen entrada en el
+// catálogo. Lo que no tiene traducción se deja con el mensaje del error, que está en inglés.
+func (p Problem) Localize(lang i18n.Lang) Problem {
+	if detail, ok := i18n.Lookup(lang, "error."+p.Code); ok {
+		p.Detail = detail
+	}
+	if len(p.Errors) > 0 {
+		fields := make([]FieldError, len(p.Errors))
+		for i, field := range p.Errors {
+			if message, ok := i18n.Lookup(lang, "validation."+field.Code, field.Field, field.Param); ok {
+				field.Message = message
+			}
+			fields[i] = field
+		}
+		p.Errors = fields
+	}
+	return p
+}
+
+// Render escribe el error como problem+json en el idioma de la petición y corta la cadena
+// de handlers
+func Render(c *gin.Context, err error) {
+	problem := NewProblem(err, c.Request.URL.Path).Localize(i18n.FromContext(c))
+	if problem.Status >= http.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	if appErr, ok := As(err); ok && appErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+	}
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -0,0 +1,74 @@
+package apperr
+
+import (
+	"anb-app/src/i18n"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator reporta los campos con su nombre JSON (o de formulario) en lugar del
+// nombre del struct, para que FieldError.Field coincida con lo que envió el cliente
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	return validate
+}
+
+// FromValidation convierte los errores de validator en un error de validación con el
+// detalle de cada campo; cualquier otro error se devuelve sin cambios
+func FromValidation(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		field := FieldError{Field: fieldErr.Field(), Code: fieldErr.Tag(), Param: fieldParam(fieldErr)}
+		field.Message = fieldMessage(i18n.EN, field)
+		fields = append(fields, field)
+	}
+	return Validation("validation_failed", "one or more fields are invalid", fields...)
+}
+
+// fieldParam adapta el parámetro del tag para mostrarlo en el mensaje
+func fieldParam(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "eqfield":
+		return lowerFirst(fieldErr.Param())
+	case "oneof":
+		return strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	default:
+		return fieldErr.Param()
+	}
+}
+
+// fieldMessage traduce el mensaje del tag; los tags sin mensaje propio usan el genérico
+func fieldMessage(lang i18n.Lang, field FieldError) string {
+	if message, ok := i18n.Lookup(lang, "validation."+field.Code, field.Field, field.Param); ok {
+		return message
+	}
+	return i18n.T(lang, "validation.default", field.Field, field.Param)
+}
+
+// lowerFirst aproxima el nombre JSON del campo de comparación (eqfield usa el del struct)
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"net/http"
+	"anb-app/src/apperr"
 	"strconv"
 	"strings"
 
@@ -9,37 +9,40 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Todos los rechazos son 401 con el código "invalid_token"; el mensaje dice el motivo
+var errInvalidToken = apperr.Unauthorized("invalid_token", "invalid token")
+
 func (s *authService) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			apperr.Render(c, apperr.Unauthorized("missing_token", "authorization header is required"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
+			apperr.Render(c, errInvalidToken.WithMessage("authorization header format must be Bearer {token}"))
 			return
 		}
 		tokenString := parts[1]
 
 		token, err := s.ValidateToken(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			apperr.Render(c, errInvalidToken.Wrap(err))
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			apperr.Render(c, errInvalidToken.WithMessage("invalid token claims"))
 			return
 		}
 
 		userIDStr, _ := claims.GetSubject()
 		userID, err := strconv.ParseUint(userIDStr, 10, 32)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+			apperr.Render(c, errInvalidToken.WithMessage("invalid user ID in token"))
 			return
 		}
 
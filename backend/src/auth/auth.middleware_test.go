@@ -53,7 +53,7 @@ func TestAuthMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Authorization header is required")
+		assert.Contains(t, w.Body.String(), `"code":"missing_token"`)
 	})
 
 	t.Run("Fail_InvalidFormat", func(t *testing.T) {
@@ -70,7 +70,7 @@ func TestAuthMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Authorization header format must be Bearer")
+		assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
 	})
 
 	t.Run("Fail_InvalidToken", func(t *testing.T) {
@@ -87,7 +87,7 @@ func TestAuthMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid token")
+		assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
 	})
 
 	t.Run("Fail_ExpiredToken", func(t *testing.T) {
@@ -109,7 +109,7 @@ func TestAuthMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid token")
+		assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
 	})
 
 	t.Run("Fail_OnlyBearerNoToken", func(t *testing.T) {
@@ -126,6 +126,6 @@ func TestAuthMiddleware(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Authorization header format must be Bearer")
+		assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
 	})
 }
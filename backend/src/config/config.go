@@ -0,0 +1,124 @@
+// Package config carga la configuración tipada de la API y del worker desde las variables
+// de entorno, el archivo .env y un YAML opcional, y la valida al arrancar.
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cada campo declara su variable en el tag env, su clave en el YAML en el tag yaml, el valor
+// por defecto en default y las reglas de go-playground/validator en validate. Los campos con
+// secret:"true" no se muestran en --print-config.
+
+// Database es la conexión a PostgreSQL, compartida por la API, el worker y los comandos
+type Database struct {
+	Host     string `env:"DB_HOST" yaml:"host" validate:"required"`
+	Port     int    `env:"DB_PORT" yaml:"port" default:"5432" validate:"min=1,max=65535"`
+	User     string `env:"DB_USER" yaml:"user" validate:"required"`
+	Password string `env:"DB_PASSWORD" yaml:"password" secret:"true" validate:"required"`
+	Name     string `env:"DB_NAME" yaml:"name" validate:"required"`
+	SSLMode  string `env:"DB_SSLMODE" yaml:"sslmode" default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+}
+
+// DSN arma la cadena de conexión. También la usan las conexiones dedicadas, como la de
+// LISTEN para los eventos de videos.
+func (d Database) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+// AWS agrupa la región, el bucket de S3 y la cola de SQS
+type AWS struct {
+	Region      string `env:"AWS_REGION" yaml:"region" default:"us-east-1" validate:"required"`
+	S3Bucket    string `env:"S3_BUCKET_NAME" yaml:"s3_bucket" validate:"required"`
+	SQSQueueURL string `env:"SQS_QUEUE_URL" yaml:"sqs_queue_url" validate:"required,url"`
+}
+
+// CloudFront son las credenciales para firmar URLs y cookies del CDN
+type CloudFront struct {
+	Domain         string `env:"CLOUDFRONT_DOMAIN" yaml:"domain"`
+	KeyPairID      string `env:"CLOUDFRONT_KEY_PAIR_ID" yaml:"key_pair_id"`
+	PrivateKeyPath string `env:"CLOUDFRONT_PRIVATE_KEY_PATH" yaml:"private_key_path"`
+}
+
+// Media configura la firma de URLs de los archivos y su entrega por CDN
+type Media struct {
+	// URLSigner es s3 (URLs prefirmadas) o cloudfront
+	URLSigner    string        `env:"URL_SIGNER" yaml:"url_signer" default:"s3" validate:"oneof=s3 cloudfront"`
+	SignedURLTTL time.Duration `env:"SIGNED_URL_TTL" yaml:"signed_url_ttl" default:"1h" validate:"min=1m"`
+	// Delivery es signed-path, signed-cookie o cloudfront-cookie; vacío la desactiva
+	Delivery      string        `env:"MEDIA_DELIVERY" yaml:"delivery" validate:"omitempty,oneof=signed-path signed-cookie cloudfront-cookie"`
+	BaseURL       string        `env:"MEDIA_BASE_URL" yaml:"base_url" validate:"omitempty,url"`
+	SigningSecret string        `env:"MEDIA_SIGNING_SECRET" yaml:"signing_secret" secret:"true"`
+	URLTTL        time.Duration `env:"MEDIA_URL_TTL" yaml:"url_ttl" default:"168h" validate:"min=1m"`
+	URLRotation   time.Duration `env:"MEDIA_URL_ROTATION" yaml:"url_rotation" default:"24h" validate:"min=1m"`
+	CookieDomain  string        `env:"MEDIA_COOKIE_DOMAIN" yaml:"cookie_domain"`
+	// LocalDir sirve /media/* desde disco como sustituto local del CDN
+	LocalDir   string     `env:"MEDIA_LOCAL_DIR" yaml:"local_dir"`
+	CloudFront CloudFront `yaml:"cloudfront"`
+}
+
+// Security configura CORS y los headers de seguridad
+type Security struct {
+	// AllowedOrigins vacío acepta cualquier origen sin credenciales
+	AllowedOrigins        []string      `env:"CORS_ALLOWED_ORIGINS" yaml:"allowed_origins"`
+	AllowCredentials      bool          `env:"CORS_ALLOW_CREDENTIALS" yaml:"allow_credentials"`
+	CORSMaxAge            time.Duration `env:"CORS_MAX_AGE" yaml:"cors_max_age" default:"10m"`
+	ExposedHeaders        []string      `env:"CORS_EXPOSED_HEADERS" yaml:"exposed_headers"`
+	HSTSMaxAge            time.Duration `env:"HSTS_MAX_AGE" yaml:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `env:"HSTS_INCLUDE_SUBDOMAINS" yaml:"hsts_include_subdomains"`
+	ContentSecurityPolicy string        `env:"CONTENT_SECURITY_POLICY" yaml:"content_security_policy"`
+}
+
+// API es la configuración del servidor HTTP
+type API struct {
+	Port        int      `env:"SERVER_PORT" yaml:"port" default:"8080" validate:"min=1,max=65535"`
+	JWTSecret   string   `env:"JWT_SECRET" yaml:"jwt_secret" secret:"true" validate:"required"`
+	AdminEmails []string `env:"ADMIN_EMAILS" yaml:"admin_emails" validate:"dive,email"`
+	// DownloadRateKBps limita cada descarga a través de la API; 0 sin límite
+	DownloadRateKBps int      `env:"DOWNLOAD_RATE_KBPS" yaml:"download_rate_kbps" default:"4096" validate:"min=0"`
+	Database         Database `yaml:"database"`
+	AWS              AWS      `yaml:"aws"`
+	Media            Media    `yaml:"media"`
+	Security         Security `yaml:"security"`
+}
+
+func (a *API) validate() []string {
+	var problems []string
+	media := a.Media
+	if media.URLSigner == "cloudfront" || media.Delivery == "cloudfront-cookie" {
+		for _, field := range []struct{ name, value string }{
+			{"CLOUDFRONT_DOMAIN", media.CloudFront.Domain},
+			{"CLOUDFRONT_KEY_PAIR_ID", media.CloudFront.KeyPairID},
+			{"CLOUDFRONT_PRIVATE_KEY_PATH", media.CloudFront.PrivateKeyPath},
+		} {
+			if field.value == "" {
+				problems = append(problems, field.name+" is required with URL_SIGNER=cloudfront or MEDIA_DELIVERY=cloudfront-cookie")
+			}
+		}
+	}
+	if (media.Delivery == "signed-path" || media.Delivery == "signed-cookie") && media.BaseURL == "" {
+		problems = append(problems, "MEDIA_BASE_URL is required with MEDIA_DELIVERY="+media.Delivery)
+	}
+	if (media.Delivery == "signed-path" || media.Delivery == "signed-cookie" || media.LocalDir != "") && len(media.SigningSecret) < 32 {
+		problems = append(problems, "MEDIA_SIGNING_SECRET must be at least 32 bytes with signed media delivery or MEDIA_LOCAL_DIR")
+	}
+	if len(a.Security.AllowedOrigins) == 0 && a.Security.AllowCredentials {
+		problems = append(problems, "CORS_ALLOW_CREDENTIALS requires CORS_ALLOWED_ORIGINS")
+	}
+	return problems
+}
+
+// Worker es la configuración del procesador de videos
+type Worker struct {
+	// HealthCheckPort sirve /livez, /readyz, /health y /metrics
+	HealthCheckPort int `env:"HEALTH_CHECK_PORT" yaml:"health_check_port" default:"8080" validate:"min=1,max=65535"`
+	// PipelineSpecPath es un pipeline en YAML; vacío usa el embebido por defecto
+	PipelineSpecPath string `env:"PIPELINE_SPEC_PATH" yaml:"pipeline_spec_path"`
+	TempDir          string `env:"WORKER_TEMP_DIR" yaml:"temp_dir" default:"/tmp/video-processing" validate:"required"`
+	// DiskReserveMB es el espacio libre mínimo que se deja siempre en el disco
+	DiskReserveMB int      `env:"WORKER_DISK_RESERVE_MB" yaml:"disk_reserve_mb" default:"512" validate:"min=0"`
+	Database      Database `yaml:"database"`
+	AWS           AWS      `yaml:"aws"`
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup deja un .env y un YAML en un directorio temporal y limpia las variables que lee
+// la configuración, para que el entorno de quien corre los tests no influya
+func setup(t *testing.T, dotEnv, yamlFile string) string {
+	t.Helper()
+	dir := t.TempDir()
+	DotEnvPath = filepath.Join(dir, ".env")
+	t.Cleanup(func() { DotEnvPath = ".env" })
+	if dotEnv != "" {
+		require.NoError(t, os.WriteFile(DotEnvPath, []byte(dotEnv), 0o600))
+	}
+
+	for _, cfg := range []any{&API{}, &Worker{}} {
+		walk(reflect.ValueOf(cfg).Elem(), nil, func(field reflect.StructField, _ reflect.Value, _ []string) {
+			t.Setenv(field.Tag.Get("env"), "")
+		})
+	}
+
+	if yamlFile == "" {
+		return ""
+	}
+	path := filepath.Join(dir, "anb.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yamlFile), 0o600))
+	return path
+}
+
+const validWorkerEnv = `
+DB_HOST=localhost
+DB_USER=anb_user
+DB_PASSWORD=anb_password
+DB_NAME=anb_db
+S3_BUCKET_NAME=anb-videos
+SQS_QUEUE_URL=https://sqs.us-east-1.amazonaws.com/123/anb-queue
+`
+
+func TestLoadPrecedence(t *testing.T) {
+	file := setup(t, validWorkerEnv+"WORKER_DISK_RESERVE_MB=256\nHEALTH_CHECK_PORT=8081\n", `
+temp_dir: /data/work
+health_check_port: 9000
+database:
+  host: db.yaml.internal
+  port: 6543
+`)
+	t.Setenv("HEALTH_CHECK_PORT", "9100")
+
+	var cfg Worker
+	sources, err := Load(&cfg, file)
+	require.NoError(t, err)
+
+	// env > .env > yaml > default
+	assert.Equal(t, 9100, cfg.HealthCheckPort)
+	assert.Equal(t, SourceEnv, sources["HEALTH_CHECK_PORT"])
+	assert.Equal(t, 256, cfg.DiskReserveMB)
+	assert.Equal(t, SourceDotEnv, sources["WORKER_DISK_RESERVE_MB"])
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, SourceYAML, sources["DB_PORT"])
+	assert.Equal(t, "/data/work", cfg.TempDir)
+	assert.Equal(t, "disable", cfg.Database.SSLMode)
+	assert.Equal(t, SourceDefault, sources["DB_SSLMODE"])
+	assert.Equal(t, "us-east-1", cfg.AWS.Region)
+	assert.NotContains(t, sources, "PIPELINE_SPEC_PATH")
+	assert.Equal(t, "host=localhost user=anb_user password=anb_password dbname=anb_db port=6543 sslmode=disable", cfg.Database.DSN())
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	setup(t, "", "")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "five")
+	t.Setenv("DB_SSLMODE", "sometimes")
+	t.Setenv("SQS_QUEUE_URL", "anb-queue")
+	t.Setenv("ADMIN_EMAILS", "admin@anb.com, not-an-email")
+	t.Setenv("SIGNED_URL_TTL", "30s")
+	t.Setenv("URL_SIGNER", "cloudfront")
+	t.Setenv("CLOUDFRONT_DOMAIN", "d111.cloudfront.net")
+
+	var cfg API
+	_, err := Load(&cfg, "")
+	var configErr *Error
+	require.ErrorAs(t, err, &configErr)
+	assert.ElementsMatch(t, []string{
+		`DB_PORT (from env): "five" is not an integer`,
+		"DB_PORT must be at least 1",
+		"DB_USER is required",
+		"DB_PASSWORD is required",
+		"DB_NAME is required",
+		`DB_SSLMODE must be one of disable, allow, prefer, require, verify-ca, verify-full, got "sometimes"`,
+		"JWT_SECRET is required",
+		`ADMIN_EMAILS[1] must be an email, got "not-an-email"`,
+		"S3_BUCKET_NAME is required",
+		`SQS_QUEUE_URL must be a URL, got "anb-queue"`,
+		"SIGNED_URL_TTL must be at least 1m",
+		"CLOUDFRONT_KEY_PAIR_ID is required with URL_SIGNER=cloudfront or MEDIA_DELIVERY=cloudfront-cookie",
+		"CLOUDFRONT_PRIVATE_KEY_PATH is required with URL_SIGNER=cloudfront or MEDIA_DELIVERY=cloudfront-cookie",
+	}, configErr.Problems)
+	assert.Contains(t, err.Error(), "invalid configuration:\n  - ")
+}
+
+func TestLoadAPI(t *testing.T) {
+	file := setup(t, validWorkerEnv+"JWT_SECRET=a-long-enough-jwt-secret\n", `
+admin_emails: [ana@anb.com, luis@anb.com]
+security:
+  allowed_origins:
+    - https://anb.com
+    - https://*.anb.com
+  hsts_max_age: 8760h
+`)
+	t.Setenv("MEDIA_DELIVERY", "signed-path")
+	t.Setenv("MEDIA_BASE_URL", "https://media.anb.com")
+	t.Setenv("MEDIA_SIGNING_SECRET", "short")
+
+	var cfg API
+	_, err := Load(&cfg, file)
+	var configErr *Error
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, []string{"MEDIA_SIGNING_SECRET must be at least 32 bytes with signed media delivery or MEDIA_LOCAL_DIR"}, configErr.Problems)
+
+	assert.Equal(t, []string{"ana@anb.com", "luis@anb.com"}, cfg.AdminEmails)
+	assert.Equal(t, []string{"https://anb.com", "https://*.anb.com"}, cfg.Security.AllowedOrigins)
+	assert.Equal(t, 8760*time.Hour, cfg.Security.HSTSMaxAge)
+	assert.Equal(t, 10*time.Minute, cfg.Security.CORSMaxAge)
+	assert.Equal(t, 4096, cfg.DownloadRateKBps)
+}
+
+func TestLoadMissingYAML(t *testing.T) {
+	setup(t, validWorkerEnv, "")
+	_, err := Load(&Worker{}, "does-not-exist.yaml")
+	assert.Error(t, err)
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	setup(t, validWorkerEnv, "")
+	t.Setenv("DB_PASSWORD", "super-secret-password")
+
+	var cfg Worker
+	sources, err := Load(&cfg, "")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, &cfg, sources))
+	assert.NotContains(t, out.String(), "super-secret-password")
+	assert.Regexp(t, `DB_PASSWORD=\[redacted\]\s+# env\n`, out.String())
+	assert.Regexp(t, `DB_HOST=localhost\s+# \.env\n`, out.String())
+	assert.Regexp(t, `WORKER_TEMP_DIR=/tmp/video-processing\s+# default\n`, out.String())
+	assert.Regexp(t, `PIPELINE_SPEC_PATH=\s+# unset\n`, out.String())
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Orígenes de cada valor, de menor a mayor prioridad
+const (
+	SourceDefault = "default"
+	SourceYAML    = "yaml"
+	SourceDotEnv  = ".env"
+	SourceEnv     = "env"
+)
+
+// DotEnvPath es el archivo .env que se lee si existe
+var DotEnvPath = ".env"
+
+// Sources indica de dónde salió cada variable (por su nombre en env); las que no tienen
+// valor no aparecen
+type Sources map[string]string
+
+// Error junta todos los problemas de la configuración para informarlos de una vez
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load llena cfg (un puntero a API, Worker o un struct con los mismos tags) con los valores
+// por defecto, el YAML de file (opcional), el .env y las variables de entorno, en ese orden
+// de prioridad creciente. Una variable vacía cuenta como no definida. Devuelve un *Error con
+// todos los valores inválidos y las reglas que no se cumplen; cfg queda cargado igual para
+// poder mostrarlo.
+func Load(cfg any, file string) (Sources, error) {
+	var problems []string
+
+	fromYAML := map[string]any{}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", file, err)
+		}
+	}
+
+	// El .env también se exporta al entorno: el SDK de AWS lee de ahí sus credenciales
+	dotEnv, err := godotenv.Read(DotEnvPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("config: %s: %w", DotEnvPath, err)
+	}
+	fromEnv := map[string]string{}
+	for name := range dotEnv {
+		if value := os.Getenv(name); value != "" {
+			fromEnv[name] = value
+		}
+	}
+	if err == nil {
+		godotenv.Load(DotEnvPath)
+	}
+
+	sources := Sources{}
+	lookup := func(field reflect.StructField, yamlPath []string) (string, string) {
+		name := field.Tag.Get("env")
+		if value := os.Getenv(name); value != "" {
+			// Lo que godotenv acaba de exportar viene del .env, no del entorno
+			if _, inDotEnv := dotEnv[name]; !inDotEnv || fromEnv[name] == value {
+				return value, SourceEnv
+			}
+		}
+		if value := dotEnv[name]; value != "" {
+			return value, SourceDotEnv
+		}
+		if value, ok := yamlValue(fromYAML, yamlPath); ok {
+			return value, SourceYAML
+		}
+		return field.Tag.Get("default"), SourceDefault
+	}
+
+	walk(reflect.ValueOf(cfg).Elem(), nil, func(field reflect.StructField, value reflect.Value, yamlPath []string) {
+		raw, source := lookup(field, yamlPath)
+		if raw == "" {
+			return
+		}
+		name := field.Tag.Get("env")
+		sources[name] = source
+		if err := setValue(value, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (from %s): %v", name, source, err))
+		}
+	})
+
+	problems = append(problems, validate(cfg)...)
+	if len(problems) > 0 {
+		return sources, &Error{Problems: problems}
+	}
+	return sources, nil
+}
+
+// walk recorre los campos con tag env, también dentro de los structs anidados
+func walk(v reflect.Value, yamlPath []string, visit func(field reflect.StructField, value reflect.Value, yamlPath []string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := append(slices.Clone(yamlPath), strings.Split(field.Tag.Get("yaml"), ",")[0])
+		if field.Tag.Get("env") != "" {
+			visit(field, v.Field(i), path)
+		} else if field.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), path, visit)
+		}
+	}
+}
+
+// yamlValue busca la clave anidada y la convierte al mismo texto que tendría en una variable
+func yamlValue(tree map[string]any, path []string) (string, bool) {
+	var node any = tree
+	for _, key := range path {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if node, ok = object[key]; !ok || node == nil {
+			return "", false
+		}
+	}
+	if list, ok := node.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), true
+	}
+	return fmt.Sprint(node), true
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration (e.g. 90s, 10m, 24h)", raw)
+		}
+		value.SetInt(int64(duration))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		value.SetBool(parsed)
+	case value.Kind() == reflect.Int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		value.SetInt(int64(parsed))
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// validate aplica los tags validate y las reglas entre campos de la configuración
+func validate(cfg any) []string {
+	checker := validator.New()
+	checker.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("env")
+	})
+
+	var problems []string
+	var validationErrors validator.ValidationErrors
+	if err := checker.Struct(cfg); errors.As(err, &validationErrors) {
+		for _, fieldErr := range validationErrors {
+			problems = append(problems, ruleMessage(fieldErr))
+		}
+	}
+	if rules, ok := cfg.(interface{ validate() []string }); ok {
+		problems = append(problems, rules.validate()...)
+	}
+	return problems
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	name := fieldErr.Field()
+	switch fieldErr.Tag() {
+	case "required":
+		return name + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s, got %q", name, strings.ReplaceAll(fieldErr.Param(), " ", ", "), fieldErr.Value())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", name, fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", name, fieldErr.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a URL, got %q", name, fieldErr.Value())
+	case "email":
+		return fmt.Sprintf("%s must be an email, got %q", name, fieldErr.Value())
+	default:
+		return fmt.Sprintf("%s does not satisfy %s=%s", name, fieldErr.Tag(), fieldErr.Param())
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Redacted reemplaza los secretos al mostrar la configuración
+const Redacted = "[redacted]"
+
+// Print escribe la configuración efectiva como VARIABLE=valor con el origen de cada valor,
+// para --print-config. Los secretos definidos se muestran como [redacted].
+func Print(w io.Writer, cfg any, sources Sources) error {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	walk(reflect.ValueOf(cfg).Elem(), nil, func(field reflect.StructField, value reflect.Value, _ []string) {
+		name := field.Tag.Get("env")
+		text := formatValue(value)
+		if field.Tag.Get("secret") == "true" && text != "" {
+			text = Redacted
+		}
+		source := sources[name]
+		if source == "" {
+			source = "unset"
+		}
+		fmt.Fprintf(out, "%s=%s\t# %s\n", name, text, source)
+	})
+	return out.Flush()
+}
+
+func formatValue(value reflect.Value) string {
+	switch {
+	case value.Type() == durationType:
+		if value.Int() == 0 {
+			return ""
+		}
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Slice:
+		return strings.Join(value.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -2,39 +2,25 @@
 package database
 
 import (
+	"anb-app/src/config"
+	"anb-app/src/events"
+	"anb-app/src/outbox"
+	"anb-app/src/task"
 	"anb-app/src/user"
 	"anb-app/src/video"
 	"anb-app/src/vote"
-	"fmt"
+	"anb-app/src/webhook"
 	"log"
-	"os"
+	"strings"
 	"time"
 
-	"github.com/joho/godotenv"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectDB() *gorm.DB {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Advertencia: No se pudo encontrar el archivo .env, se usarán las variables de entorno del sistema.")
-	}
-
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
-	if sslmode == "" {
-		sslmode = "disable" // Default para desarrollo local
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		host, user, password, dbname, port, sslmode)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func ConnectDB(cfg config.Database) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatalf("Error fatal al conectar a la base de datos: %v", err)
 	}
@@ -47,16 +33,41 @@ func MigrateTables(db *gorm.DB) {
 	log.Println("Verificando estado de las tablas...")
 
 	// Ejecutar migraciones automáticas
-	err := db.AutoMigrate(&user.User{}, &video.Video{}, &vote.Vote{})
+	err := db.AutoMigrate(&user.User{}, &video.Video{}, &vote.Vote{}, &task.ProcessingJob{}, &events.VideoEvent{},
+		&outbox.Message{}, &webhook.Endpoint{}, &webhook.Delivery{})
 	if err != nil {
 		log.Fatalf("Error al ejecutar las migraciones: %v", err)
 	}
+	if err := MigrateSearch(db); err != nil {
+		log.Fatalf("Error al preparar la búsqueda de texto completo: %v", err)
+	}
 	log.Println("Migraciones completadas.")
 
 	// Poblar con datos de prueba si está habilitado
 	SeedDatabase(db)
 }
 
+// PromoteAdmins asigna el rol de administrador a los correos indicados (p. ej. la
+// variable ADMIN_EMAILS). No quita el rol a nadie.
+func PromoteAdmins(db *gorm.DB, emails []string) {
+	var list []string
+	for _, email := range emails {
+		if email = strings.TrimSpace(strings.ToLower(email)); email != "" {
+			list = append(list, email)
+		}
+	}
+	if len(list) == 0 {
+		return
+	}
+
+	result := db.Model(&user.User{}).Where("LOWER(email) IN ?", list).Update("role", user.RoleAdmin)
+	if result.Error != nil {
+		log.Printf("Error al asignar administradores: %v", result.Error)
+		return
+	}
+	log.Printf("Administradores configurados: %d de %d correos encontrados", result.RowsAffected, len(list))
+}
+
 func SeedDatabase(db *gorm.DB) {
 	// Verificar si ya hay datos en la base
 	var userCount int64
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"anb-app/src/video"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// searchMigrations crea la configuración de búsqueda en español sin acentos, las columnas
+// tsvector generadas de videos y usuarios y sus índices GIN. Todas las sentencias son
+// idempotentes, así que se ejecutan en cada arranque después de AutoMigrate.
+var searchMigrations = []string{
+	`CREATE EXTENSION IF NOT EXISTS unaccent`,
+	fmt.Sprintf(`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '%[1]s') THEN
+		CREATE TEXT SEARCH CONFIGURATION %[1]s (COPY = spanish);
+		ALTER TEXT SEARCH CONFIGURATION %[1]s
+			ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+	END IF;
+END
+$$`, video.SearchConfig),
+	fmt.Sprintf(`ALTER TABLE videos ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('%[1]s', coalesce(description, '')), 'B')
+	) STORED`, video.SearchConfig),
+	fmt.Sprintf(`ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('%[1]s', coalesce(first_name, '') || ' ' || coalesce(last_name, '')), 'A') ||
+		setweight(to_tsvector('%[1]s', coalesce(city, '')), 'C')
+	) STORED`, video.SearchConfig),
+	`CREATE INDEX IF NOT EXISTS idx_videos_search_vector ON videos USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector)`,
+}
+
+// MigrateSearch prepara la base de datos para la búsqueda de texto completo
+func MigrateSearch(db *gorm.DB) error {
+	for _, statement := range searchMigrations {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
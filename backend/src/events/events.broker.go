@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// Pausa antes de reabrir la conexión de LISTEN tras un error
+	listenRetryDelay = 5 * time.Second
+	// Eventos pendientes por suscriptor; si un cliente lento los llena se le desconecta
+	subscriberBuffer = 64
+	// Los eventos más antiguos que esto ya no se pueden recuperar con Last-Event-ID
+	retention     = 24 * time.Hour
+	pruneInterval = time.Hour
+)
+
+// Broker reparte entre los streams abiertos en esta instancia los eventos que llegan
+// por LISTEN. Cualquier instancia (API o worker) puede publicar con Publish.
+type Broker struct {
+	dsn  string
+	repo EventRepository
+
+	mu          sync.Mutex
+	subscribers map[uint]map[chan VideoEvent]struct{}
+}
+
+func NewBroker(dsn string, repo EventRepository) *Broker {
+	return &Broker{
+		dsn:         dsn,
+		repo:        repo,
+		subscribers: make(map[uint]map[chan VideoEvent]struct{}),
+	}
+}
+
+// Subscribe registra un stream para los eventos del usuario. El canal se cierra al
+// llamar a cancel o si el cliente no consume a tiempo.
+func (b *Broker) Subscribe(userID uint) (<-chan VideoEvent, func()) {
+	ch := make(chan VideoEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan VideoEvent]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.remove(userID, ch)
+		})
+	}
+	return ch, cancel
+}
+
+// remove quita y cierra el canal si sigue registrado; requiere b.mu
+func (b *Broker) remove(userID uint, ch chan VideoEvent) {
+	subs, ok := b.subscribers[userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
+
+// dispatch entrega el evento a los streams del usuario sin bloquear al listener
+func (b *Broker) dispatch(userID uint, event VideoEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+			// El cliente se reconectará con Last-Event-ID y recuperará lo perdido
+			log.Printf("Video events: dropping slow subscriber for user %d", userID)
+			b.remove(userID, ch)
+		}
+	}
+}
+
+// Run escucha el canal de Postgres hasta que ctx termina, reconectando si la
+// conexión se cae, y purga periódicamente los eventos antiguos.
+func (b *Broker) Run(ctx context.Context) {
+	go b.prune(ctx)
+
+	for {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Video events: listener error, reconnecting in %s: %v", listenRetryDelay, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+	log.Printf("Video events: listening on channel %s", Channel)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var payload notification
+		if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+			log.Printf("Video events: ignoring malformed notification: %v", err)
+			continue
+		}
+		event := payload.VideoEvent
+		event.UserID = payload.UserID
+		b.dispatch(payload.UserID, event)
+	}
+}
+
+func (b *Broker) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if deleted, err := b.repo.DeleteOlderThan(time.Now().Add(-retention)); err != nil {
+				log.Printf("Video events: failed to prune old events: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Video events: pruned %d old events", deleted)
+			}
+		}
+	}
+}
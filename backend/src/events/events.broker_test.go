@@ -0,0 +1,50 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker(t *testing.T) {
+	t.Run("Dispatch_OnlyToOwner", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		mine, cancelMine := broker.Subscribe(1)
+		defer cancelMine()
+		other, cancelOther := broker.Subscribe(2)
+		defer cancelOther()
+
+		broker.dispatch(1, VideoEvent{ID: 10, UserID: 1, VideoID: 3})
+
+		assert.Equal(t, uint64(10), (<-mine).ID)
+		assert.Empty(t, other)
+	})
+
+	t.Run("Cancel_ClosesChannel", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		ch, cancel := broker.Subscribe(1)
+
+		cancel()
+		cancel()
+
+		_, ok := <-ch
+		assert.False(t, ok)
+		assert.Empty(t, broker.subscribers)
+	})
+
+	t.Run("Dispatch_DropsSlowSubscriber", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		ch, cancel := broker.Subscribe(1)
+		defer cancel()
+
+		for i := 0; i <= subscriberBuffer; i++ {
+			broker.dispatch(1, VideoEvent{ID: uint64(i + 1), UserID: 1})
+		}
+
+		received := 0
+		for range ch {
+			received++
+		}
+		assert.Equal(t, subscriberBuffer, received)
+	})
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Eventos que se reenvían como máximo al reconectar
+	replayLimit = 500
+	// Comentario periódico para que proxies y ALB no cierren la conexión ociosa
+	heartbeatInterval = 15 * time.Second
+	// Espera sugerida al navegador antes de reconectar
+	retryMillis = 3000
+)
+
+type EventBroker interface {
+	Subscribe(userID uint) (<-chan VideoEvent, func())
+}
+
+type EventController struct {
+	broker    EventBroker
+	eventRepo EventRepository
+}
+
+func NewEventController(broker EventBroker, eventRepo EventRepository) *EventController {
+	return &EventController{
+		broker:    broker,
+		eventRepo: eventRepo,
+	}
+}
+
+// Stream mantiene abierto un stream SSE con los cambios de los videos del usuario.
+// Si el cliente envía Last-Event-ID (o ?last_event_id=) primero recibe lo que se perdió.
+func (ec *EventController) Stream(c *gin.Context) {
+	userIDClaim, exists := c.Get("userID")
+	if !exists {
+		c.Error(errUnauthenticated)
+		return
+	}
+	userID := userIDClaim.(uint)
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		c.Error(ErrInvalidLastEventID)
+		return
+	}
+
+	// Suscribirse antes de leer el historial para no perder eventos entre ambos pasos
+	live, cancel := ec.broker.Subscribe(userID)
+	defer cancel()
+
+	var missed []VideoEvent
+	if lastEventID > 0 {
+		missed, err = ec.eventRepo.FindAfter(userID, lastEventID, replayLimit)
+		if err != nil {
+			c.Error(fmt.Errorf("retrieving missed events: %w", err))
+			return
+		}
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Evita que nginx acumule la respuesta en un buffer
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", retryMillis)
+	// Lo enviado en la recuperación puede llegar también en vivo. Se descarta por ID y
+	// no por ser menor que el último: los IDs no llegan en orden cuando las
+	// transacciones que los insertan confirman en otro orden.
+	replayed := make(map[uint64]struct{}, len(missed))
+	for _, event := range missed {
+		writeEvent(c.Writer, event)
+		replayed[event.ID] = struct{}{}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-live:
+			if !ok {
+				// Suscripción cerrada por ser un cliente lento: el navegador reconectará
+				return
+			}
+			// Ya enviado durante la recuperación; cada ID llega en vivo una sola vez
+			if _, ok := replayed[event.ID]; ok {
+				delete(replayed, event.ID)
+				continue
+			}
+			writeEvent(c.Writer, event)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+func parseLastEventID(c *gin.Context) (uint64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func writeEvent(w gin.ResponseWriter, event VideoEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
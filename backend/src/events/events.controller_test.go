@@ -0,0 +1,166 @@
+package events
+
+import (
+	"anb-app/src/apperr/apperrtest"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventRepository) FindAfter(userID uint, afterID uint64, limit int) ([]VideoEvent, error) {
+	args := m.Called(userID, afterID, limit)
+	return args.Get(0).([]VideoEvent), args.Error(1)
+}
+
+func (m *MockEventRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeBroker expone el canal del suscriptor para que el test empuje eventos
+type fakeBroker struct {
+	ch chan VideoEvent
+}
+
+func (b *fakeBroker) Subscribe(userID uint) (<-chan VideoEvent, func()) {
+	return b.ch, func() {}
+}
+
+// runStream ejecuta Stream hasta que se entregan los eventos en vivo y luego corta la conexión
+func runStream(t *testing.T, controller *EventController, lastEventID string, live []VideoEvent, broker *fakeBroker) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/videos/events", nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("userID", uint(1))
+
+	done := make(chan struct{})
+	go func() {
+		controller.Stream(c)
+		close(done)
+	}()
+	for _, event := range live {
+		broker.ch <- event
+	}
+	// Dar tiempo a que se escriba el último evento antes de cerrar
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+	return w
+}
+
+func TestEventController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Stream_SendsLiveEvents", func(t *testing.T) {
+		broker := &fakeBroker{ch: make(chan VideoEvent)}
+		controller := NewEventController(broker, new(MockEventRepository))
+
+		w := runStream(t, controller, "", []VideoEvent{{ID: 7, VideoID: 3, Type: TypeTask, Status: "processing", Step: "scale", Progress: 40}}, broker)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "id: 7\nevent: task\ndata: ")
+		assert.Contains(t, body, `"step":"scale"`)
+		assert.NotContains(t, body, "user_id")
+	})
+
+	t.Run("Stream_ReplaysMissedEventsWithoutDuplicates", func(t *testing.T) {
+		broker := &fakeBroker{ch: make(chan VideoEvent)}
+		repo := new(MockEventRepository)
+		controller := NewEventController(broker, repo)
+
+		repo.On("FindAfter", uint(1), uint64(5), replayLimit).Return([]VideoEvent{
+			{ID: 6, Type: TypeTask, Status: "processing"},
+			{ID: 7, Type: TypeTask, Status: "processing"},
+		}, nil)
+
+		// El 7 llega también en vivo porque la suscripción se abrió antes del replay
+		w := runStream(t, controller, "5", []VideoEvent{{ID: 7, Type: TypeTask}, {ID: 8, Type: TypeTask, Status: "succeeded"}}, broker)
+
+		body := w.Body.String()
+		assert.Equal(t, 1, strings.Count(body, "id: 6\n"))
+		assert.Equal(t, 1, strings.Count(body, "id: 7\n"))
+		assert.Equal(t, 1, strings.Count(body, "id: 8\n"))
+		assert.Less(t, strings.Index(body, "id: 6\n"), strings.Index(body, "id: 8\n"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Stream_SendsLiveEventsCommittedOutOfOrder", func(t *testing.T) {
+		broker := &fakeBroker{ch: make(chan VideoEvent)}
+		repo := new(MockEventRepository)
+		controller := NewEventController(broker, repo)
+
+		repo.On("FindAfter", uint(1), uint64(5), replayLimit).Return([]VideoEvent{{ID: 8, Type: TypeTask}}, nil)
+
+		// El 7 confirmó después que el 8: no estaba en el replay y se debe entregar igual
+		w := runStream(t, controller, "5", []VideoEvent{{ID: 8, Type: TypeTask}, {ID: 7, Type: TypeTask}, {ID: 9, Type: TypeTask}}, broker)
+
+		body := w.Body.String()
+		assert.Equal(t, 1, strings.Count(body, "id: 7\n"))
+		assert.Equal(t, 1, strings.Count(body, "id: 8\n"))
+		assert.Equal(t, 1, strings.Count(body, "id: 9\n"))
+	})
+
+	t.Run("Stream_InvalidLastEventID", func(t *testing.T) {
+		controller := NewEventController(&fakeBroker{ch: make(chan VideoEvent)}, new(MockEventRepository))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/videos/events", nil)
+		c.Request.Header.Set("Last-Event-ID", "abc")
+		c.Set("userID", uint(1))
+
+		apperrtest.Handle(controller.Stream, c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_last_event_id", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("Stream_ReplayError", func(t *testing.T) {
+		repo := new(MockEventRepository)
+		repo.On("FindAfter", uint(1), uint64(5), replayLimit).Return([]VideoEvent(nil), errors.New("connection refused"))
+		controller := NewEventController(&fakeBroker{ch: make(chan VideoEvent)}, repo)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/videos/events", nil)
+		c.Request.Header.Set("Last-Event-ID", "5")
+		c.Set("userID", uint(1))
+
+		apperrtest.Handle(controller.Stream, c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_error", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("Stream_Unauthorized", func(t *testing.T) {
+		controller := NewEventController(&fakeBroker{ch: make(chan VideoEvent)}, new(MockEventRepository))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/videos/events", nil)
+
+		apperrtest.Handle(controller.Stream, c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "unauthenticated", apperrtest.ProblemCode(t, w))
+	})
+}
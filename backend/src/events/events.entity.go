@@ -0,0 +1,28 @@
+package events
+
+import "time"
+
+// Tipos de evento que recibe el stream de un usuario
+const (
+	// TypeTask es un cambio de estado o de avance de una tarea de procesamiento
+	TypeTask = "task"
+)
+
+// VideoEvent es un cambio en uno de los videos de un usuario. Se guarda para que un
+// cliente que se reconecta con Last-Event-ID reciba lo que se perdió.
+type VideoEvent struct {
+	ID        uint64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `json:"-" gorm:"index:idx_video_events_user_id_id,priority:1;not null"`
+	VideoID   uint      `json:"video_id" gorm:"not null"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Type      string    `json:"type" gorm:"not null"`
+	Status    string    `json:"status"`
+	Step      string    `json:"step,omitempty"`
+	Progress  float64   `json:"progress"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+func (VideoEvent) TableName() string {
+	return "video_events"
+}
@@ -0,0 +1,11 @@
+package events
+
+import "anb-app/src/apperr"
+
+// Errores del stream de eventos con código estable
+var (
+	ErrInvalidLastEventID = apperr.Validation("invalid_last_event_id", "Last-Event-ID must be a positive integer",
+		apperr.FieldError{Field: "last_event_id", Code: "number", Message: "last_event_id must be a positive integer"})
+
+	errUnauthenticated = apperr.Unauthorized("unauthenticated", "user not authenticated")
+)
@@ -0,0 +1,19 @@
+package events
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpEventRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodGet, Path: "/videos/events", Tags: []string{"videos"}, Auth: true,
+		Summary: "Stream SSE con los cambios de estado de mis videos",
+		Description: "Cada evento lleva id, el tipo en event y un VideoEvent en JSON en data. Al reconectar, " +
+			"el header Last-Event-ID (o last_event_id) reenvía los eventos perdidos.",
+		Query:       []openapi.Parameter{{Name: "last_event_id", Description: "Alternativa al header Last-Event-ID", Type: "integer"}},
+		ContentType: openapi.EventStream,
+		Errors:      []int{http.StatusBadRequest},
+	},
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Channel es el canal de LISTEN/NOTIFY por el que se anuncian los eventos
+const Channel = "video_events"
+
+type EventRepository interface {
+	FindAfter(userID uint, afterID uint64, limit int) ([]VideoEvent, error)
+	DeleteOlderThan(cutoff time.Time) (int64, error)
+}
+
+type eventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{
+		db: db,
+	}
+}
+
+// FindAfter devuelve los eventos del usuario posteriores a afterID, en orden
+func (r *eventRepository) FindAfter(userID uint, afterID uint64, limit int) ([]VideoEvent, error) {
+	var events []VideoEvent
+	result := r.db.Where("user_id = ? AND id > ?", userID, afterID).Order("id ASC").Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+func (r *eventRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&VideoEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// Publish guarda el evento y lo anuncia con pg_notify. Debe llamarse dentro de la
+// transacción del cambio que describe: Postgres solo entrega la notificación al
+// hacer commit, así que un rollback no deja eventos fantasma.
+func Publish(tx *gorm.DB, event *VideoEvent) error {
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to store video event: %w", err)
+	}
+	payload, err := json.Marshal(notification{VideoEvent: *event, UserID: event.UserID})
+	if err != nil {
+		return err
+	}
+	if err := tx.Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error; err != nil {
+		return fmt.Errorf("failed to notify video event: %w", err)
+	}
+	return nil
+}
+
+// notification es el payload de NOTIFY; incluye el usuario, que no se expone al cliente
+type notification struct {
+	VideoEvent
+	UserID uint `json:"user_id"`
+}
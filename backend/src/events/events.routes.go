@@ -0,0 +1,8 @@
+package events
+
+import "github.com/gin-gonic/gin"
+
+func SignUpEventRoutes(router *gin.RouterGroup, ec *EventController, authMiddleware gin.HandlerFunc) {
+
+	router.GET("/videos/events", authMiddleware, ec.Stream)
+}
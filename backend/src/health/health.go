@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultTimeout limita cada comprobación para que el probe del ALB no se cuelgue
+	DefaultTimeout = 2 * time.Second
+	// DefaultCacheTTL evita consultar las dependencias en cada probe
+	DefaultCacheTTL = 5 * time.Second
+
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc comprueba una dependencia; debe respetar la cancelación del contexto
+type CheckFunc func(ctx context.Context) error
+
+// Dependency es una dependencia externa que la instancia necesita para atender tráfico
+type Dependency struct {
+	Name  string
+	Check CheckFunc
+}
+
+// Result es el estado de una dependencia en la última comprobación
+type Result struct {
+	Status    string    `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report es la respuesta de /readyz
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker ejecuta las comprobaciones de readiness y guarda sus resultados durante
+// cacheTTL, de modo que varios probes seguidos no multipliquen las llamadas a AWS.
+type Checker struct {
+	dependencies []Dependency
+	timeout      time.Duration
+	cacheTTL     time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]Result
+}
+
+func NewChecker(timeout, cacheTTL time.Duration, dependencies ...Dependency) *Checker {
+	return &Checker{
+		dependencies: dependencies,
+		timeout:      timeout,
+		cacheTTL:     cacheTTL,
+		now:          time.Now,
+		cache:        make(map[string]Result),
+	}
+}
+
+// Readiness comprueba en paralelo las dependencias cuyo resultado haya expirado
+func (c *Checker) Readiness(ctx context.Context) Report {
+	// El lock se mantiene durante la comprobación: los probes concurrentes esperan
+	// el resultado en lugar de lanzar su propia ronda de llamadas
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Las dependencias vencidas se eligen antes de lanzar las goroutines, que escriben
+	// cada una en su posición de results; el cache solo se toca tras wg.Wait()
+	var stale []Dependency
+	for _, dep := range c.dependencies {
+		if cached, ok := c.cache[dep.Name]; ok && c.now().Sub(cached.CheckedAt) < c.cacheTTL {
+			continue
+		}
+		stale = append(stale, dep)
+	}
+
+	results := make([]Result, len(stale))
+	var wg sync.WaitGroup
+	for i, dep := range stale {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, dep)
+		}()
+	}
+	wg.Wait()
+	for i, dep := range stale {
+		c.cache[dep.Name] = results[i]
+	}
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.dependencies))}
+	for _, dep := range c.dependencies {
+		result := c.cache[dep.Name]
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+		report.Checks[dep.Name] = result
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, dep Dependency) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := c.now()
+	err := dep.Check(ctx)
+	result := Result{
+		Status:    StatusOK,
+		LatencyMs: float64(c.now().Sub(start).Microseconds()) / 1000,
+		CheckedAt: c.now(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LiveHandler responde a /livez: el proceso está vivo, sin mirar dependencias
+func (c *Checker) LiveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+	}
+}
+
+// ReadyHandler responde a /readyz con 200 si todas las dependencias responden y 503 si no
+func (c *Checker) ReadyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Readiness(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	}
+}
+
+// Postgres comprueba la conexión a la base de datos con un ping
+func Postgres(db *gorm.DB) Dependency {
+	return Dependency{
+		Name: "postgres",
+		Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
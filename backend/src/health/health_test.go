@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staticCheck(err error) CheckFunc {
+	return func(ctx context.Context) error { return err }
+}
+
+func countingCheck(calls *int, err error) CheckFunc {
+	return func(ctx context.Context) error {
+		*calls++
+		return err
+	}
+}
+
+func TestChecker(t *testing.T) {
+	t.Run("ReadyHandler_AllDependenciesUp", func(t *testing.T) {
+		checker := NewChecker(time.Second, time.Minute,
+			Dependency{Name: "postgres", Check: staticCheck(nil)},
+			Dependency{Name: "s3", Check: staticCheck(nil)},
+		)
+		w := httptest.NewRecorder()
+
+		checker.ReadyHandler()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var report Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+		assert.Equal(t, StatusOK, report.Checks["s3"].Status)
+	})
+
+	t.Run("ReadyHandler_DependencyDown", func(t *testing.T) {
+		checker := NewChecker(time.Second, time.Minute,
+			Dependency{Name: "postgres", Check: staticCheck(nil)},
+			Dependency{Name: "sqs", Check: staticCheck(errors.New("queue does not exist"))},
+		)
+		w := httptest.NewRecorder()
+
+		checker.ReadyHandler()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var report Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, StatusFail, report.Status)
+		assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+		assert.Equal(t, StatusFail, report.Checks["sqs"].Status)
+		assert.Equal(t, "queue does not exist", report.Checks["sqs"].Error)
+	})
+
+	t.Run("Readiness_CachesResults", func(t *testing.T) {
+		var calls int
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		checker := NewChecker(time.Second, 5*time.Second, Dependency{Name: "s3", Check: countingCheck(&calls, nil)})
+		checker.now = func() time.Time { return now }
+
+		checker.Readiness(context.Background())
+		checker.Readiness(context.Background())
+		assert.Equal(t, 1, calls)
+
+		now = now.Add(6 * time.Second)
+		checker.Readiness(context.Background())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Readiness_AppliesTimeout", func(t *testing.T) {
+		slow := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		checker := NewChecker(10*time.Millisecond, time.Minute, Dependency{Name: "postgres", Check: slow})
+
+		report := checker.Readiness(context.Background())
+
+		assert.Equal(t, StatusFail, report.Status)
+		assert.Contains(t, report.Checks["postgres"].Error, "deadline exceeded")
+	})
+
+	t.Run("LiveHandler_IgnoresDependencies", func(t *testing.T) {
+		checker := NewChecker(time.Second, time.Minute,
+			Dependency{Name: "postgres", Check: staticCheck(errors.New("down"))},
+		)
+		w := httptest.NewRecorder()
+
+		checker.LiveHandler()(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+}
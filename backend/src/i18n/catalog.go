@@ -0,0 +1,123 @@
+package i18n
+
+// catalog guarda los mensajes por clave e idioma.
+//
+//   - Las respuestas exitosas usan una clave por mensaje ("vote.created").
+//   - Las validaciones usan "validation.<tag de validator>" con dos argumentos: el campo y
+//     el parámetro del tag (los índices explícitos permiten no usar el segundo).
+//     "validation.default" es para los tags sin mensaje propio.
+//   - Los errores usan "error.<código estable>". Solo tienen traducción al español: el mensaje
+//     del error en el código ya está en inglés y es el que se muestra en ese idioma.
+var catalog = map[string]map[Lang]string{
+	// Respuestas
+	"video.uploaded": {
+		ES: "Video subido correctamente. Procesamiento en curso.",
+		EN: "Video uploaded successfully. Processing has started.",
+	},
+	"video.deleted": {
+		ES: "El video ha sido eliminado exitosamente.",
+		EN: "The video has been deleted successfully.",
+	},
+	"video.restored": {
+		ES: "El video ha sido restaurado.",
+		EN: "The video has been restored.",
+	},
+	"vote.created": {
+		ES: "Voto registrado correctamente.",
+		EN: "Vote successfully registered.",
+	},
+	"vote.deleted": {
+		ES: "Voto eliminado correctamente.",
+		EN: "Vote successfully deleted.",
+	},
+	"webhook.redelivery_scheduled": {
+		ES: "Reenvío programado.",
+		EN: "Delivery scheduled.",
+	},
+
+	// Validaciones de go-playground/validator
+	"validation.required": {
+		ES: "%[1]s es obligatorio",
+		EN: "%[1]s is required",
+	},
+	"validation.email": {
+		ES: "%[1]s debe ser un correo electrónico válido",
+		EN: "%[1]s must be a valid email address",
+	},
+	"validation.min": {
+		ES: "%[1]s debe tener al menos %[2]s caracteres",
+		EN: "%[1]s must be at least %[2]s characters long",
+	},
+	"validation.max": {
+		ES: "%[1]s debe tener como máximo %[2]s caracteres",
+		EN: "%[1]s must be at most %[2]s characters long",
+	},
+	"validation.eqfield": {
+		ES: "%[1]s debe coincidir con %[2]s",
+		EN: "%[1]s must match %[2]s",
+	},
+	"validation.oneof": {
+		ES: "%[1]s debe ser uno de: %[2]s",
+		EN: "%[1]s must be one of: %[2]s",
+	},
+	"validation.gt": {
+		ES: "%[1]s debe ser mayor que %[2]s",
+		EN: "%[1]s must be greater than %[2]s",
+	},
+	"validation.lte": {
+		ES: "%[1]s debe ser como máximo %[2]s",
+		EN: "%[1]s must be at most %[2]s",
+	},
+	"validation.number": {
+		ES: "%[1]s debe ser un entero positivo",
+		EN: "%[1]s must be a positive integer",
+	},
+	"validation.default": {
+		ES: "%[1]s no es válido",
+		EN: "%[1]s is invalid",
+	},
+
+	// Errores (problem+json)
+	"error.internal_error":         {ES: "Ocurrió un error inesperado"},
+	"error.invalid_body":           {ES: "El cuerpo de la petición no es válido o está mal formado"},
+	"error.validation_failed":      {ES: "Uno o más campos no son válidos"},
+	"error.missing_token":          {ES: "El header Authorization es obligatorio"},
+	"error.invalid_token":          {ES: "El token no es válido"},
+	"error.unauthenticated":        {ES: "El usuario no está autenticado"},
+	"error.invalid_credentials":    {ES: "Las credenciales no son válidas"},
+	"error.email_taken":            {ES: "El correo electrónico ya está registrado"},
+	"error.admin_required":         {ES: "Se requiere el rol de administrador"},
+	"error.origin_not_allowed":     {ES: "El origen no tiene permiso para llamar a la API"},
+	"error.already_voted":          {ES: "Ya votaste por este video"},
+	"error.vote_not_found":         {ES: "El voto no existe"},
+	"error.video_not_found":        {ES: "El video no existe"},
+	"error.video_forbidden":        {ES: "No tienes permiso sobre este video"},
+	"error.video_processing":       {ES: "No se puede eliminar un video mientras se procesa"},
+	"error.video_not_in_trash":     {ES: "El video no está en la papelera"},
+	"error.restore_window_expired": {ES: "Ya venció el plazo para restaurar el video"},
+	"error.video_not_processed":    {ES: "El video procesado todavía no está disponible"},
+	"error.original_forbidden":     {ES: "No tienes permiso para descargar el video original"},
+	"error.video_file_not_found":   {ES: "No se encontró el archivo del video"},
+	"error.video_file_required":    {ES: "El archivo de video es obligatorio"},
+	"error.invalid_video_id":       {ES: "video_id debe ser un entero positivo"},
+	"error.video_not_requeueable":  {ES: "El video no existe o ya se está procesando"},
+	"error.invalid_requeue_filter": {ES: "El filtro no es válido"},
+	"error.invalid_metadata":       {ES: "Los metadatos no son válidos"},
+	"error.invalid_query":          {ES: "La búsqueda no es válida"},
+	"error.invalid_variant":        {ES: "variant debe ser original o processed"},
+	"error.task_not_found":         {ES: "La tarea no existe o no pertenece al usuario"},
+	"error.invalid_last_event_id":  {ES: "Last-Event-ID debe ser un entero positivo"},
+	"error.invalid_endpoint_url":   {ES: "La URL del endpoint no es válida"},
+	"error.invalid_event_type":     {ES: "El tipo de evento no es válido"},
+	"error.invalid_endpoint_id":    {ES: "endpoint_id debe ser un entero positivo"},
+	"error.invalid_delivery_id":    {ES: "delivery_id debe ser un entero positivo"},
+	"error.endpoint_not_found":     {ES: "El endpoint no existe"},
+	"error.delivery_not_found":     {ES: "La entrega no existe"},
+	"error.endpoint_inactive":      {ES: "El endpoint de esta entrega ya no está activo"},
+	"error.invalid_limit":          {ES: "limit debe ser un entero positivo"},
+	"error.invalid_cursor":         {ES: "El cursor no corresponde a esta consulta"},
+	"error.invalid_sort":           {ES: "El orden no es válido"},
+	"error.invalid_user_id":        {ES: "user_id debe ser un número"},
+	"error.invalid_from":           {ES: "from debe ser una fecha (AAAA-MM-DD) o una hora RFC 3339"},
+	"error.invalid_to":             {ES: "to debe ser una fecha (AAAA-MM-DD) o una hora RFC 3339 posterior a from"},
+}
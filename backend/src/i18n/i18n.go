@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Lang es un idioma soportado por el catálogo
+type Lang string
+
+const (
+	ES Lang = "es"
+	EN Lang = "en"
+)
+
+// Default es el idioma de las peticiones sin Accept-Language o sin ningún idioma soportado
+const Default = ES
+
+const contextKey = "lang"
+
+// El primer idioma es el que elige el matcher cuando no hay coincidencia
+var (
+	supported = []Lang{ES, EN}
+	matcher   = language.NewMatcher([]language.Tag{language.Spanish, language.English})
+)
+
+// Parse elige el idioma de un header Accept-Language respetando los pesos q:
+// "en-US,en;q=0.9" es EN, "es-CO" es ES y "fr" cae en el idioma por defecto
+func Parse(acceptLanguage string) Lang {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// Middleware resuelve el idioma de la petición una sola vez y lo anuncia en la respuesta
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := Parse(c.GetHeader("Accept-Language"))
+		c.Set(contextKey, lang)
+		c.Header("Content-Language", string(lang))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// FromContext devuelve el idioma resuelto por el middleware; sin middleware lo lee del header
+func FromContext(c *gin.Context) Lang {
+	if lang, ok := c.Get(contextKey); ok {
+		return lang.(Lang)
+	}
+	if c.Request == nil {
+		return Default
+	}
+	return Parse(c.GetHeader("Accept-Language"))
+}
+
+// Lookup busca el mensaje en el idioma pedido, sin recurrir a otro idioma
+func Lookup(lang Lang, key string, args ...any) (string, bool) {
+	message, ok := catalog[key][lang]
+	if !ok {
+		return "", false
+	}
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return message, true
+}
+
+// T traduce un mensaje; si falta en ese idioma usa el idioma por defecto y, si tampoco
+// está, devuelve la clave para que el faltante se note
+func T(lang Lang, key string, args ...any) string {
+	if message, ok := Lookup(lang, key, args...); ok {
+		return message
+	}
+	if message, ok := Lookup(Default, key, args...); ok {
+		return message
+	}
+	return key
+}
+
+// Message traduce un mensaje al idioma de la petición
+func Message(c *gin.Context, key string, args ...any) string {
+	return T(FromContext(c), key, args...)
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]Lang{
+		"":                        Default,
+		"es-CO":                   ES,
+		"en":                      EN,
+		"en-US,en;q=0.9":          EN,
+		"es;q=0.4,en-GB;q=0.8":    EN,
+		"fr-FR,fr;q=0.9,es;q=0.5": ES,
+		"de":                      Default,
+		"not a language;;":        Default,
+	}
+	for header, expected := range cases {
+		assert.Equal(t, expected, Parse(header), header)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Vote successfully registered.", T(EN, "vote.created"))
+	assert.Equal(t, "password2 debe coincidir con password", T(ES, "validation.eqfield", "password2", "password"))
+	// Los índices explícitos permiten que el mensaje no use el parámetro
+	assert.Equal(t, "email is required", T(EN, "validation.required", "email", ""))
+	// Los errores solo tienen español; T recurre al idioma por defecto y Lookup no
+	assert.Equal(t, "El video no existe", T(EN, "error.video_not_found"))
+	_, ok := Lookup(EN, "error.video_not_found")
+	assert.False(t, ok)
+	assert.Equal(t, "missing.key", T(ES, "missing.key"))
+}
+
+func TestCatalogHasBothLanguagesForMessages(t *testing.T) {
+	for key, translations := range catalog {
+		if _, ok := translations[Default]; !ok {
+			t.Errorf("%s has no %s translation", key, Default)
+		}
+		if _, ok := translations[EN]; !ok && !strings.HasPrefix(key, "error.") {
+			t.Errorf("%s has no %s translation", key, EN)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, Message(c, "video.restored"))
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "The video has been restored.", w.Body.String())
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+
+	// Sin middleware el idioma se lee del header en cada llamada
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	assert.Equal(t, Default, FromContext(c))
+}
@@ -0,0 +1,38 @@
+package media
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CookieIssuer emite las cookies que dan acceso a los archivos públicos en el CDN
+type CookieIssuer interface {
+	Cookies() ([]*http.Cookie, time.Time, error)
+}
+
+type MediaController struct {
+	issuer CookieIssuer
+}
+
+func NewMediaController(issuer CookieIssuer) *MediaController {
+	return &MediaController{issuer: issuer}
+}
+
+// CreateSession entrega las cookies de acceso al CDN. No requiere sesión: los archivos
+// procesados son públicos, las cookies solo evitan que se enlacen desde fuera sin pasar por la API.
+func (mc *MediaController) CreateSession(c *gin.Context) {
+	cookies, expiresAt, err := mc.issuer.Cookies()
+	if err != nil {
+		c.Error(fmt.Errorf("issuing media cookies: %w", err))
+		return
+	}
+
+	for _, cookie := range cookies {
+		http.SetCookie(c.Writer, cookie)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, MediaSessionResponse{ExpiresAt: expiresAt})
+}
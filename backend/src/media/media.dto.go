@@ -0,0 +1,7 @@
+package media
+
+import "time"
+
+type MediaSessionResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
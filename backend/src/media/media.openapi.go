@@ -0,0 +1,15 @@
+package media
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpMediaRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/public/media/session", Tags: []string{"public"},
+		Summary:  "Cookies de acceso a los archivos públicos en el CDN",
+		Response: MediaSessionResponse{},
+	},
+}
@@ -0,0 +1,22 @@
+package media
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SignUpMediaRoutes(router *gin.RouterGroup, mc *MediaController) {
+
+	publicRoutes := router.Group("/public/media")
+	{
+		publicRoutes.POST("/session", mc.CreateSession)
+	}
+}
+
+// MountServer sirve los archivos públicos bajo path (p. ej. /media) con el servidor local
+func MountServer(router *gin.Engine, path string, server *Server) {
+	handler := gin.WrapH(http.StripPrefix(path, server))
+	router.GET(path+"/*key", handler)
+	router.HEAD(path+"/*key", handler)
+}
@@ -0,0 +1,84 @@
+package media
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"anb-app/src/storage"
+)
+
+// Server es el sustituto local del CDN: sirve los archivos públicos de un
+// LocalStorageService verificando la firma de la ruta o la cookie de acceso.
+// Soporta Range y peticiones condicionales como lo haría el borde.
+type Server struct {
+	store  *storage.LocalStorageService
+	secret []byte
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func NewServer(store *storage.LocalStorageService, secret []byte) (*Server, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("media signing secret must be at least 32 bytes")
+	}
+	return &Server{store: store, secret: secret, maxAge: time.Hour, now: time.Now}, nil
+}
+
+// ServeHTTP espera la ruta sin el prefijo de montaje: /s/{expira}/{firma}/{clave} o /{clave}
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, err := s.authorize(r)
+	if err != nil {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	// Los originales y cualquier otra clave nunca se entregan por aquí
+	if !strings.HasPrefix(key, PublicPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := s.store.Open(key)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(s.maxAge.Seconds())))
+	// Cookie y URL llevan su propia autorización; la respuesta no depende del usuario
+	http.ServeContent(w, r, key, info.ModTime(), file)
+}
+
+func (s *Server) authorize(r *http.Request) (string, error) {
+	if strings.HasPrefix(r.URL.Path, "/s/") {
+		return VerifyPath(s.secret, r.URL.Path, s.now())
+	}
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return "", err
+	}
+	if err := VerifyCookie(s.secret, cookie.Value, s.now()); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(r.URL.Path, "/"), nil
+}
@@ -0,0 +1,132 @@
+package media
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"anb-app/src/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer monta el servidor local sobre un directorio temporal con un video
+// procesado y un original
+func newTestServer(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorageService(dir)
+	require.NoError(t, err)
+	for key, content := range map[string]string{
+		"processed/1.mp4": "0123456789",
+		"originals/1.mp4": "original",
+	} {
+		path, _ := store.Path(key)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	server, err := NewServer(store, testSecret)
+	require.NoError(t, err)
+	router := gin.New()
+	MountServer(router, "/media", server)
+	return router
+}
+
+func signedPath(t *testing.T, key string) string {
+	t.Helper()
+	signer, err := NewPathSigner("http://localhost/media", testSecret, 24*time.Hour, time.Hour)
+	require.NoError(t, err)
+	signed, _ := signer.Sign(key)
+	parsed, _ := url.Parse(signed.URL)
+	return parsed.Path
+}
+
+func TestServer(t *testing.T) {
+	router := newTestServer(t)
+
+	t.Run("SignedPath", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", signedPath(t, "processed/1.mp4"), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0123456789", w.Body.String())
+		assert.Contains(t, w.Header().Get("Cache-Control"), "public")
+	})
+
+	t.Run("SignedPathRange", func(t *testing.T) {
+		req := httptest.NewRequest("GET", signedPath(t, "processed/1.mp4"), nil)
+		req.Header.Set("Range", "bytes=2-4")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "234", w.Body.String())
+	})
+
+	t.Run("TamperedSignature", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", signedPath(t, "processed/1.mp4")+"x", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("UnsignedWithoutCookie", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/media/processed/1.mp4", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("StableURLWithCookie", func(t *testing.T) {
+		signer, _ := NewCookieSigner("http://localhost/media", testSecret, "", 24*time.Hour, time.Hour)
+		cookies, _, _ := signer.Cookies()
+		req := httptest.NewRequest("GET", "/media/processed/1.mp4", nil)
+		req.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0123456789", w.Body.String())
+	})
+
+	t.Run("OriginalsAreNeverServed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", signedPath(t, "originals/1.mp4"), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", signedPath(t, "processed/2.mp4"), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestMediaController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer, err := NewCookieSigner("https://media.anb.com", testSecret, ".anb.com", 24*time.Hour, time.Hour)
+	require.NoError(t, err)
+	controller := NewMediaController(signer)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/public/media/session", nil)
+
+	controller.CreateSession(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Set-Cookie"), CookieName+"=")
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "expires_at")
+}
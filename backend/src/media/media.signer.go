@@ -0,0 +1,170 @@
+package media
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"anb-app/src/storage"
+)
+
+const (
+	// PublicPrefix agrupa las claves de los archivos procesados, que son públicos
+	PublicPrefix = "processed/"
+	// CookieName es la cookie de acceso que verifica el servidor de media
+	CookieName = "anb_media"
+)
+
+// PathSigner entrega URLs firmadas de larga duración con la firma en la ruta:
+// {base}/s/{expira}/{firma}/{clave}. El vencimiento se alinea a rotation, así que todas
+// las instancias generan la misma URL durante cada periodo y el CDN la puede cachear.
+type PathSigner struct {
+	baseURL  string
+	secret   []byte
+	ttl      time.Duration
+	rotation time.Duration
+	now      func() time.Time
+}
+
+// NewPathSigner exige rotation menor que ttl: cada URL vale al menos ttl - rotation
+func NewPathSigner(baseURL string, secret []byte, ttl, rotation time.Duration) (*PathSigner, error) {
+	if err := validate(baseURL, secret, ttl, rotation); err != nil {
+		return nil, err
+	}
+	return &PathSigner{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		secret:   secret,
+		ttl:      ttl,
+		rotation: rotation,
+		now:      time.Now,
+	}, nil
+}
+
+func validate(baseURL string, secret []byte, ttl, rotation time.Duration) error {
+	if baseURL == "" {
+		return errors.New("media delivery requires a base URL")
+	}
+	if len(secret) < 32 {
+		return errors.New("media signing secret must be at least 32 bytes")
+	}
+	if rotation <= 0 || rotation >= ttl {
+		return fmt.Errorf("media URL rotation (%s) must be positive and shorter than the TTL (%s)", rotation, ttl)
+	}
+	return nil
+}
+
+func (s *PathSigner) Sign(key string) (storage.SignedURL, error) {
+	expiresAt := s.now().Truncate(s.rotation).Add(s.ttl)
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	signature := sign(s.secret, "path", exp, key)
+	return storage.SignedURL{
+		URL:       s.baseURL + "/s/" + exp + "/" + signature + escapeKey(key),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyPath valida una ruta /s/{expira}/{firma}/{clave} y devuelve la clave
+func VerifyPath(secret []byte, path string, now time.Time) (string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/s/"), "/", 3)
+	if !strings.HasPrefix(path, "/s/") || len(parts) != 3 || parts[2] == "" {
+		return "", errors.New("invalid signed path")
+	}
+	exp, signature, key := parts[0], parts[1], parts[2]
+	if err := checkExpiry(exp, now); err != nil {
+		return "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, "path", exp, key))) {
+		return "", errors.New("invalid signature")
+	}
+	return key, nil
+}
+
+// CookieSigner entrega URLs estables {base}/{clave}, sin firma. El acceso a todo
+// PublicPrefix lo da la cookie CookieName, que emite la API y verifica el servidor de media.
+type CookieSigner struct {
+	baseURL      string
+	secret       []byte
+	cookieDomain string
+	ttl          time.Duration
+	rotation     time.Duration
+	now          func() time.Time
+}
+
+func NewCookieSigner(baseURL string, secret []byte, cookieDomain string, ttl, rotation time.Duration) (*CookieSigner, error) {
+	if err := validate(baseURL, secret, ttl, rotation); err != nil {
+		return nil, err
+	}
+	return &CookieSigner{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		secret:       secret,
+		cookieDomain: cookieDomain,
+		ttl:          ttl,
+		rotation:     rotation,
+		now:          time.Now,
+	}, nil
+}
+
+func (s *CookieSigner) Sign(key string) (storage.SignedURL, error) {
+	return storage.SignedURL{URL: s.baseURL + escapeKey(key), ExpiresAt: s.now().Add(s.ttl)}, nil
+}
+
+// Cookies devuelve la cookie de acceso; su valor es {expira}.{firma}
+func (s *CookieSigner) Cookies() ([]*http.Cookie, time.Time, error) {
+	expiresAt := s.now().Truncate(s.rotation).Add(s.ttl)
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return []*http.Cookie{{
+		Name:     CookieName,
+		Value:    exp + "." + sign(s.secret, "cookie", exp, PublicPrefix),
+		Domain:   s.cookieDomain,
+		Path:     "/",
+		Expires:  expiresAt,
+		Secure:   strings.HasPrefix(s.baseURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}}, expiresAt, nil
+}
+
+// VerifyCookie valida el valor de la cookie de acceso
+func VerifyCookie(secret []byte, value string, now time.Time) error {
+	exp, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return errors.New("invalid cookie")
+	}
+	if err := checkExpiry(exp, now); err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, "cookie", exp, PublicPrefix))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func checkExpiry(exp string, now time.Time) error {
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return errors.New("invalid expiry")
+	}
+	if !now.Before(time.Unix(unix, 0)) {
+		return errors.New("signature expired")
+	}
+	return nil
+}
+
+// sign es HMAC-SHA256 sobre tipo, vencimiento y recurso; el tipo impide usar la firma
+// de una URL como cookie o al revés
+func sign(secret []byte, kind, exp, resource string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(kind + "\n" + exp + "\n" + resource))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func escapeKey(key string) string {
+	return (&url.URL{Path: "/" + key}).EscapedPath()
+}
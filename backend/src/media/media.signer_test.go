@@ -0,0 +1,105 @@
+package media
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestPathSigner(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	signer, err := NewPathSigner("https://media.anb.com/", testSecret, 7*24*time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	signer.now = func() time.Time { return now }
+
+	first, err := signer.Sign("processed/clip 1.mp4")
+	require.NoError(t, err)
+
+	t.Run("StableWithinRotation", func(t *testing.T) {
+		signer.now = func() time.Time { return now.Add(10 * time.Hour) }
+		second, _ := signer.Sign("processed/clip 1.mp4")
+
+		assert.Equal(t, first.URL, second.URL)
+		assert.Equal(t, time.Date(2025, 3, 21, 0, 0, 0, 0, time.UTC), first.ExpiresAt)
+	})
+
+	t.Run("RotatesAfterPeriod", func(t *testing.T) {
+		signer.now = func() time.Time { return now.Add(24 * time.Hour) }
+		next, _ := signer.Sign("processed/clip 1.mp4")
+
+		assert.NotEqual(t, first.URL, next.URL)
+	})
+
+	t.Run("VerifyRoundTrip", func(t *testing.T) {
+		parsed, err := url.Parse(first.URL)
+		require.NoError(t, err)
+
+		key, err := VerifyPath(testSecret, parsed.Path, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "processed/clip 1.mp4", key)
+	})
+
+	t.Run("RejectsOtherKey", func(t *testing.T) {
+		parsed, _ := url.Parse(first.URL)
+		tampered := strings.Replace(parsed.Path, "clip 1", "clip 2", 1)
+
+		_, err := VerifyPath(testSecret, tampered, now)
+
+		assert.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("RejectsExpired", func(t *testing.T) {
+		parsed, _ := url.Parse(first.URL)
+
+		_, err := VerifyPath(testSecret, parsed.Path, first.ExpiresAt)
+
+		assert.ErrorContains(t, err, "expired")
+	})
+}
+
+func TestNewPathSignerValidation(t *testing.T) {
+	_, err := NewPathSigner("https://media.anb.com", []byte("short"), time.Hour, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewPathSigner("https://media.anb.com", testSecret, time.Hour, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewPathSigner("", testSecret, time.Hour, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestCookieSigner(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	signer, err := NewCookieSigner("https://media.anb.com", testSecret, ".anb.com", 24*time.Hour, time.Hour)
+	require.NoError(t, err)
+	signer.now = func() time.Time { return now }
+
+	signed, _ := signer.Sign("processed/1_thumb.jpg")
+	assert.Equal(t, "https://media.anb.com/processed/1_thumb.jpg", signed.URL)
+
+	cookies, expiresAt, err := signer.Cookies()
+	require.NoError(t, err)
+	require.Len(t, cookies, 1)
+	assert.Equal(t, CookieName, cookies[0].Name)
+	assert.Equal(t, ".anb.com", cookies[0].Domain)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, now.Add(24*time.Hour), expiresAt)
+
+	assert.NoError(t, VerifyCookie(testSecret, cookies[0].Value, now))
+	assert.Error(t, VerifyCookie(testSecret, cookies[0].Value, expiresAt))
+	assert.Error(t, VerifyCookie([]byte("another-secret-another-secret-xx"), cookies[0].Value, now))
+
+	// Una firma de ruta no sirve como cookie
+	pathSigner, _ := NewPathSigner("https://media.anb.com", testSecret, 24*time.Hour, time.Hour)
+	pathSigner.now = signer.now
+	pathURL, _ := pathSigner.Sign(PublicPrefix)
+	parts := strings.Split(strings.TrimPrefix(pathURL.URL, "https://media.anb.com/s/"), "/")
+	assert.Error(t, VerifyCookie(testSecret, parts[0]+"."+parts[1], now))
+}
@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"anb-app/src/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameter es un parámetro de query de una operación
+type Parameter struct {
+	Name        string
+	Description string
+	// Type es string (por defecto), integer o boolean
+	Type     string
+	Required bool
+	Enum     []string
+}
+
+// EventStream es el ContentType de las rutas que responden un stream SSE
+const EventStream = "text/event-stream"
+
+// Operation documenta una ruta registrada en gin. Path es la ruta relativa al grupo con
+// la sintaxis de gin (/videos/:video_id); Request, Form y Response son valores de los DTO.
+type Operation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	// Auth indica que la ruta exige el token Bearer
+	Auth       bool
+	Deprecated bool
+	// PathParams describe los parámetros de ruta; los que terminan en _id y no están aquí
+	// son enteros positivos y el resto texto
+	PathParams []Parameter
+	Query      []Parameter
+	// Request es el cuerpo JSON; Form es un cuerpo multipart/form-data con los archivos en Files
+	Request any
+	Form    any
+	Files   []string
+	// Status es el código de éxito (200 por defecto). Response es su cuerpo JSON; con
+	// ContentType la respuesta es un archivo de ese tipo o, con EventStream, un stream SSE.
+	Status      int
+	Response    any
+	ContentType string
+	// Responses documenta cuerpos JSON de otros códigos (p. ej. un 500 con resultado parcial)
+	Responses map[int]any
+	// Errors son los códigos que responden problem+json; 401 y 500 se agregan solos
+	Errors []int
+}
+
+// Spec junta las operaciones que documentan los paquetes de rutas
+type Spec struct {
+	info       Info
+	operations map[string]Operation
+	doc        *Document
+}
+
+func New(title, version string) *Spec {
+	return &Spec{info: Info{Title: title, Version: version}, operations: map[string]Operation{}}
+}
+
+// Add registra las operaciones de un grupo; prefix es la ruta del grupo (/api/v1)
+func (s *Spec) Add(prefix string, operations ...Operation) {
+	for _, operation := range operations {
+		operation.Path = path.Join(prefix, operation.Path)
+		s.operations[routeKey(operation.Method, operation.Path)] = operation
+	}
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// Build genera el documento a partir de las rutas registradas en gin bajo prefix. Una ruta
+// sin Operation o una Operation sin ruta en gin es un error, para que el documento no se
+// desvíe del router.
+func (s *Spec) Build(routes gin.RoutesInfo, prefix string) (*Document, error) {
+	registry := newSchemaRegistry()
+	problem := registry.schemaFor(reflect.TypeOf(apperr.Problem{}), false)
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    s.info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: registry.schemas,
+			Responses: map[string]*Response{
+				"Problem": {
+					Description: "Error con el formato RFC 7807",
+					Content:     map[string]MediaType{apperr.ContentType: {Schema: problem}},
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		routes: map[string]*OperationObject{},
+	}
+
+	var problems []string
+	registered := map[string]bool{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix) {
+			continue
+		}
+		key := routeKey(route.Method, route.Path)
+		registered[key] = true
+		operation, ok := s.operations[key]
+		if !ok {
+			problems = append(problems, key+" is registered but not documented")
+			continue
+		}
+
+		object := operationObject(registry, operation)
+		doc.routes[key] = object
+		openAPIPath := ginPath.ReplaceAllString(route.Path, "{$1}")
+		if doc.Paths[openAPIPath] == nil {
+			doc.Paths[openAPIPath] = PathItem{}
+		}
+		doc.Paths[openAPIPath][strings.ToLower(route.Method)] = object
+	}
+
+	for key := range s.operations {
+		if !registered[key] {
+			problems = append(problems, key+" is documented but not registered in the router")
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return nil, fmt.Errorf("openapi: %s", strings.Join(problems, "; "))
+	}
+	s.doc = doc
+	return doc, nil
+}
+
+// ValidationMiddleware valida contra el documento del último Build. Se registra antes que las
+// rutas, cuando el documento todavía no existe; hasta el Build deja pasar todo.
+func (s *Spec) ValidationMiddleware(report func(c *gin.Context, err error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.doc == nil {
+			c.Next()
+			return
+		}
+		ValidationMiddleware(s.doc, report)(c)
+	}
+}
+
+var ginPath = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func operationObject(registry *schemaRegistry, operation Operation) *OperationObject {
+	object := &OperationObject{
+		OperationID: operationID(operation.Method, operation.Path),
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range ginPath.FindAllStringSubmatch(operation.Path, -1) {
+		param := ParameterObject{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if i := slices.IndexFunc(operation.PathParams, func(p Parameter) bool { return p.Name == param.Name }); i >= 0 {
+			param.Description = operation.PathParams[i].Description
+			param.Schema = &Schema{Type: cmp.Or(operation.PathParams[i].Type, "string"), Enum: operation.PathParams[i].Enum}
+		} else if strings.HasSuffix(param.Name, "_id") {
+			param.Schema = &Schema{Type: "integer", Minimum: ptr(1.0)}
+		}
+		object.Parameters = append(object.Parameters, param)
+	}
+	for _, param := range operation.Query {
+		object.Parameters = append(object.Parameters, ParameterObject{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: cmp.Or(param.Type, "string"), Enum: param.Enum},
+		})
+	}
+
+	switch {
+	case operation.Request != nil:
+		object.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: registry.schemaFor(reflect.TypeOf(operation.Request), true)},
+		}}
+	case operation.Form != nil:
+		form := registry.objectSchema(reflect.TypeOf(operation.Form), true, "form")
+		for _, file := range operation.Files {
+			form.Properties[file] = &Schema{Type: "string", Format: "binary"}
+			form.Required = append(form.Required, file)
+		}
+		object.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{"multipart/form-data": {Schema: form}}}
+	}
+
+	status := cmp.Or(operation.Status, http.StatusOK)
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case operation.ContentType == EventStream:
+		success.Content = map[string]MediaType{EventStream: {Schema: &Schema{Type: "string"}}}
+	case operation.ContentType != "":
+		// Los archivos admiten Range y peticiones condicionales
+		success.Content = map[string]MediaType{operation.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+		object.Responses[strconv.Itoa(http.StatusPartialContent)] = &Response{Description: "Partial Content", Content: success.Content}
+		object.Responses[strconv.Itoa(http.StatusNotModified)] = &Response{Description: "Not Modified"}
+		object.Responses[strconv.Itoa(http.StatusRequestedRangeNotSatisfiable)] = &Response{Description: "Range Not Satisfiable"}
+	case operation.Response != nil:
+		success.Content = jsonContent(registry, operation.Response)
+	}
+	object.Responses[strconv.Itoa(status)] = success
+	for code, body := range operation.Responses {
+		object.Responses[strconv.Itoa(code)] = &Response{Description: http.StatusText(code), Content: jsonContent(registry, body)}
+	}
+
+	errors := slices.Clone(operation.Errors)
+	if operation.Auth {
+		object.Security = []map[string][]string{{"bearerAuth": {}}}
+		errors = append(errors, http.StatusUnauthorized)
+	}
+	errors = append(errors, http.StatusInternalServerError)
+	for _, code := range errors {
+		if object.Responses[strconv.Itoa(code)] == nil {
+			object.Responses[strconv.Itoa(code)] = &Response{Ref: "#/components/responses/Problem"}
+		}
+	}
+	return object
+}
+
+func jsonContent(registry *schemaRegistry, body any) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: registry.schemaFor(reflect.TypeOf(body), false)}}
+}
+
+// operationID arma un identificador estable: GET /api/v1/videos/:video_id es get_api_v1_videos_video_id
+func operationID(method, routePath string) string {
+	id := strings.ToLower(method) + "_" + strings.Trim(ginPath.ReplaceAllString(routePath, "$1"), "/")
+	return strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(id)
+}
+
+// Handler sirve el documento generado
+func Handler(doc *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
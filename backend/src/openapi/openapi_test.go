@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type item struct {
+	base
+	Title  string     `json:"title"`
+	Note   string     `json:"note,omitempty"`
+	Tags   []string   `json:"tags"`
+	Parent *item      `json:"parent,omitempty"`
+	SeenAt *time.Time `json:"seen_at"`
+	secret string
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type createItem struct {
+	Title    string `json:"title" validate:"required,min=3,max=100"`
+	Category string `json:"category" validate:"omitempty,oneof=dunk three_pointer"`
+	Email    string `json:"email" validate:"required,email"`
+	Score    int    `json:"score" validate:"gt=0,lte=10"`
+}
+
+type uploadItem struct {
+	Title string `form:"title" validate:"required"`
+	Day   string `form:"day" time_format:"2006-01-02"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	registry := newSchemaRegistry()
+
+	ref := registry.schemaFor(reflect.TypeOf(page[item]{}), false)
+	assert.Equal(t, "#/components/schemas/pageitem", ref.Ref)
+
+	schema := registry.schemas["item"]
+	require.NotNil(t, schema)
+	// El struct embebido se aplana y los campos sin exportar no aparecen
+	assert.ElementsMatch(t, []string{"id", "created_at", "title", "note", "tags", "parent", "seen_at"}, keys(schema.Properties))
+	assert.ElementsMatch(t, []string{"id", "created_at", "title", "tags", "seen_at"}, schema.Required)
+	assert.Equal(t, "date-time", schema.Properties["created_at"].Format)
+	assert.True(t, schema.Properties["seen_at"].Nullable)
+	assert.True(t, schema.Properties["tags"].Nullable)
+	assert.Equal(t, []*Schema{{Ref: "#/components/schemas/item"}}, schema.Properties["parent"].AllOf)
+
+	request := registry.schemas[registry.register(reflect.TypeOf(createItem{}), true)]
+	assert.ElementsMatch(t, []string{"title", "email"}, request.Required)
+	assert.Equal(t, 3, *request.Properties["title"].MinLength)
+	assert.Equal(t, 100, *request.Properties["title"].MaxLength)
+	assert.Equal(t, []string{"dunk", "three_pointer"}, request.Properties["category"].Enum)
+	assert.Equal(t, "email", request.Properties["email"].Format)
+	assert.True(t, request.Properties["score"].ExclusiveMinimum)
+	assert.Equal(t, 10.0, *request.Properties["score"].Maximum)
+
+	form := registry.objectSchema(reflect.TypeOf(uploadItem{}), true, "form")
+	assert.Equal(t, []string{"title"}, form.Required)
+	assert.Equal(t, "date", form.Properties["day"].Format)
+}
+
+func keys(properties map[string]*Schema) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	return names
+}
+
+var testOperations = []Operation{
+	{Method: http.MethodGet, Path: "/items/:item_id", Response: item{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/items", Auth: true, Request: createItem{}, Status: http.StatusCreated, Response: item{}},
+}
+
+// newRouter registra las rutas de prueba con el middleware y guarda lo que reporta
+func newRouter(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *[]error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	spec := New("Test", "1")
+	spec.Add("/api/v1", testOperations...)
+
+	var reports []error
+	router := gin.New()
+	router.Use(spec.ValidationMiddleware(func(c *gin.Context, err error) { reports = append(reports, err) }))
+	api := router.Group("/api/v1")
+	api.GET("/items/:item_id", handler)
+	api.POST("/items", handler)
+	// Fuera del prefijo del documento
+	router.GET("/media/*key", handler)
+
+	_, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+	return router, &reports
+}
+
+func TestBuild(t *testing.T) {
+	router, _ := newRouter(t, func(c *gin.Context) {})
+	spec := New("Test", "1")
+	spec.Add("/api/v1", testOperations...)
+	doc, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+
+	get := doc.Paths["/api/v1/items/{item_id}"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "get_api_v1_items_item_id", get.OperationID)
+	assert.Equal(t, "integer", get.Parameters[0].Schema.Type)
+	assert.Equal(t, "#/components/responses/Problem", get.Responses["500"].Ref)
+	assert.NotContains(t, get.Responses, "401")
+	assert.NotContains(t, doc.Paths, "/media/{key}")
+
+	undocumented := New("Test", "1")
+	undocumented.Add("/api/v1", testOperations[0])
+	_, err = undocumented.Build(router.Routes(), "/api/")
+	assert.ErrorContains(t, err, "POST /api/v1/items is registered but not documented")
+
+	missing := New("Test", "1")
+	missing.Add("/api/v1", append(slices.Clone(testOperations), Operation{Method: http.MethodDelete, Path: "/items/:item_id"})...)
+	_, err = missing.Build(router.Routes(), "/api/")
+	assert.ErrorContains(t, err, "DELETE /api/v1/items/:item_id is documented but not registered")
+}
+
+func TestBuildPathParamsAndStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/tasks/:task_id", func(c *gin.Context) {})
+	router.GET("/api/v1/events", func(c *gin.Context) {})
+
+	spec := New("Test", "1")
+	spec.Add("/api/v1",
+		Operation{Method: http.MethodGet, Path: "/tasks/:task_id", PathParams: []Parameter{{Name: "task_id", Description: "ID hexadecimal"}}, Response: item{}},
+		Operation{Method: http.MethodGet, Path: "/events", ContentType: EventStream},
+	)
+	doc, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+
+	task := doc.Paths["/api/v1/tasks/{task_id}"]["get"]
+	assert.Equal(t, "string", task.Parameters[0].Schema.Type)
+	assert.Equal(t, "ID hexadecimal", task.Parameters[0].Description)
+
+	// Un stream SSE no admite Range ni peticiones condicionales
+	events := doc.Paths["/api/v1/events"]["get"]
+	assert.Contains(t, events.Responses["200"].Content, EventStream)
+	assert.NotContains(t, events.Responses, "206")
+	assert.NotContains(t, events.Responses, "304")
+}
+
+func TestHandler(t *testing.T) {
+	router, _ := newRouter(t, func(c *gin.Context) {})
+	spec := New("Test", "1")
+	spec.Add("/api/v1", testOperations...)
+	doc, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+	router.GET("/api/openapi.json", Handler(doc))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "3.0.3", body["openapi"])
+	assert.Contains(t, body["paths"], "/api/v1/items/{item_id}")
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	validItem := gin.H{"id": 1, "created_at": "2025-03-14T18:30:00Z", "title": "Mate", "tags": nil, "seen_at": nil}
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		status  int
+		respond any
+		report  string
+	}{
+		{name: "Valid", method: http.MethodGet, path: "/api/v1/items/1", status: http.StatusOK, respond: validItem},
+		{name: "RouteOutsideDocument", method: http.MethodGet, path: "/media/a.mp4", status: http.StatusTeapot},
+		{
+			name: "UndocumentedProperty", method: http.MethodGet, path: "/api/v1/items/1", status: http.StatusOK,
+			respond: gin.H{"id": 1, "created_at": "2025-03-14T18:30:00Z", "title": "Mate", "tags": nil, "seen_at": nil, "email": "a@b.co"},
+			report:  "$.email is not documented",
+		},
+		{
+			name: "MissingRequiredProperty", method: http.MethodGet, path: "/api/v1/items/1", status: http.StatusOK,
+			respond: gin.H{"id": 1, "created_at": "2025-03-14T18:30:00Z", "tags": nil, "seen_at": nil},
+			report:  "$.title is required",
+		},
+		{
+			name: "WrongType", method: http.MethodGet, path: "/api/v1/items/1", status: http.StatusOK,
+			respond: gin.H{"id": "1", "created_at": "2025-03-14T18:30:00Z", "title": "Mate", "tags": nil, "seen_at": nil},
+			report:  "$.id must be a number",
+		},
+		{name: "UndocumentedStatus", method: http.MethodGet, path: "/api/v1/items/1", status: http.StatusConflict, respond: gin.H{}, report: "status 409 is not documented"},
+		{name: "BadPathParameter", method: http.MethodGet, path: "/api/v1/items/abc", status: http.StatusOK, respond: validItem, report: `path parameter "item_id"`},
+		{
+			name: "ValidBody", method: http.MethodPost, path: "/api/v1/items", status: http.StatusCreated, respond: validItem,
+			body: `{"title":"Mate del año","email":"ana@anb.com","score":3}`,
+		},
+		{
+			name: "InvalidBody", method: http.MethodPost, path: "/api/v1/items", status: http.StatusCreated, respond: validItem,
+			body:   `{"title":"Mate del año","category":"layup","score":3}`,
+			report: "$.email is required",
+		},
+		{
+			name: "InvalidEnum", method: http.MethodPost, path: "/api/v1/items", status: http.StatusCreated, respond: validItem,
+			body:   `{"title":"Mate del año","email":"ana@anb.com","category":"layup"}`,
+			report: "$.category must be one of",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received string
+			router, reports := newRouter(t, func(c *gin.Context) {
+				// El handler sigue leyendo el cuerpo después de la validación
+				data, _ := c.GetRawData()
+				received = string(data)
+				if tc.respond == nil {
+					c.Status(tc.status)
+					return
+				}
+				c.JSON(tc.status, tc.respond)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.body, received)
+
+			if tc.report == "" {
+				assert.Empty(t, *reports)
+				return
+			}
+			require.Len(t, *reports, 1)
+			assert.ErrorContains(t, (*reports)[0], tc.report)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"cmp"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry convierte tipos de Go en esquemas y registra los structs como componentes.
+// Las reglas siguen a encoding/json: el nombre sale del tag json, los campos embebidos sin
+// tag se aplanan y los slices pueden ser null.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// schemaFor devuelve el esquema de un tipo. En las peticiones los campos obligatorios son los
+// que tienen validate:"required"; en las respuestas, los que no llevan omitempty.
+func (r *schemaRegistry) schemaFor(t reflect.Type, request bool) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		schema := r.schemaFor(t.Elem(), request)
+		if schema.Ref != "" {
+			// En 3.0 nullable no puede ir junto a $ref
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case t.Kind() == reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + r.register(t, request)}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: ptr(0.0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem(), request), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	default:
+		return &Schema{}
+	}
+}
+
+// register agrega el struct a los componentes una sola vez y devuelve su nombre
+func (r *schemaRegistry) register(t reflect.Type, request bool) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+	name := schemaName(t)
+	if _, taken := r.schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	r.names[t] = name
+	// Se reserva antes de recorrer los campos por si el tipo es recursivo
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.objectSchema(t, request, "json")
+	return name
+}
+
+// objectSchema arma las propiedades de un struct leyendo el tag indicado (json o form)
+func (r *schemaRegistry) objectSchema(t reflect.Type, request bool, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := fieldName(field, tag)
+		if skip {
+			continue
+		}
+
+		// Campos embebidos sin nombre: sus propiedades son del struct que los contiene
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := r.objectSchema(field.Type, request, tag)
+			for key, value := range embedded.Properties {
+				schema.Properties[key] = value
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaFor(field.Type, request)
+		rules := parseRules(field.Tag.Get("validate"))
+		applyRules(property, rules)
+		if field.Tag.Get("time_format") == time.DateOnly {
+			property.Format = "date"
+		}
+		schema.Properties[name] = property
+
+		_, required := rules["required"]
+		if request && required || !request && !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// fieldName lee el nombre del tag; skip indica un campo que no se serializa
+func fieldName(field reflect.StructField, tag string) (name string, omitempty bool, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+	value := field.Tag.Get(tag)
+	if value == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(value, ",")
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+// parseRules separa las reglas de validate:"required,oneof=a b,max=200"
+func parseRules(tag string) map[string]string {
+	rules := map[string]string{}
+	for _, rule := range strings.Split(tag, ",") {
+		if rule != "" {
+			name, param, _ := strings.Cut(rule, "=")
+			rules[name] = param
+		}
+	}
+	return rules
+}
+
+// applyRules traduce las reglas de validator que tienen equivalente en JSON Schema
+func applyRules(schema *Schema, rules map[string]string) {
+	number := func(name string) *float64 {
+		value, err := strconv.ParseFloat(rules[name], 64)
+		if err != nil {
+			return nil
+		}
+		return &value
+	}
+
+	if _, ok := rules["email"]; ok {
+		schema.Format = "email"
+	}
+	switch schema.Type {
+	case "string":
+		if oneof, ok := rules["oneof"]; ok {
+			schema.Enum = strings.Fields(oneof)
+		}
+		if value := number("min"); value != nil {
+			schema.MinLength = ptr(int(*value))
+		}
+		if value := number("max"); value != nil {
+			schema.MaxLength = ptr(int(*value))
+		}
+	case "integer", "number":
+		if value := number("min"); value != nil {
+			schema.Minimum = value
+		}
+		if value := number("gt"); value != nil {
+			schema.Minimum, schema.ExclusiveMinimum = value, true
+		}
+		if value := cmp.Or(number("max"), number("lte")); value != nil {
+			schema.Maximum = value
+		}
+	}
+}
+
+var packagePath = regexp.MustCompile(`[\w\-./]*\.`)
+
+// schemaName quita las rutas de paquete de los tipos genéricos:
+// Page[anb-app/src/video.PublicVideoResponse] es PagePublicVideoResponse
+func schemaName(t reflect.Type) string {
+	name := packagePath.ReplaceAllString(t.Name(), "")
+	return strings.NewReplacer("[", "", "]", "", ",", "", "*", "").Replace(name)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
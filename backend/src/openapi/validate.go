@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidationMiddleware compara cada petición y su respuesta con el documento y reporta las
+// diferencias. Es para los tests y el modo test de gin: no cambia la respuesta, solo avisa.
+// Las rutas que no están en el documento pasan sin revisar.
+func ValidationMiddleware(doc *Document, report func(c *gin.Context, err error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := doc.routes[routeKey(c.Request.Method, c.FullPath())]
+		if operation == nil {
+			c.Next()
+			return
+		}
+		route := c.Request.Method + " " + c.FullPath()
+
+		if err := doc.validateRequest(c, operation); err != nil {
+			report(c, fmt.Errorf("%s: request does not match the OpenAPI document: %w", route, err))
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if err := doc.validateResponse(c.Request.Method, operation, writer); err != nil {
+			report(c, fmt.Errorf("%s: response does not match the OpenAPI document: %w", route, err))
+		}
+	}
+}
+
+// captureWriter guarda una copia de los cuerpos JSON para revisarlos al final
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	if strings.Contains(w.Header().Get("Content-Type"), "json") {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (d *Document) validateRequest(c *gin.Context, operation *OperationObject) error {
+	for _, param := range operation.Parameters {
+		var values []string
+		if param.In == "path" {
+			values = []string{c.Param(param.Name)}
+		} else {
+			values = c.QueryArray(param.Name)
+		}
+		if len(values) == 0 || values[0] == "" {
+			if param.Required {
+				return fmt.Errorf("%s parameter %q is required", param.In, param.Name)
+			}
+			continue
+		}
+		for _, value := range values {
+			if err := validateParameter(param.Schema, value); err != nil {
+				return fmt.Errorf("%s parameter %q: %w", param.In, param.Name, err)
+			}
+		}
+	}
+
+	if operation.RequestBody == nil {
+		return nil
+	}
+	if media, ok := operation.RequestBody.Content["application/json"]; ok {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		// El handler vuelve a leer el cuerpo
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		var body any
+		if err := json.Unmarshal(data, &body); err != nil {
+			return fmt.Errorf("body is not valid JSON: %w", err)
+		}
+		return d.validateValue(media.Schema, body, "$", false)
+	}
+	if media, ok := operation.RequestBody.Content["multipart/form-data"]; ok {
+		// gin guarda el formulario ya leído, así que el handler lo sigue encontrando
+		form, err := c.MultipartForm()
+		if err != nil {
+			return fmt.Errorf("body is not a multipart form: %w", err)
+		}
+		for _, name := range media.Schema.Required {
+			if len(form.Value[name]) == 0 && len(form.File[name]) == 0 {
+				return fmt.Errorf("form field %q is required", name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateParameter(schema *Schema, value string) error {
+	switch schema.Type {
+	case "integer":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		if schema.Minimum != nil && float64(number) < *schema.Minimum {
+			return fmt.Errorf("%d is less than %v", number, *schema.Minimum)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	}
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, value) {
+		return fmt.Errorf("%q is not one of %v", value, schema.Enum)
+	}
+	return nil
+}
+
+func (d *Document) validateResponse(method string, operation *OperationObject, w *captureWriter) error {
+	status := w.Status()
+	response := operation.Responses[strconv.Itoa(status)]
+	if response == nil {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+	if response.Ref != "" {
+		response = d.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	}
+	if len(response.Content) == 0 || method == http.MethodHead || status == http.StatusNotModified {
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	media, ok := response.Content[contentType]
+	if !ok {
+		return fmt.Errorf("status %d: content type %q is not documented", status, contentType)
+	}
+	if !strings.Contains(contentType, "json") {
+		return nil
+	}
+
+	var body any
+	if err := json.Unmarshal(w.body.Bytes(), &body); err != nil {
+		return fmt.Errorf("status %d: body is not valid JSON: %w", status, err)
+	}
+	// En las respuestas una propiedad sin documentar es un error: el DTO y el documento se separaron
+	return d.validateValue(media.Schema, body, "$", true)
+}
+
+// validateValue revisa un valor decodificado de JSON contra el esquema. Con strict las
+// propiedades que no están en el esquema son un error.
+func (d *Document) validateValue(schema *Schema, value any, at string, strict bool) error {
+	if schema.Ref != "" {
+		return d.validateValue(d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")], value, at, strict)
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" && len(schema.AllOf) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", at)
+	}
+	for _, part := range schema.AllOf {
+		if err := d.validateValue(part, value, at, strict); err != nil {
+			return err
+		}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", at)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s.%s is required", at, name)
+			}
+		}
+		for name, nested := range object {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if strict {
+					return fmt.Errorf("%s.%s is not documented", at, name)
+				}
+				continue
+			}
+			if err := d.validateValue(property, nested, at+"."+name, strict); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s must be an array", at)
+		}
+		for i, item := range items {
+			if err := d.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", at, i), strict); err != nil {
+				return err
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", at)
+		}
+		return validateString(schema, text, at)
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s must be a number", at)
+		}
+		if schema.Type == "integer" && number != math.Trunc(number) {
+			return fmt.Errorf("%s must be an integer", at)
+		}
+		if schema.Minimum != nil && (number < *schema.Minimum || schema.ExclusiveMinimum && number == *schema.Minimum) {
+			return fmt.Errorf("%s must be greater than %v", at, *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			return fmt.Errorf("%s must be at most %v", at, *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", at)
+		}
+	}
+	return nil
+}
+
+func validateString(schema *Schema, text string, at string) error {
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, text) {
+		return fmt.Errorf("%s must be one of %v", at, schema.Enum)
+	}
+	length := utf8.RuneCountInString(text)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return fmt.Errorf("%s must be at least %d characters long", at, *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return fmt.Errorf("%s must be at most %d characters long", at, *schema.MaxLength)
+	}
+	switch schema.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			return fmt.Errorf("%s must be an RFC 3339 date-time", at)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, text); err != nil {
+			return fmt.Errorf("%s must be a date (YYYY-MM-DD)", at)
+		}
+	}
+	return nil
+}
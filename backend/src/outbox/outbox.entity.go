@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Message es un efecto secundario (webhook, tarea en cola...) registrado en la misma
+// transacción que el cambio que lo origina. El relay lo entrega después, al menos una vez.
+type Message struct {
+	ID        uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+	Topic     string `json:"topic" gorm:"index;not null"`
+	EventType string `json:"event_type" gorm:"not null"`
+	Payload   string `json:"payload" gorm:"type:jsonb;not null"`
+	// Evita registrar dos veces el mismo evento (p. ej. un umbral de votos ya alcanzado)
+	DedupeKey   *string    `json:"dedupe_key,omitempty" gorm:"uniqueIndex"`
+	Attempts    int        `json:"attempts" gorm:"default:0"`
+	LastError   string     `json:"last_error,omitempty"`
+	AvailableAt time.Time  `json:"available_at" gorm:"index"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty" gorm:"index"`
+	// Se rindió tras MaxAttempts intentos; queda para revisarlo a mano
+	FailedAt  *time.Time `json:"failed_at,omitempty" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// New serializa el payload y prepara el mensaje para guardarlo con Add
+func New(topic, eventType string, payload any) (Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Topic:       topic,
+		EventType:   eventType,
+		Payload:     string(data),
+		AvailableAt: time.Now(),
+	}, nil
+}
+
+// WithDedupeKey marca el mensaje para que se ignore si ya existe otro con la misma clave
+func (m Message) WithDedupeKey(key string) Message {
+	m.DedupeKey = &key
+	return m
+}
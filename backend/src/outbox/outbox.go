@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 50
+	// Espera máxima entre reintentos de un mensaje cuyo handler falla
+	maxBackoff = 10 * time.Minute
+	// Después de tantos intentos fallidos el mensaje se marca como fallido (FailedAt) y no se
+	// reintenta más; con el backoff son poco más de una hora
+	MaxAttempts = 15
+	// Tiempo máximo de cada handler; el lote se reserva por batchSize veces este tiempo
+	handlerTimeout = 10 * time.Second
+	// Los mensajes entregados se conservan este tiempo para auditoría, salvo los que
+	// tienen DedupeKey: su fila es la que impide registrar el evento otra vez
+	retention = 7 * 24 * time.Hour
+)
+
+// Add guarda los mensajes usando tx, la transacción del cambio que los origina.
+// Los mensajes con una DedupeKey ya registrada se descartan en silencio.
+func Add(tx *gorm.DB, messages ...Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range messages {
+		if messages[i].AvailableAt.IsZero() {
+			messages[i].AvailableAt = now
+		}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&messages).Error; err != nil {
+		return fmt.Errorf("failed to store outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Handler entrega un mensaje. Corre fuera de toda transacción y la entrega es al menos
+// una vez: si el relay no llega a marcar el mensaje, se vuelve a llamar con el mismo, así
+// que lo que escriba en db debe ser idempotente.
+type Handler func(ctx context.Context, db *gorm.DB, msg *Message) error
+
+// Relay lee los mensajes pendientes y los pasa al handler de su topic. Varias
+// instancias pueden correrlo a la vez: cada lote se reserva con FOR UPDATE SKIP LOCKED
+// moviendo su available_at al final del lease, y los handlers corren después del commit.
+type Relay struct {
+	db           *gorm.DB
+	handlers     map[string]Handler
+	pollInterval time.Duration
+	batchSize    int
+	lease        time.Duration
+}
+
+func NewRelay(db *gorm.DB) *Relay {
+	return &Relay{
+		db:           db,
+		handlers:     make(map[string]Handler),
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		lease:        defaultBatchSize*handlerTimeout + time.Minute,
+	}
+}
+
+// Handle registra el handler de un topic; debe llamarse antes de Run
+func (r *Relay) Handle(topic string, handler Handler) {
+	r.handlers[topic] = handler
+}
+
+// Run procesa lotes hasta que ctx termina
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Now()
+
+	for {
+		// Mientras haya lotes llenos se sigue sin esperar al ticker
+		for {
+			processed, err := r.ProcessBatch(ctx)
+			if err != nil {
+				log.Printf("Outbox: failed to process batch: %v", err)
+				break
+			}
+			if processed < r.batchSize {
+				break
+			}
+		}
+		if time.Since(lastPrune) > time.Hour {
+			r.prune()
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch entrega un lote de mensajes pendientes y devuelve cuántos tomó
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	messages, leasedUntil, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range messages {
+		msg := &messages[i]
+		handlerCtx, cancel := context.WithTimeout(ctx, handlerTimeout)
+		handleErr := r.handlers[msg.Topic](handlerCtx, r.db.WithContext(handlerCtx), msg)
+		cancel()
+
+		settle(msg, handleErr, time.Now())
+		// Solo se guarda si el lease sigue siendo nuestro; si venció, otro relay ya lo tomó
+		result := r.db.WithContext(ctx).Model(&Message{}).
+			Where("id = ? AND available_at = ?", msg.ID, leasedUntil).
+			Select("attempts", "last_error", "available_at", "processed_at", "failed_at").
+			Updates(msg)
+		if result.Error != nil {
+			return len(messages), result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("Outbox: lease of message %d expired before it was settled", msg.ID)
+		}
+	}
+	return len(messages), nil
+}
+
+// claim reserva un lote: lo bloquea, mueve available_at al final del lease y confirma, para
+// que ninguna llamada de red de los handlers ocurra con la transacción abierta
+func (r *Relay) claim(ctx context.Context) ([]Message, time.Time, error) {
+	topics := make([]string, 0, len(r.handlers))
+	for topic := range r.handlers {
+		topics = append(topics, topic)
+	}
+	if len(topics) == 0 {
+		return nil, time.Time{}, nil
+	}
+
+	var messages []Message
+	now := time.Now()
+	// Postgres guarda microsegundos: así la comparación al liquidar el mensaje es exacta
+	leasedUntil := now.Add(r.lease).Truncate(time.Microsecond)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("processed_at IS NULL AND failed_at IS NULL AND available_at <= ? AND topic IN ?", now, topics).
+			Order("id").Limit(r.batchSize).Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+			messages[i].AvailableAt = leasedUntil
+		}
+		return tx.Model(&Message{}).Where("id IN ?", ids).Update("available_at", leasedUntil).Error
+	})
+	return messages, leasedUntil, err
+}
+
+// settle registra el resultado del handler: entregado, un reintento con backoff o, al
+// llegar a MaxAttempts, fallido para siempre
+func settle(msg *Message, handleErr error, now time.Time) {
+	if handleErr == nil {
+		msg.ProcessedAt = &now
+		msg.LastError = ""
+		return
+	}
+
+	msg.Attempts++
+	msg.LastError = handleErr.Error()
+	if msg.Attempts >= MaxAttempts {
+		msg.FailedAt = &now
+		log.Printf("Outbox: message %d (%s) failed after %d attempts, giving up: %v", msg.ID, msg.EventType, msg.Attempts, handleErr)
+		return
+	}
+	msg.AvailableAt = now.Add(Backoff(msg.Attempts))
+	log.Printf("Outbox: message %d (%s) failed, attempt %d: %v", msg.ID, msg.EventType, msg.Attempts, handleErr)
+}
+
+func (r *Relay) prune() {
+	result := r.db.Where("processed_at < ? AND dedupe_key IS NULL", time.Now().Add(-retention)).Delete(&Message{})
+	if result.Error != nil {
+		log.Printf("Outbox: failed to prune delivered messages: %v", result.Error)
+	}
+}
+
+// Backoff devuelve la espera antes del siguiente intento: 2s, 4s, 8s... hasta maxBackoff
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 20 {
+		return maxBackoff
+	}
+	return min(time.Duration(1<<attempts)*time.Second, maxBackoff)
+}
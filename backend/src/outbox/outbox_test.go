@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB conecta a la base de TEST_DATABASE_DSN; sin ella el test se omite
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Message{}))
+	return db
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("New_SerializesPayload", func(t *testing.T) {
+		msg, err := New("webhook", "video.processed", map[string]any{"video_id": 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "webhook", msg.Topic)
+		assert.Equal(t, "video.processed", msg.EventType)
+		assert.JSONEq(t, `{"video_id":3}`, msg.Payload)
+		assert.Nil(t, msg.DedupeKey)
+		assert.False(t, msg.AvailableAt.IsZero())
+	})
+
+	t.Run("New_InvalidPayload", func(t *testing.T) {
+		_, err := New("webhook", "video.processed", make(chan int))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("WithDedupeKey", func(t *testing.T) {
+		msg, _ := New("webhook", "vote.threshold_reached", nil)
+
+		deduped := msg.WithDedupeKey("votes:3:100")
+
+		assert.Equal(t, "votes:3:100", *deduped.DedupeKey)
+		assert.Nil(t, msg.DedupeKey)
+	})
+
+	t.Run("Backoff_GrowsUntilCap", func(t *testing.T) {
+		assert.Equal(t, 2*time.Second, Backoff(1))
+		assert.Equal(t, 8*time.Second, Backoff(3))
+		assert.Equal(t, maxBackoff, Backoff(15))
+		assert.Equal(t, maxBackoff, Backoff(100))
+	})
+
+	t.Run("Settle_Delivered", func(t *testing.T) {
+		msg := &Message{Attempts: 2, LastError: "timeout"}
+		now := time.Now()
+
+		settle(msg, nil, now)
+
+		assert.Equal(t, &now, msg.ProcessedAt)
+		assert.Empty(t, msg.LastError)
+		assert.Nil(t, msg.FailedAt)
+	})
+
+	t.Run("Settle_RetriesWithBackoff", func(t *testing.T) {
+		msg := &Message{Attempts: 2}
+		now := time.Now()
+
+		settle(msg, errors.New("sqs unavailable"), now)
+
+		assert.Equal(t, 3, msg.Attempts)
+		assert.Equal(t, "sqs unavailable", msg.LastError)
+		assert.Equal(t, now.Add(Backoff(3)), msg.AvailableAt)
+		assert.Nil(t, msg.ProcessedAt)
+		assert.Nil(t, msg.FailedAt)
+	})
+
+	t.Run("Settle_GivesUpAfterMaxAttempts", func(t *testing.T) {
+		msg := &Message{Attempts: MaxAttempts - 1}
+		now := time.Now()
+
+		settle(msg, errors.New("invalid payload"), now)
+
+		assert.Equal(t, MaxAttempts, msg.Attempts)
+		assert.Equal(t, &now, msg.FailedAt)
+		assert.Nil(t, msg.ProcessedAt)
+	})
+
+	t.Run("ProcessBatch_ClaimsAndSettles", func(t *testing.T) {
+		db := testDB(t)
+		topic := fmt.Sprintf("test.relay.%d", time.Now().UnixNano())
+		t.Cleanup(func() { db.Where("topic = ?", topic).Delete(&Message{}) })
+
+		ok, _ := New(topic, "ok", nil)
+		failing, _ := New(topic, "failing", nil)
+		failing.Attempts = MaxAttempts - 1
+		require.NoError(t, Add(db, ok, failing))
+
+		relay := NewRelay(db)
+		relay.Handle(topic, func(ctx context.Context, db *gorm.DB, msg *Message) error {
+			// El lote ya está reservado y confirmado: otro relay no lo ve
+			var pending int64
+			db.Model(&Message{}).Where("topic = ? AND available_at <= ?", topic, time.Now()).Count(&pending)
+			assert.Zero(t, pending)
+			if msg.EventType == "failing" {
+				return errors.New("invalid payload")
+			}
+			return nil
+		})
+
+		processed, err := relay.ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, processed)
+
+		var messages []Message
+		require.NoError(t, db.Where("topic = ?", topic).Order("id").Find(&messages).Error)
+		require.Len(t, messages, 2)
+		assert.NotNil(t, messages[0].ProcessedAt)
+		assert.NotNil(t, messages[1].FailedAt)
+		assert.Nil(t, messages[1].ProcessedAt)
+
+		// Ni el entregado ni el fallido se vuelven a tomar
+		processed, err = relay.ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+	})
+
+	t.Run("Prune_KeepsDedupeKeys", func(t *testing.T) {
+		db := testDB(t)
+		eventType := fmt.Sprintf("test.prune.%d", time.Now().UnixNano())
+		key := "test:" + eventType
+		t.Cleanup(func() { db.Where("event_type = ?", eventType).Delete(&Message{}) })
+
+		plain, _ := New("webhook", eventType, nil)
+		deduped, _ := New("webhook", eventType, nil)
+		require.NoError(t, Add(db, plain, deduped.WithDedupeKey(key)))
+		expired := time.Now().Add(-retention - time.Hour)
+		require.NoError(t, db.Model(&Message{}).Where("event_type = ?", eventType).Update("processed_at", expired).Error)
+
+		NewRelay(db).prune()
+
+		var remaining []Message
+		require.NoError(t, db.Where("event_type = ?", eventType).Find(&remaining).Error)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, key, *remaining[0].DedupeKey)
+
+		// El evento ya registrado sigue bloqueando uno nuevo con la misma clave
+		again, _ := New("webhook", eventType, nil)
+		require.NoError(t, Add(db, again.WithDedupeKey(key)))
+		var count int64
+		require.NoError(t, db.Model(&Message{}).Where("dedupe_key = ?", key).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,387 @@
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"anb-app/src/apperr"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Kind indica cómo se guarda en el cursor el valor de una clave de orden
+type Kind int
+
+const (
+	KindInt Kind = iota
+	KindTime
+)
+
+// Field es una clave de orden permitida. Column es la expresión SQL con la que se ordena
+// y compara; si la columna admite NULL debe envolverse en COALESCE (ver Epoch).
+type Field struct {
+	Column string
+	Kind   Kind
+}
+
+// Epoch reemplaza a los tiempos nulos: COALESCE(col, 'epoch'::timestamptz)
+var Epoch = time.Unix(0, 0).UTC()
+
+// SortKey es una clave de orden pedida con ?sort=; "-votes" ordena descendente
+type SortKey struct {
+	Name string
+	Desc bool
+}
+
+// Spec describe un listado: las claves de orden que admite, la de desempate y el
+// orden por defecto. Con Offset el cursor guarda la posición en lugar de los valores
+// de la última fila (rankings, búsqueda por relevancia).
+type Spec struct {
+	Fields      map[string]Field
+	IDColumn    string
+	DefaultSort []SortKey
+	Offset      bool
+}
+
+// Filters son los filtros comunes de los listados; cada handler decide cuáles respeta
+type Filters struct {
+	Status string
+	UserID uint
+	From   *time.Time
+	To     *time.Time
+}
+
+// Request es una petición de página ya validada
+type Request struct {
+	Limit   int
+	Sort    []SortKey
+	Filters Filters
+	After   *Cursor
+
+	spec        Spec
+	fingerprint string
+}
+
+// Cursor es el contenido de next_cursor. Se entrega al cliente como base64 opaco.
+type Cursor struct {
+	Values      []string `json:"v,omitempty"`
+	ID          uint     `json:"id,omitempty"`
+	Offset      int      `json:"o,omitempty"`
+	Fingerprint string   `json:"f"`
+}
+
+// Page es el sobre común de las respuestas paginadas. NextCursor es null en la última página.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Limit      int     `json:"limit"`
+}
+
+// NewRequest arma la primera página de un listado con el orden por defecto, para
+// llamadas que no vienen de una query HTTP
+func NewRequest(spec Spec, limit int) Request {
+	return Request{
+		Limit:       min(max(limit, 1), MaxLimit),
+		Sort:        spec.DefaultSort,
+		spec:        spec,
+		fingerprint: fingerprint(url.Values{}),
+	}
+}
+
+// Parse lee limit, sort, cursor, status, user_id, from y to de la query
+func Parse(c *gin.Context, spec Spec) (Request, error) {
+	req := Request{Limit: DefaultLimit, spec: spec}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Request{}, invalid("limit", "invalid limit: must be a positive integer")
+		}
+		req.Limit = min(limit, MaxLimit)
+	}
+
+	sort, err := parseSort(c.Query("sort"), spec)
+	if err != nil {
+		return Request{}, err
+	}
+	req.Sort = sort
+
+	filters, err := parseFilters(c)
+	if err != nil {
+		return Request{}, err
+	}
+	req.Filters = filters
+	req.fingerprint = fingerprint(c.Request.URL.Query())
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil || cursor.Fingerprint != req.fingerprint {
+			return Request{}, invalid("cursor", "invalid cursor: it does not match this query")
+		}
+		if !spec.Offset && !validValues(cursor.Values, req.Sort, spec) {
+			return Request{}, invalid("cursor", "invalid cursor: it does not match this query")
+		}
+		req.After = cursor
+	}
+
+	return req, nil
+}
+
+// invalid es el error de validación de un parámetro de la query; el código es
+// invalid_<parámetro> (invalid_limit, invalid_cursor...)
+func invalid(param, message string) error {
+	return apperr.Validation("invalid_"+param, message, apperr.FieldError{Field: param, Code: "invalid", Message: message})
+}
+
+func validValues(values []string, sort []SortKey, spec Spec) bool {
+	if len(values) != len(sort) {
+		return false
+	}
+	for i, key := range sort {
+		if _, err := decodeValue(spec.Fields[key.Name].Kind, values[i]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSort(raw string, spec Spec) ([]SortKey, error) {
+	if strings.TrimSpace(raw) == "" {
+		return spec.DefaultSort, nil
+	}
+
+	var keys []SortKey
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Name: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := spec.Fields[key.Name]; !ok {
+			return nil, invalid("sort", fmt.Sprintf("invalid sort: unknown key %q", key.Name))
+		}
+		for _, existing := range keys {
+			if existing.Name == key.Name {
+				return nil, invalid("sort", fmt.Sprintf("invalid sort: key %q is repeated", key.Name))
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseFilters(c *gin.Context) (Filters, error) {
+	filters := Filters{Status: c.Query("status")}
+
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return Filters{}, invalid("user_id", "invalid filter: user_id must be a number")
+		}
+		filters.UserID = uint(userID)
+	}
+
+	if raw := c.Query("from"); raw != "" {
+		from, _, err := parseTime(raw)
+		if err != nil {
+			return Filters{}, invalid("from", "invalid filter: from must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		filters.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, dateOnly, err := parseTime(raw)
+		if err != nil {
+			return Filters{}, invalid("to", "invalid filter: to must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		// Una fecha sin hora incluye el día completo; To siempre es exclusivo
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filters.To = &to
+	}
+	if filters.From != nil && filters.To != nil && !filters.To.After(*filters.From) {
+		return Filters{}, invalid("to", "invalid filter: to must be after from")
+	}
+
+	return filters, nil
+}
+
+func parseTime(raw string) (time.Time, bool, error) {
+	if value, err := time.Parse(time.RFC3339, raw); err == nil {
+		return value, false, nil
+	}
+	value, err := time.Parse(time.DateOnly, raw)
+	return value, true, err
+}
+
+// Offset es la posición desde la que empieza la página (listados con Spec.Offset)
+func (r Request) Offset() int {
+	if r.After == nil {
+		return 0
+	}
+	return r.After.Offset
+}
+
+// Apply agrega el orden, la condición del cursor y el límite (una fila de más para
+// saber si hay otra página). Los filtros los aplica cada repositorio.
+func (r Request) Apply(db *gorm.DB) *gorm.DB {
+	if r.spec.Offset {
+		return db.Offset(r.Offset()).Limit(r.Limit + 1)
+	}
+
+	idDesc := len(r.Sort) > 0 && r.Sort[len(r.Sort)-1].Desc
+	var orders []string
+	for _, key := range r.Sort {
+		orders = append(orders, r.spec.Fields[key.Name].Column+direction(key.Desc))
+	}
+	orders = append(orders, r.spec.IDColumn+direction(idDesc))
+
+	if r.After != nil {
+		condition, args := r.keyset(idDesc)
+		db = db.Where(condition, args...)
+	}
+
+	return db.Order(strings.Join(orders, ", ")).Limit(r.Limit + 1)
+}
+
+// keyset arma la comparación lexicográfica (k1, ..., kn, id) contra la última fila:
+// (k1 < v1) OR (k1 = v1 AND k2 < v2) OR ... con el operador según la dirección de cada clave.
+// Parse ya validó que los valores del cursor se pueden decodificar.
+func (r Request) keyset(idDesc bool) (string, []interface{}) {
+	columns := make([]string, 0, len(r.Sort)+1)
+	descs := make([]bool, 0, len(r.Sort)+1)
+	values := make([]interface{}, 0, len(r.Sort)+1)
+
+	for i, key := range r.Sort {
+		field := r.spec.Fields[key.Name]
+		value, _ := decodeValue(field.Kind, r.After.Values[i])
+		columns = append(columns, field.Column)
+		descs = append(descs, key.Desc)
+		values = append(values, value)
+	}
+	columns = append(columns, r.spec.IDColumn)
+	descs = append(descs, idDesc)
+	values = append(values, r.After.ID)
+
+	var clauses []string
+	var args []interface{}
+	for i := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			args = append(args, values[j])
+		}
+		operator := " > ?"
+		if descs[i] {
+			operator = " < ?"
+		}
+		parts = append(parts, columns[i]+operator)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+// NewPage recorta la fila de más que pidió Apply y arma next_cursor a partir de la última
+// fila entregada. key devuelve el valor de cada clave de orden (int, time.Time o *time.Time).
+func NewPage[T any](items []T, req Request, id func(T) uint, key func(item T, name string) interface{}) Page[T] {
+	page := Page[T]{Items: items, Limit: req.Limit}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) <= req.Limit {
+		return page
+	}
+
+	page.Items = items[:req.Limit]
+	last := page.Items[len(page.Items)-1]
+
+	cursor := Cursor{Fingerprint: req.fingerprint}
+	if req.spec.Offset {
+		cursor.Offset = req.Offset() + req.Limit
+	} else {
+		cursor.ID = id(last)
+		for _, sortKey := range req.Sort {
+			cursor.Values = append(cursor.Values, encodeValue(key(last, sortKey.Name)))
+		}
+	}
+
+	token := encodeCursor(cursor)
+	page.NextCursor = &token
+	return page
+}
+
+func encodeValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return Epoch.Format(time.RFC3339Nano)
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func decodeValue(kind Kind, raw string) (interface{}, error) {
+	if kind == KindTime {
+		return time.Parse(time.RFC3339Nano, raw)
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+func encodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// fingerprint ata el cursor a los parámetros con los que se generó (orden, filtros y los
+// propios de cada listado, como category o q), para que no se reutilice en otra consulta.
+// El límite puede cambiar entre páginas.
+func fingerprint(query url.Values) string {
+	query.Del("cursor")
+	query.Del("limit")
+	sum := sha256.Sum256([]byte(query.Encode()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Map convierte los elementos de una página conservando el cursor
+func Map[T, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+	return Page[U]{Items: items, NextCursor: page.NextCursor, Limit: page.Limit}
+}
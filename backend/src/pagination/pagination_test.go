@@ -0,0 +1,165 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	ID        uint
+	Votes     int
+	CreatedAt time.Time
+}
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"votes":      {Column: "votes", Kind: KindInt},
+		"created_at": {Column: "created_at", Kind: KindTime},
+	},
+	IDColumn:    "id",
+	DefaultSort: []SortKey{{Name: "votes", Desc: true}},
+}
+
+func rowID(r row) uint {
+	return r.ID
+}
+
+func rowKey(r row, name string) interface{} {
+	if name == "votes" {
+		return r.Votes
+	}
+	return r.CreatedAt
+}
+
+func parseQuery(t *testing.T, spec Spec, query string) (Request, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items?"+query, nil)
+	return Parse(c, spec)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		req, err := parseQuery(t, testSpec, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultLimit, req.Limit)
+		assert.Equal(t, testSpec.DefaultSort, req.Sort)
+		assert.Nil(t, req.After)
+	})
+
+	t.Run("SortAndLimit", func(t *testing.T) {
+		req, err := parseQuery(t, testSpec, "sort=-created_at,votes&limit=500")
+
+		assert.NoError(t, err)
+		assert.Equal(t, MaxLimit, req.Limit)
+		assert.Equal(t, []SortKey{{Name: "created_at", Desc: true}, {Name: "votes"}}, req.Sort)
+	})
+
+	t.Run("UnknownSortKey", func(t *testing.T) {
+		_, err := parseQuery(t, testSpec, "sort=title")
+
+		assert.ErrorContains(t, err, "invalid sort")
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		_, err := parseQuery(t, testSpec, "limit=0")
+
+		assert.ErrorContains(t, err, "invalid limit")
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		req, err := parseQuery(t, testSpec, "status=processed&user_id=7&from=2025-03-01&to=2025-03-31")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "processed", req.Filters.Status)
+		assert.Equal(t, uint(7), req.Filters.UserID)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), *req.Filters.From)
+		// Una fecha sin hora incluye el día completo
+		assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), *req.Filters.To)
+	})
+
+	t.Run("InvalidDateRange", func(t *testing.T) {
+		_, err := parseQuery(t, testSpec, "from=2025-03-31&to=2025-03-01")
+
+		assert.ErrorContains(t, err, "invalid filter")
+	})
+
+	t.Run("GarbageCursor", func(t *testing.T) {
+		_, err := parseQuery(t, testSpec, "cursor=not-a-cursor")
+
+		assert.ErrorContains(t, err, "invalid cursor")
+	})
+}
+
+func TestNewPage(t *testing.T) {
+	created := time.Date(2025, 3, 14, 18, 30, 0, 123456000, time.UTC)
+
+	t.Run("NextCursorRoundTrip", func(t *testing.T) {
+		req, _ := parseQuery(t, testSpec, "limit=2&status=processed")
+		rows := []row{{ID: 9, Votes: 30}, {ID: 4, Votes: 12, CreatedAt: created}, {ID: 2, Votes: 12}}
+
+		page := NewPage(rows, req, rowID, rowKey)
+
+		assert.Len(t, page.Items, 2)
+		if assert.NotNil(t, page.NextCursor) {
+			next, err := parseQuery(t, testSpec, "limit=5&status=processed&cursor="+*page.NextCursor)
+			assert.NoError(t, err)
+			assert.Equal(t, uint(4), next.After.ID)
+			assert.Equal(t, []string{"12"}, next.After.Values)
+		}
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		req, _ := parseQuery(t, testSpec, "limit=2")
+
+		page := NewPage([]row{{ID: 1}}, req, rowID, rowKey)
+
+		assert.Len(t, page.Items, 1)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("EmptyPageHasItemsArray", func(t *testing.T) {
+		req, _ := parseQuery(t, testSpec, "")
+
+		page := NewPage[row](nil, req, rowID, rowKey)
+
+		assert.NotNil(t, page.Items)
+	})
+
+	t.Run("CursorBoundToQuery", func(t *testing.T) {
+		req, _ := parseQuery(t, testSpec, "limit=1&status=processed")
+		page := NewPage([]row{{ID: 2}, {ID: 1}}, req, rowID, rowKey)
+
+		_, err := parseQuery(t, testSpec, "status=failed&cursor="+*page.NextCursor)
+
+		assert.ErrorContains(t, err, "invalid cursor")
+	})
+
+	t.Run("OffsetCursor", func(t *testing.T) {
+		spec := Spec{Offset: true}
+		req, _ := parseQuery(t, spec, "limit=2")
+		page := NewPage([]row{{ID: 1}, {ID: 2}, {ID: 3}}, req, nil, nil)
+
+		next, err := parseQuery(t, spec, "limit=2&cursor="+*page.NextCursor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, next.Offset())
+	})
+}
+
+func TestKeyset(t *testing.T) {
+	created := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	req, _ := parseQuery(t, testSpec, "sort=-votes,created_at")
+	req.After = &Cursor{Values: []string{"12", created.Format(time.RFC3339Nano)}, ID: 4}
+
+	condition, args := req.keyset(false)
+
+	assert.Equal(t, "((votes < ?) OR (votes = ? AND created_at > ?) OR (votes = ? AND created_at = ? AND id > ?))", condition)
+	assert.Equal(t, []interface{}{int64(12), int64(12), created, int64(12), created, uint(4)}, args)
+}
@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Por debajo de este nivel (el umbral absoluto de EBU R128) el audio se considera silencio
+const silenceThresholdLUFS = -70.0
+
+// MediaInfo resume la salida de ffprobe para un archivo
+type MediaInfo struct {
+	HasVideo bool
+	HasAudio bool
+	Duration float64
+}
+
+// Loudness es la medición EBU R128 que devuelve el filtro loudnorm
+type Loudness struct {
+	Integrated   float64
+	TruePeak     float64
+	Range        float64
+	Threshold    float64
+	TargetOffset float64
+}
+
+// IsSilent indica si la pista de audio no tiene contenido audible
+func (l Loudness) IsSilent() bool {
+	return math.IsInf(l.Integrated, -1) || l.Integrated <= silenceThresholdLUFS
+}
+
+// ProbeArgs devuelve los argumentos de ffprobe para inspeccionar streams y duración
+func ProbeArgs(input string) []string {
+	return []string{"-v", "error", "-show_entries", "stream=codec_type:format=duration", "-of", "json", input}
+}
+
+// ParseProbe interpreta la salida JSON de ffprobe
+func ParseProbe(output []byte) (*MediaInfo, error) {
+	var probe struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("invalid ffprobe output: %w", err)
+	}
+
+	info := &MediaInfo{}
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			info.HasVideo = true
+		case "audio":
+			info.HasAudio = true
+		}
+	}
+	if probe.Format.Duration != "" {
+		duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", probe.Format.Duration, err)
+		}
+		info.Duration = duration
+	}
+	return info, nil
+}
+
+// AddSilenceArgs agrega una pista de audio silenciosa a un clip que no tiene audio,
+// para que los pasos siguientes (normalización, concat) siempre encuentren una.
+func AddSilenceArgs(input, output string) []string {
+	return []string{"-y", "-i", input,
+		"-f", "lavfi", "-i", "anullsrc=channel_layout=stereo:sample_rate=48000",
+		"-map", "0:v:0", "-map", "1:a:0", "-c:v", "copy", "-c:a", "aac", "-b:a", "128k", "-shortest", output}
+}
+
+// LoudnessScanArgs devuelve la primera pasada de loudnorm, que solo mide el audio
+func LoudnessScanArgs(st Step, input string) []string {
+	return []string{"-hide_banner", "-nostats", "-i", input, "-vn",
+		"-af", loudnormFilter(st, nil) + ":print_format=json", "-f", "null", "-"}
+}
+
+// ParseLoudness extrae la medición JSON que loudnorm escribe al final de stderr
+func ParseLoudness(stderr string) (*Loudness, error) {
+	start, end := strings.LastIndex(stderr, "{"), strings.LastIndex(stderr, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("loudnorm measurement not found in ffmpeg output")
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(stderr[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid loudnorm measurement: %w", err)
+	}
+
+	l := &Loudness{}
+	fields := map[string]*float64{
+		"input_i":       &l.Integrated,
+		"input_tp":      &l.TruePeak,
+		"input_lra":     &l.Range,
+		"input_thresh":  &l.Threshold,
+		"target_offset": &l.TargetOffset,
+	}
+	for key, dst := range fields {
+		value, ok := raw[key]
+		if !ok {
+			return nil, fmt.Errorf("loudnorm measurement is missing %s", key)
+		}
+		// ParseFloat acepta "-inf", que es lo que loudnorm reporta para silencio
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		*dst = parsed
+	}
+	return l, nil
+}
@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const loudnormStderr = `[Parsed_loudnorm_0 @ 0x55d5c2a7e8c0]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}
+`
+
+func TestAudio(t *testing.T) {
+	t.Run("ParseProbe_VideoWithAudio", func(t *testing.T) {
+		output := `{"streams":[{"codec_type":"video"},{"codec_type":"audio"}],"format":{"duration":"12.480000"}}`
+
+		info, err := ParseProbe([]byte(output))
+
+		assert.NoError(t, err)
+		assert.True(t, info.HasVideo)
+		assert.True(t, info.HasAudio)
+		assert.Equal(t, 12.48, info.Duration)
+	})
+
+	t.Run("ParseProbe_NoAudio", func(t *testing.T) {
+		info, err := ParseProbe([]byte(`{"streams":[{"codec_type":"video"}],"format":{"duration":"3.0"}}`))
+
+		assert.NoError(t, err)
+		assert.True(t, info.HasVideo)
+		assert.False(t, info.HasAudio)
+	})
+
+	t.Run("ParseProbe_InvalidOutput", func(t *testing.T) {
+		_, err := ParseProbe([]byte("not json"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("ParseLoudness_Success", func(t *testing.T) {
+		l, err := ParseLoudness(loudnormStderr)
+
+		assert.NoError(t, err)
+		assert.Equal(t, -27.61, l.Integrated)
+		assert.Equal(t, -4.47, l.TruePeak)
+		assert.Equal(t, 18.06, l.Range)
+		assert.Equal(t, -39.20, l.Threshold)
+		assert.Equal(t, 0.58, l.TargetOffset)
+		assert.False(t, l.IsSilent())
+	})
+
+	t.Run("ParseLoudness_Silence", func(t *testing.T) {
+		stderr := strings.NewReplacer(`"-27.61"`, `"-inf"`, `"-4.47"`, `"-inf"`).Replace(loudnormStderr)
+
+		l, err := ParseLoudness(stderr)
+
+		assert.NoError(t, err)
+		assert.True(t, math.IsInf(l.Integrated, -1))
+		assert.True(t, l.IsSilent())
+	})
+
+	t.Run("ParseLoudness_MissingMeasurement", func(t *testing.T) {
+		_, err := ParseLoudness("Error opening input file")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("NormalizeAudio_SecondPassUsesMeasurement", func(t *testing.T) {
+		measured := &Loudness{Integrated: -27.61, TruePeak: -4.47, Range: 18.06, Threshold: -39.2, TargetOffset: 0.58}
+
+		args, err := BuildArgs(Step{Type: StepNormalizeAudio}, Job{Input: "in.mkv", Output: "out.mkv", Loudness: measured})
+
+		assert.NoError(t, err)
+		assert.Contains(t, args, "loudnorm=I=-16:TP=-1.5:LRA=11:measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:measured_thresh=-39.2:offset=0.58:linear=true")
+	})
+
+	t.Run("LoudnessScanArgs_OnlyMeasures", func(t *testing.T) {
+		joined := strings.Join(LoudnessScanArgs(Step{Type: StepNormalizeAudio}, "in.mkv"), " ")
+
+		assert.Contains(t, joined, "print_format=json")
+		assert.Contains(t, joined, "-f null -")
+	})
+
+	t.Run("AddSilenceArgs_KeepsVideo", func(t *testing.T) {
+		joined := strings.Join(AddSilenceArgs("in.mp4", "out.mkv"), " ")
+
+		assert.Contains(t, joined, "anullsrc=channel_layout=stereo:sample_rate=48000")
+		assert.Contains(t, joined, "-c:v copy")
+		assert.Contains(t, joined, "-shortest")
+	})
+}
@@ -0,0 +1,132 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Job describe una ejecución concreta de un paso
+type Job struct {
+	Input  string
+	Output string
+	// Archivos con el texto ya renderizado de cada TextOverlay del paso, en el
+	// mismo orden. Se usan archivos para no tener que escapar el texto en el filtro.
+	TextFiles []string
+	// Medición previa del audio; si existe, normalize_audio hace la segunda pasada
+	// de loudnorm (lineal y más precisa) en lugar de la normalización dinámica.
+	Loudness *Loudness
+}
+
+// BuildArgs construye los argumentos de FFmpeg para ejecutar un paso
+func BuildArgs(st Step, job Job) ([]string, error) {
+	input, output := job.Input, job.Output
+
+	switch st.Type {
+	case StepTrim:
+		// Copia de streams: recortar no necesita re-codificar
+		return []string{"-y", "-i", input, "-t", formatNumber(st.MaxDuration),
+			"-map", "0:v:0", "-map", "0:a:0?", "-c", "copy", "-avoid_negative_ts", "make_zero", output}, nil
+	case StepScale:
+		return scaleArgs(st, job)
+	case StepNormalizeAudio:
+		return []string{"-y", "-i", input, "-af", loudnormFilter(st, job.Loudness),
+			"-c:v", "copy", "-c:a", "aac", "-b:a", "128k", "-ar", "48000", output}, nil
+	case StepConcat:
+		return concatArgs(st, input, output), nil
+	}
+	return nil, fmt.Errorf("unknown step type %q", st.Type)
+}
+
+func scaleArgs(st Step, job Job) ([]string, error) {
+	args := []string{"-y", "-i", job.Input}
+
+	if !st.HasOverlays() {
+		args = append(args, "-vf", fmt.Sprintf("scale=%d:%d,setdar=16/9", st.Width, st.Height))
+	} else {
+		if len(job.TextFiles) != len(st.Texts) {
+			return nil, fmt.Errorf("expected %d text files, got %d", len(st.Texts), len(job.TextFiles))
+		}
+		graph, extraInputs, videoLabel := scaleFilterGraph(st, job.TextFiles)
+		for _, in := range extraInputs {
+			// overlay repite el único fotograma del logo durante todo el video
+			args = append(args, "-i", in)
+		}
+		args = append(args, "-filter_complex", graph, "-map", "["+videoLabel+"]", "-map", "0:a:0?")
+	}
+
+	args = append(args, videoEncodeArgs(st)...)
+	args = append(args, "-c:a", "aac", "-b:a", "128k", job.Output)
+	return args, nil
+}
+
+// concatArgs une intro, clip y cierre con el filtro concat. Cada entrada se normaliza
+// a la misma resolución, framerate y formato de audio antes de unirlas, por lo que
+// no importa que los archivos tengan codecs distintos.
+func concatArgs(st Step, input, output string) []string {
+	width, height, fps := orDefault(st.Width, DefaultWidth), orDefault(st.Height, DefaultHeight), orDefault(st.FPS, DefaultFPS)
+
+	var inputs []string
+	if st.Intro != "" {
+		inputs = append(inputs, st.Intro)
+	}
+	inputs = append(inputs, input)
+	if st.Outro != "" {
+		inputs = append(inputs, st.Outro)
+	}
+
+	args := []string{"-y"}
+	var filter, streams strings.Builder
+	for i, in := range inputs {
+		args = append(args, "-i", in)
+		fmt.Fprintf(&filter, "[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%d,format=yuv420p[v%d];",
+			i, width, height, width, height, fps, i)
+		fmt.Fprintf(&filter, "[%d:a]aformat=sample_rates=48000:channel_layouts=stereo[a%d];", i, i)
+		fmt.Fprintf(&streams, "[v%d][a%d]", i, i)
+	}
+	fmt.Fprintf(&filter, "%sconcat=n=%d:v=1:a=1[v][a]", streams.String(), len(inputs))
+
+	args = append(args, "-filter_complex", filter.String(), "-map", "[v]", "-map", "[a]")
+	args = append(args, videoEncodeArgs(st)...)
+	args = append(args, "-c:a", "aac", "-b:a", "128k", "-movflags", "+faststart", output)
+	return args
+}
+
+func videoEncodeArgs(st Step) []string {
+	preset := st.Preset
+	if preset == "" {
+		preset = DefaultPreset
+	}
+	return []string{"-c:v", "libx264", "-preset", preset, "-crf", strconv.Itoa(orDefault(st.CRF, DefaultCRF))}
+}
+
+func loudnormFilter(st Step, measured *Loudness) string {
+	i, tp, lra := st.IntegratedLoudness, st.TruePeak, st.LoudnessRange
+	if i == 0 {
+		i = -16
+	}
+	if tp == 0 {
+		tp = -1.5
+	}
+	if lra == 0 {
+		lra = 11
+	}
+	filter := fmt.Sprintf("loudnorm=I=%s:TP=%s:LRA=%s", formatNumber(i), formatNumber(tp), formatNumber(lra))
+	if measured != nil {
+		filter += fmt.Sprintf(":measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true",
+			formatNumber(measured.Integrated), formatNumber(measured.TruePeak), formatNumber(measured.Range),
+			formatNumber(measured.Threshold), formatNumber(measured.TargetOffset))
+	}
+	return filter
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
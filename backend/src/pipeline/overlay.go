@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Posiciones admitidas para logos y textos
+const (
+	PositionTopLeft     = "top-left"
+	PositionTopRight    = "top-right"
+	PositionBottomLeft  = "bottom-left"
+	PositionBottomRight = "bottom-right"
+	PositionCenter      = "center"
+)
+
+const (
+	defaultMargin    = 24
+	defaultFontFile  = "/usr/share/fonts/dejavu/DejaVuSans-Bold.ttf"
+	defaultFontSize  = 32
+	defaultFontColor = "white"
+)
+
+// Watermark superpone una imagen (el logo de ANB) sobre el video
+type Watermark struct {
+	Image    string  `json:"image" yaml:"image"`
+	Position string  `json:"position,omitempty" yaml:"position,omitempty"`
+	Opacity  float64 `json:"opacity,omitempty" yaml:"opacity,omitempty"`
+	Margin   int     `json:"margin,omitempty" yaml:"margin,omitempty"`
+	// Ancho del logo en píxeles; el alto se ajusta proporcionalmente
+	Width int `json:"width,omitempty" yaml:"width,omitempty"`
+	// Color que se vuelve transparente, útil para logos JPG con fondo sólido
+	KeyColor string `json:"key_color,omitempty" yaml:"key_color,omitempty"`
+}
+
+// TextOverlay dibuja un texto sobre el video. Text admite las variables
+// {player_name}, {city} y {title}, que se reemplazan con los datos del video.
+type TextOverlay struct {
+	Text      string  `json:"text" yaml:"text"`
+	Position  string  `json:"position,omitempty" yaml:"position,omitempty"`
+	Margin    int     `json:"margin,omitempty" yaml:"margin,omitempty"`
+	FontFile  string  `json:"font_file,omitempty" yaml:"font_file,omitempty"`
+	FontSize  int     `json:"font_size,omitempty" yaml:"font_size,omitempty"`
+	FontColor string  `json:"font_color,omitempty" yaml:"font_color,omitempty"`
+	Opacity   float64 `json:"opacity,omitempty" yaml:"opacity,omitempty"`
+	// Dibuja un recuadro semitransparente detrás del texto
+	Box bool `json:"box,omitempty" yaml:"box,omitempty"`
+}
+
+// Metadata son los datos del video disponibles para los textos superpuestos
+type Metadata struct {
+	PlayerName string
+	City       string
+	Title      string
+}
+
+// RenderText reemplaza las variables de una plantilla de texto
+func RenderText(template string, meta Metadata) string {
+	return strings.NewReplacer(
+		"{player_name}", meta.PlayerName,
+		"{city}", meta.City,
+		"{title}", meta.Title,
+	).Replace(template)
+}
+
+func validPosition(position string) bool {
+	switch position {
+	case "", PositionTopLeft, PositionTopRight, PositionBottomLeft, PositionBottomRight, PositionCenter:
+		return true
+	}
+	return false
+}
+
+// placement devuelve las expresiones x/y de FFmpeg para una posición. mainW/mainH son
+// las variables del video base y itemW/itemH las del elemento superpuesto, que
+// cambian de nombre entre los filtros overlay y drawtext.
+func placement(position string, margin int, mainW, mainH, itemW, itemH string) (string, string) {
+	m := fmt.Sprint(margin)
+	left, right := m, fmt.Sprintf("%s-%s-%s", mainW, itemW, m)
+	top, bottom := m, fmt.Sprintf("%s-%s-%s", mainH, itemH, m)
+
+	switch position {
+	case PositionTopLeft:
+		return left, top
+	case PositionBottomLeft:
+		return left, bottom
+	case PositionBottomRight:
+		return right, bottom
+	case PositionCenter:
+		return fmt.Sprintf("(%s-%s)/2", mainW, itemW), fmt.Sprintf("(%s-%s)/2", mainH, itemH)
+	}
+	return right, top
+}
+
+// scaleFilterGraph arma el filter_complex del paso de escalado, aplicando el logo y
+// los textos en la misma pasada para no re-codificar de nuevo. Devuelve el grafo,
+// las entradas adicionales (el logo) y la etiqueta del stream de video resultante.
+func scaleFilterGraph(st Step, textFiles []string) (string, []string, string) {
+	var parts, extraInputs []string
+	parts = append(parts, fmt.Sprintf("[0:v]scale=%d:%d,setdar=16/9[base]", st.Width, st.Height))
+	last := "base"
+
+	if wm := st.Watermark; wm != nil {
+		extraInputs = append(extraInputs, wm.Image)
+
+		logo := []string{"format=rgba"}
+		if wm.Width > 0 {
+			logo = append(logo, fmt.Sprintf("scale=%d:-1", wm.Width))
+		}
+		if wm.KeyColor != "" {
+			logo = append(logo, fmt.Sprintf("colorkey=%s:0.3:0.2", wm.KeyColor))
+		}
+		logo = append(logo, fmt.Sprintf("colorchannelmixer=aa=%s", formatNumber(opacityOrDefault(wm.Opacity))))
+		parts = append(parts, fmt.Sprintf("[1:v]%s[logo]", strings.Join(logo, ",")))
+
+		x, y := placement(wm.Position, marginOrDefault(wm.Margin), "W", "H", "w", "h")
+		parts = append(parts, fmt.Sprintf("[%s][logo]overlay=%s:%s[wm]", last, x, y))
+		last = "wm"
+	}
+
+	for i, text := range st.Texts {
+		fontFile := text.FontFile
+		if fontFile == "" {
+			fontFile = defaultFontFile
+		}
+		fontColor := text.FontColor
+		if fontColor == "" {
+			fontColor = defaultFontColor
+		}
+
+		x, y := placement(text.Position, marginOrDefault(text.Margin), "w", "h", "tw", "th")
+		opts := []string{
+			fmt.Sprintf("textfile='%s'", textFiles[i]),
+			"expansion=none",
+			fmt.Sprintf("fontfile='%s'", fontFile),
+			fmt.Sprintf("fontsize=%d", orDefault(text.FontSize, defaultFontSize)),
+			fmt.Sprintf("fontcolor=%s@%s", fontColor, formatNumber(opacityOrDefault(text.Opacity))),
+			"x=" + x,
+			"y=" + y,
+		}
+		if text.Box {
+			opts = append(opts, "box=1", "boxcolor=black@0.4", "boxborderw=8")
+		}
+
+		label := fmt.Sprintf("txt%d", i)
+		parts = append(parts, fmt.Sprintf("[%s]drawtext=%s[%s]", last, strings.Join(opts, ":"), label))
+		last = label
+	}
+
+	return strings.Join(parts, ";"), extraInputs, last
+}
+
+func opacityOrDefault(opacity float64) float64 {
+	if opacity <= 0 || opacity > 1 {
+		return 1
+	}
+	return opacity
+}
+
+func marginOrDefault(margin int) int {
+	if margin <= 0 {
+		return defaultMargin
+	}
+	return margin
+}
@@ -0,0 +1,262 @@
+package pipeline
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed specs/*.yaml
+var builtinSpecs embed.FS
+
+// Tipos de paso soportados por el worker
+const (
+	StepTrim           = "trim"
+	StepScale          = "scale"
+	StepNormalizeAudio = "normalize_audio"
+	StepConcat         = "concat"
+)
+
+// Valores por defecto de codificación
+const (
+	DefaultWidth   = 1280
+	DefaultHeight  = 720
+	DefaultFPS     = 30
+	DefaultPreset  = "fast"
+	DefaultCRF     = 23
+	DefaultRetries = 2
+)
+
+// Spec describe un pipeline de procesamiento versionado
+type Spec struct {
+	Name                string `json:"name" yaml:"name"`
+	Version             int    `json:"version" yaml:"version"`
+	Retries             int    `json:"retries" yaml:"retries"`
+	RetryBackoffSeconds int    `json:"retry_backoff_seconds" yaml:"retry_backoff_seconds"`
+	Steps               []Step `json:"steps" yaml:"steps"`
+}
+
+// Step es un paso del pipeline. Solo se usan los campos relevantes para su tipo.
+type Step struct {
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type    string `json:"type" yaml:"type"`
+	Retries *int   `json:"retries,omitempty" yaml:"retries,omitempty"`
+
+	// trim
+	MaxDuration float64 `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+
+	// scale / concat
+	Width  int    `json:"width,omitempty" yaml:"width,omitempty"`
+	Height int    `json:"height,omitempty" yaml:"height,omitempty"`
+	FPS    int    `json:"fps,omitempty" yaml:"fps,omitempty"`
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
+	CRF    int    `json:"crf,omitempty" yaml:"crf,omitempty"`
+
+	// normalize_audio (EBU R128)
+	IntegratedLoudness float64 `json:"integrated_lufs,omitempty" yaml:"integrated_lufs,omitempty"`
+	TruePeak           float64 `json:"true_peak,omitempty" yaml:"true_peak,omitempty"`
+	LoudnessRange      float64 `json:"loudness_range,omitempty" yaml:"loudness_range,omitempty"`
+	// Rechaza clips cuya pista de audio es silencio absoluto
+	RejectSilent bool `json:"reject_silent,omitempty" yaml:"reject_silent,omitempty"`
+
+	// scale: branding aplicado en la misma pasada de codificación
+	Watermark *Watermark    `json:"watermark,omitempty" yaml:"watermark,omitempty"`
+	Texts     []TextOverlay `json:"texts,omitempty" yaml:"texts,omitempty"`
+
+	// concat (bumpers)
+	Intro string `json:"intro,omitempty" yaml:"intro,omitempty"`
+	Outro string `json:"outro,omitempty" yaml:"outro,omitempty"`
+}
+
+// Ref identifica la versión del pipeline que se guarda en cada video
+func (s *Spec) Ref() string {
+	return fmt.Sprintf("%s@v%d", s.Name, s.Version)
+}
+
+// Label devuelve un nombre legible del paso para logs
+func (st Step) Label() string {
+	if st.Name != "" {
+		return st.Name
+	}
+	return st.Type
+}
+
+// HasOverlays indica si el paso aplica logo o textos
+func (st Step) HasOverlays() bool {
+	return st.Watermark != nil || len(st.Texts) > 0
+}
+
+// Assets devuelve los archivos locales que el pipeline necesita (bumpers, logos, fuentes)
+func (s *Spec) Assets() []string {
+	var assets []string
+	for _, st := range s.Steps {
+		for _, path := range []string{st.Intro, st.Outro} {
+			if path != "" {
+				assets = append(assets, path)
+			}
+		}
+		if st.Watermark != nil {
+			assets = append(assets, st.Watermark.Image)
+		}
+		for _, text := range st.Texts {
+			if text.FontFile != "" {
+				assets = append(assets, text.FontFile)
+			} else {
+				assets = append(assets, defaultFontFile)
+			}
+		}
+	}
+	return assets
+}
+
+// MaxAttempts devuelve cuántas veces se puede ejecutar el paso antes de fallar
+func (s *Spec) MaxAttempts(st Step) int {
+	retries := s.Retries
+	if st.Retries != nil {
+		retries = *st.Retries
+	}
+	return retries + 1
+}
+
+// RetryBackoff devuelve la espera antes del intento número attempt (empezando en 1)
+func (s *Spec) RetryBackoff(attempt int) time.Duration {
+	return time.Duration(s.RetryBackoffSeconds*attempt) * time.Second
+}
+
+// Default devuelve el pipeline embebido en el binario
+func Default() (*Spec, error) {
+	data, err := builtinSpecs.ReadFile("specs/default.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read builtin pipeline: %w", err)
+	}
+	return Parse(data, ".yaml")
+}
+
+// Load lee una especificación desde un archivo YAML o JSON
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline spec %s: %w", path, err)
+	}
+	return Parse(data, filepath.Ext(path))
+}
+
+// Parse decodifica y valida una especificación. El formato se elige por la extensión.
+func Parse(data []byte, ext string) (*Spec, error) {
+	spec := &Spec{Retries: DefaultRetries}
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("invalid pipeline JSON: %w", err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("invalid pipeline YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported pipeline spec format %q", ext)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate comprueba que la especificación se pueda ejecutar
+func (s *Spec) Validate() error {
+	var errs []error
+
+	if s.Name == "" {
+		errs = append(errs, errors.New("pipeline name is required"))
+	}
+	if s.Version < 1 {
+		errs = append(errs, errors.New("pipeline version must be >= 1"))
+	}
+	if s.Retries < 0 || s.RetryBackoffSeconds < 0 {
+		errs = append(errs, errors.New("retries and retry_backoff_seconds must be >= 0"))
+	}
+	if len(s.Steps) == 0 {
+		errs = append(errs, errors.New("pipeline must define at least one step"))
+	}
+
+	concatSteps := 0
+	for i, st := range s.Steps {
+		prefix := fmt.Sprintf("step %d (%s)", i+1, st.Label())
+		if st.Retries != nil && *st.Retries < 0 {
+			errs = append(errs, fmt.Errorf("%s: retries must be >= 0", prefix))
+		}
+		if st.HasOverlays() && st.Type != StepScale {
+			errs = append(errs, fmt.Errorf("%s: watermark and texts are only supported on scale steps", prefix))
+		}
+		errs = append(errs, st.validateOverlays(prefix)...)
+
+		switch st.Type {
+		case StepTrim:
+			if st.MaxDuration <= 0 {
+				errs = append(errs, fmt.Errorf("%s: max_duration must be > 0", prefix))
+			}
+		case StepScale:
+			if st.Width <= 0 || st.Height <= 0 || st.Width%2 != 0 || st.Height%2 != 0 {
+				errs = append(errs, fmt.Errorf("%s: width and height must be positive even numbers", prefix))
+			}
+		case StepNormalizeAudio:
+		case StepConcat:
+			concatSteps++
+			if st.Intro == "" && st.Outro == "" {
+				errs = append(errs, fmt.Errorf("%s: concat requires an intro or an outro", prefix))
+			}
+			if i != len(s.Steps)-1 {
+				errs = append(errs, fmt.Errorf("%s: concat must be the last step", prefix))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown step type %q", prefix, st.Type))
+		}
+	}
+	if concatSteps > 1 {
+		errs = append(errs, errors.New("pipeline can define at most one concat step"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (st Step) validateOverlays(prefix string) []error {
+	var errs []error
+
+	if wm := st.Watermark; wm != nil {
+		if wm.Image == "" {
+			errs = append(errs, fmt.Errorf("%s: watermark image is required", prefix))
+		}
+		if !validPosition(wm.Position) {
+			errs = append(errs, fmt.Errorf("%s: invalid watermark position %q", prefix, wm.Position))
+		}
+		if wm.Opacity < 0 || wm.Opacity > 1 {
+			errs = append(errs, fmt.Errorf("%s: watermark opacity must be between 0 and 1", prefix))
+		}
+		if wm.Margin < 0 || wm.Width < 0 {
+			errs = append(errs, fmt.Errorf("%s: watermark margin and width must be >= 0", prefix))
+		}
+	}
+
+	for i, text := range st.Texts {
+		if strings.TrimSpace(text.Text) == "" {
+			errs = append(errs, fmt.Errorf("%s: text overlay %d is empty", prefix, i+1))
+		}
+		if !validPosition(text.Position) {
+			errs = append(errs, fmt.Errorf("%s: invalid position %q for text overlay %d", prefix, text.Position, i+1))
+		}
+		if text.Opacity < 0 || text.Opacity > 1 {
+			errs = append(errs, fmt.Errorf("%s: opacity of text overlay %d must be between 0 and 1", prefix, i+1))
+		}
+	}
+
+	return errs
+}
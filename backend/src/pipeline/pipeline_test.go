@@ -0,0 +1,201 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineSpec(t *testing.T) {
+	t.Run("Default_IsValid", func(t *testing.T) {
+		spec, err := Default()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "anb-default@v3", spec.Ref())
+		assert.Equal(t, []string{StepTrim, StepScale, StepNormalizeAudio, StepConcat}, []string{spec.Steps[0].Type, spec.Steps[1].Type, spec.Steps[2].Type, spec.Steps[3].Type})
+	})
+
+	t.Run("Parse_JSON", func(t *testing.T) {
+		data := `{"name":"short","version":3,"retries":1,"steps":[{"type":"trim","max_duration":10},{"type":"normalize_audio","retries":0}]}`
+
+		spec, err := Parse([]byte(data), ".json")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "short@v3", spec.Ref())
+		assert.Equal(t, 2, spec.MaxAttempts(spec.Steps[0]))
+		assert.Equal(t, 1, spec.MaxAttempts(spec.Steps[1]))
+	})
+
+	t.Run("Parse_DefaultRetries", func(t *testing.T) {
+		spec, err := Parse([]byte("name: x\nversion: 1\nsteps:\n  - type: trim\n    max_duration: 5\n"), ".yml")
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultRetries+1, spec.MaxAttempts(spec.Steps[0]))
+	})
+
+	t.Run("Parse_UnsupportedFormat", func(t *testing.T) {
+		_, err := Parse([]byte("name = x"), ".toml")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported")
+	})
+
+	t.Run("Validate_AggregatesErrors", func(t *testing.T) {
+		spec := &Spec{
+			Version: 0,
+			Steps: []Step{
+				{Type: StepConcat, Intro: "intro.mp4"},
+				{Type: StepScale, Width: 1281, Height: 720},
+				{Type: "blur"},
+			},
+		}
+
+		err := spec.Validate()
+
+		assert.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, "name is required")
+		assert.Contains(t, msg, "version must be >= 1")
+		assert.Contains(t, msg, "concat must be the last step")
+		assert.Contains(t, msg, "positive even numbers")
+		assert.Contains(t, msg, `unknown step type "blur"`)
+	})
+
+	t.Run("Validate_Overlays", func(t *testing.T) {
+		spec := &Spec{
+			Name:    "x",
+			Version: 1,
+			Steps: []Step{
+				{Type: StepTrim, MaxDuration: 30, Texts: []TextOverlay{{Text: "{title}"}}},
+				{Type: StepScale, Width: 1280, Height: 720,
+					Watermark: &Watermark{Position: "middle", Opacity: 2},
+					Texts:     []TextOverlay{{Text: " "}}},
+			},
+		}
+
+		err := spec.Validate()
+
+		assert.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, "only supported on scale steps")
+		assert.Contains(t, msg, "watermark image is required")
+		assert.Contains(t, msg, `invalid watermark position "middle"`)
+		assert.Contains(t, msg, "opacity must be between 0 and 1")
+		assert.Contains(t, msg, "text overlay 1 is empty")
+	})
+
+	t.Run("RenderText_ReplacesVariables", func(t *testing.T) {
+		meta := Metadata{PlayerName: "Luis Martínez", City: "Cali", Title: "Mate 360"}
+
+		assert.Equal(t, "Luis Martínez · Cali - Mate 360", RenderText("{player_name} · {city} - {title}", meta))
+	})
+
+	t.Run("Assets_IncludesBumpersLogoAndFonts", func(t *testing.T) {
+		spec, err := Default()
+
+		assert.NoError(t, err)
+		assert.Contains(t, spec.Assets(), "/app/intro/anb.mp4")
+		assert.Contains(t, spec.Assets(), "/app/assets/anb-logo.jpg")
+		assert.Contains(t, spec.Assets(), defaultFontFile)
+	})
+
+	t.Run("RetryBackoff_IsLinear", func(t *testing.T) {
+		spec := &Spec{RetryBackoffSeconds: 5}
+
+		assert.Equal(t, 5*time.Second, spec.RetryBackoff(1))
+		assert.Equal(t, 10*time.Second, spec.RetryBackoff(2))
+	})
+}
+
+func TestBuildArgs(t *testing.T) {
+	t.Run("Trim_CopiesStreams", func(t *testing.T) {
+		args, err := BuildArgs(Step{Type: StepTrim, MaxDuration: 30}, Job{Input: "in.mp4", Output: "out.mkv"})
+
+		assert.NoError(t, err)
+		joined := strings.Join(args, " ")
+		assert.Contains(t, joined, "-t 30")
+		assert.Contains(t, joined, "-c copy")
+		assert.Equal(t, "out.mkv", args[len(args)-1])
+	})
+
+	t.Run("Scale_ReencodesVideo", func(t *testing.T) {
+		args, err := BuildArgs(Step{Type: StepScale, Width: 1280, Height: 720}, Job{Input: "in.mkv", Output: "out.mkv"})
+
+		assert.NoError(t, err)
+		joined := strings.Join(args, " ")
+		assert.Contains(t, joined, "-vf scale=1280:720,setdar=16/9")
+		assert.Contains(t, joined, "-c:v libx264 -preset fast -crf 23")
+	})
+
+	t.Run("Concat_NormalizesEveryInput", func(t *testing.T) {
+		step := Step{Type: StepConcat, Intro: "intro.mp4", Outro: "outro.mp4"}
+
+		args, err := BuildArgs(step, Job{Input: "clip.mkv", Output: "final.mp4"})
+
+		assert.NoError(t, err)
+		joined := strings.Join(args, " ")
+		assert.Contains(t, joined, "-i intro.mp4 -i clip.mkv -i outro.mp4")
+		assert.Contains(t, joined, "concat=n=3:v=1:a=1[v][a]")
+		assert.Contains(t, joined, "fps=30")
+		assert.NotContains(t, joined, "-c copy")
+	})
+
+	t.Run("Concat_OnlyIntro", func(t *testing.T) {
+		args, err := BuildArgs(Step{Type: StepConcat, Intro: "intro.mp4"}, Job{Input: "clip.mkv", Output: "final.mp4"})
+
+		assert.NoError(t, err)
+		assert.Contains(t, strings.Join(args, " "), "concat=n=2:v=1:a=1")
+	})
+
+	t.Run("NormalizeAudio_DefaultTargets", func(t *testing.T) {
+		args, err := BuildArgs(Step{Type: StepNormalizeAudio}, Job{Input: "in.mkv", Output: "out.mkv"})
+
+		assert.NoError(t, err)
+		assert.Contains(t, args, "loudnorm=I=-16:TP=-1.5:LRA=11")
+	})
+
+	t.Run("Scale_AppliesOverlaysInSamePass", func(t *testing.T) {
+		step := Step{
+			Type:   StepScale,
+			Width:  1280,
+			Height: 720,
+			Watermark: &Watermark{
+				Image:    "logo.png",
+				Position: PositionBottomRight,
+				Opacity:  0.5,
+				Margin:   10,
+				Width:    120,
+			},
+			Texts: []TextOverlay{{Text: "{player_name}", Position: PositionBottomLeft, Box: true}},
+		}
+
+		args, err := BuildArgs(step, Job{Input: "in.mkv", Output: "out.mkv", TextFiles: []string{"/tmp/t1.txt"}})
+
+		assert.NoError(t, err)
+		joined := strings.Join(args, " ")
+		assert.Contains(t, joined, "-i in.mkv -i logo.png")
+		assert.Contains(t, joined, "[0:v]scale=1280:720,setdar=16/9[base]")
+		assert.Contains(t, joined, "colorchannelmixer=aa=0.5")
+		assert.Contains(t, joined, "[base][logo]overlay=W-w-10:H-h-10[wm]")
+		assert.Contains(t, joined, "[wm]drawtext=textfile='/tmp/t1.txt':expansion=none")
+		assert.Contains(t, joined, "x=24:y=h-th-24:box=1")
+		assert.Contains(t, joined, "-map [txt0] -map 0:a:0?")
+		assert.NotContains(t, joined, "-vf")
+	})
+
+	t.Run("Scale_MissingTextFiles", func(t *testing.T) {
+		step := Step{Type: StepScale, Width: 1280, Height: 720, Texts: []TextOverlay{{Text: "{title}"}}}
+
+		_, err := BuildArgs(step, Job{Input: "in.mkv", Output: "out.mkv"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownStep", func(t *testing.T) {
+		_, err := BuildArgs(Step{Type: "blur"}, Job{Input: "in", Output: "out"})
+
+		assert.Error(t, err)
+	})
+}
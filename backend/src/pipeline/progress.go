@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressArgs son las opciones globales que hacen que FFmpeg escriba su avance en
+// stdout como bloques "clave=valor" terminados en "progress=continue|end"
+func ProgressArgs() []string {
+	return []string{"-progress", "pipe:1", "-nostats"}
+}
+
+// Progress es un bloque de la salida de -progress
+type Progress struct {
+	// OutTime es la posición ya codificada de la salida
+	OutTime time.Duration
+	// Done indica el último bloque (progress=end)
+	Done bool
+}
+
+// Fraction devuelve el avance entre 0 y 1 respecto a la duración esperada de la salida
+func (p Progress) Fraction(expected time.Duration) float64 {
+	if p.Done {
+		return 1
+	}
+	if expected <= 0 || p.OutTime <= 0 {
+		return 0
+	}
+	return min(float64(p.OutTime)/float64(expected), 1)
+}
+
+// ScanProgress lee la salida de -progress y llama a fn al cerrar cada bloque.
+// Termina cuando r se cierra.
+func ScanProgress(r io.Reader, fn func(Progress)) error {
+	var current Progress
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		// out_time_us y out_time_ms están en microsegundos (out_time_ms es un nombre histórico)
+		case "out_time_us", "out_time_ms":
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+				current.OutTime = time.Duration(us) * time.Microsecond
+			}
+		case "progress":
+			current.Done = value == "end"
+			fn(current)
+		}
+	}
+	return scanner.Err()
+}
+
+// ExpectedDuration estima la duración de la salida de un paso a partir de la del
+// clip de entrada y, para concat, la de la intro y el cierre
+func ExpectedDuration(st Step, input float64, intro, outro float64) time.Duration {
+	seconds := input
+	switch st.Type {
+	case StepTrim:
+		if st.MaxDuration > 0 && seconds > st.MaxDuration {
+			seconds = st.MaxDuration
+		}
+	case StepConcat:
+		seconds += intro + outro
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
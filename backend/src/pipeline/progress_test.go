@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const progressOutput = `frame=120
+fps=60.00
+out_time_us=4000000
+out_time_ms=4000000
+out_time=00:00:04.000000
+speed=2.0x
+progress=continue
+frame=240
+out_time_us=8000000
+out_time_ms=8000000
+progress=continue
+frame=300
+out_time_us=N/A
+progress=end
+`
+
+func TestProgress(t *testing.T) {
+	t.Run("ScanProgress_EmitsEachBlock", func(t *testing.T) {
+		var blocks []Progress
+
+		err := ScanProgress(strings.NewReader(progressOutput), func(p Progress) { blocks = append(blocks, p) })
+
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 3)
+		assert.Equal(t, 4*time.Second, blocks[0].OutTime)
+		assert.Equal(t, 8*time.Second, blocks[1].OutTime)
+		assert.False(t, blocks[1].Done)
+		assert.True(t, blocks[2].Done)
+	})
+
+	t.Run("Fraction", func(t *testing.T) {
+		assert.Equal(t, 0.25, Progress{OutTime: 5 * time.Second}.Fraction(20*time.Second))
+		assert.Equal(t, 1.0, Progress{OutTime: 25 * time.Second}.Fraction(20*time.Second))
+		assert.Equal(t, 0.0, Progress{OutTime: 5 * time.Second}.Fraction(0))
+		assert.Equal(t, 1.0, Progress{Done: true}.Fraction(0))
+	})
+
+	t.Run("ExpectedDuration", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, ExpectedDuration(Step{Type: StepTrim, MaxDuration: 30}, 45, 0, 0))
+		assert.Equal(t, 12*time.Second, ExpectedDuration(Step{Type: StepTrim, MaxDuration: 30}, 12, 0, 0))
+		assert.Equal(t, 40*time.Second, ExpectedDuration(Step{Type: StepConcat}, 30, 5, 5))
+		assert.Equal(t, 30*time.Second, ExpectedDuration(Step{Type: StepScale}, 30, 5, 5))
+	})
+}
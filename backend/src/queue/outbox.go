@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"anb-app/src/outbox"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TopicTasks es el topic del outbox cuyos mensajes se publican en la cola de tareas
+const TopicTasks = "queue"
+
+// EnqueueRequest son los argumentos de EnqueueTask guardados en el outbox
+type EnqueueRequest struct {
+	TaskType       string      `json:"task_type"`
+	Payload        TaskPayload `json:"payload"`
+	MaxRetry       int         `json:"max_retry"`
+	TimeoutSeconds int         `json:"timeout_seconds"`
+}
+
+// NewOutboxMessage prepara el encolado de una tarea para registrarlo con outbox.Add
+// en la misma transacción que el cambio que la origina
+func NewOutboxMessage(taskType string, payload TaskPayload, maxRetry int, timeout time.Duration) (outbox.Message, error) {
+	return outbox.New(TopicTasks, taskType, EnqueueRequest{
+		TaskType:       taskType,
+		Payload:        payload,
+		MaxRetry:       maxRetry,
+		TimeoutSeconds: int(timeout.Seconds()),
+	})
+}
+
+// OutboxHandler publica en client los mensajes de TopicTasks. Si el mensaje no se
+// llega a marcar como enviado se publica otra vez, así que el worker debe tolerar
+// recibir la misma tarea más de una vez.
+func OutboxHandler(client QueueClient) outbox.Handler {
+	return func(ctx context.Context, db *gorm.DB, msg *outbox.Message) error {
+		var req EnqueueRequest
+		if err := json.Unmarshal([]byte(msg.Payload), &req); err != nil {
+			return fmt.Errorf("invalid enqueue request: %w", err)
+		}
+		_, err := client.EnqueueTask(ctx, req.TaskType, req.Payload, req.MaxRetry, time.Duration(req.TimeoutSeconds)*time.Second)
+		return err
+	}
+}
@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"anb-app/src/outbox"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingClient guarda las tareas encoladas
+type recordingClient struct {
+	taskType string
+	payload  TaskPayload
+	maxRetry int
+	timeout  time.Duration
+	err      error
+}
+
+func (c *recordingClient) EnqueueTask(ctx context.Context, taskType string, payload TaskPayload, maxRetry int, timeout time.Duration) (string, error) {
+	c.taskType, c.payload, c.maxRetry, c.timeout = taskType, payload, maxRetry, timeout
+	return "msg-1", c.err
+}
+
+func (c *recordingClient) Close() error { return nil }
+
+func TestOutboxHandler(t *testing.T) {
+	t.Run("PublishesRecordedTask", func(t *testing.T) {
+		msg, err := NewOutboxMessage("task:video:process", TaskPayload{VideoID: 9, TaskID: "abc"}, 5, 10*time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, TopicTasks, msg.Topic)
+
+		client := &recordingClient{}
+		err = OutboxHandler(client)(context.Background(), nil, &msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "task:video:process", client.taskType)
+		assert.Equal(t, TaskPayload{VideoID: 9, TaskID: "abc"}, client.payload)
+		assert.Equal(t, 5, client.maxRetry)
+		assert.Equal(t, 10*time.Minute, client.timeout)
+	})
+
+	t.Run("QueueErrorIsReturned", func(t *testing.T) {
+		msg, _ := NewOutboxMessage("task:video:process", TaskPayload{VideoID: 9}, 5, time.Minute)
+		client := &recordingClient{err: errors.New("sqs unavailable")}
+
+		err := OutboxHandler(client)(context.Background(), nil, &msg)
+
+		assert.EqualError(t, err, "sqs unavailable")
+	})
+
+	t.Run("InvalidPayload", func(t *testing.T) {
+		msg := outbox.Message{Topic: TopicTasks, Payload: "not json"}
+
+		err := OutboxHandler(&recordingClient{})(context.Background(), nil, &msg)
+
+		assert.Error(t, err)
+	})
+}
@@ -8,6 +8,8 @@ import (
 // TaskPayload representa el payload de una tarea
 type TaskPayload struct {
 	VideoID uint `json:"video_id"`
+	// TaskID identifica el registro de processing_jobs que el worker actualiza
+	TaskID string `json:"task_id,omitempty"`
 }
 
 // Task representa una tarea en la cola
@@ -15,6 +17,12 @@ type Task struct {
 	ID      string
 	Type    string
 	Payload TaskPayload
+	// Attempt es el número de entrega de la tarea (1 en el primer intento)
+	Attempt int
+	// MaxRetry es el número de reintentos permitidos tras el primer intento
+	MaxRetry int
+	// EnqueuedAt es el momento en que se encoló, para medir la espera en cola
+	EnqueuedAt time.Time
 }
 
 // QueueClient interfaz para abstraer el sistema de colas
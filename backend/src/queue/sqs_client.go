@@ -79,6 +79,18 @@ func (s *SQSClient) EnqueueTask(ctx context.Context, taskType string, payload Ta
 	return messageID, nil
 }
 
+// Ping comprueba que la cola exista y sea accesible consultando sus atributos
+func (s *SQSClient) Ping(ctx context.Context) error {
+	_, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(s.queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get SQS queue attributes: %w", err)
+	}
+	return nil
+}
+
 // Close cierra el cliente (SQS no requiere cerrar conexión explícitamente)
 func (s *SQSClient) Close() error {
 	return nil
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -82,9 +83,14 @@ func (s *SQSConsumer) ReceiveTask(ctx context.Context) (*Task, error) {
 
 	// Crear la tarea
 	task := &Task{
-		ID:      aws.ToString(message.MessageId),
-		Type:    msgPayload.TaskType,
-		Payload: msgPayload.Payload,
+		ID:       aws.ToString(message.MessageId),
+		Type:     msgPayload.TaskType,
+		Payload:  msgPayload.Payload,
+		Attempt:  1,
+		MaxRetry: msgPayload.MaxRetry,
+	}
+	if msgPayload.CreatedAt > 0 {
+		task.EnqueuedAt = time.Unix(msgPayload.CreatedAt, 0)
 	}
 
 	// Guardar el receipt handle para poder completar/fallar la tarea después
@@ -94,6 +100,9 @@ func (s *SQSConsumer) ReceiveTask(ctx context.Context) (*Task, error) {
 	retryCount := 0
 	if receiveCount, ok := message.Attributes["ApproximateReceiveCount"]; ok {
 		retryCount, _ = strconv.Atoi(receiveCount)
+		if retryCount > 0 {
+			task.Attempt = retryCount
+		}
 		retryCount-- // Restar 1 porque el primer intento no es un retry
 	}
 
@@ -151,6 +160,18 @@ func (s *SQSConsumer) FailTask(ctx context.Context, task *Task) error {
 	return nil
 }
 
+// Ping comprueba que la cola exista y sea accesible consultando sus atributos
+func (s *SQSConsumer) Ping(ctx context.Context) error {
+	_, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(s.queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get SQS queue attributes: %w", err)
+	}
+	return nil
+}
+
 // Close cierra el cliente
 func (s *SQSConsumer) Close() error {
 	return nil
@@ -0,0 +1,217 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"anb-app/src/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAllowedHeaders y DefaultExposedHeaders cubren el token, las descargas con Range y
+// las peticiones condicionales
+var (
+	DefaultAllowedHeaders = []string{"Content-Type", "Authorization", "Accept-Language", "Range", "If-None-Match", "If-Range"}
+	DefaultExposedHeaders = []string{"Content-Range", "Content-Disposition", "Content-Language", "ETag", "Accept-Ranges", "Retry-After"}
+)
+
+var errOriginNotAllowed = apperr.Forbidden("origin_not_allowed", "the origin is not allowed to call this API")
+
+// CORSConfig configura qué orígenes pueden llamar a la API desde un navegador
+type CORSConfig struct {
+	// AllowedOrigins son orígenes exactos (https://anb.com), con comodín de subdominio
+	// (https://*.anb.com, que no incluye https://anb.com) o "*" para cualquiera
+	AllowedOrigins   []string
+	AllowCredentials bool
+	// MaxAge es cuánto guarda el navegador la respuesta al preflight; 0 no envía el header
+	MaxAge         time.Duration
+	AllowedHeaders []string
+	ExposedHeaders []string
+}
+
+type corsPolicy struct {
+	config    CORSConfig
+	anyOrigin bool
+	exact     map[string]bool
+	wildcards []wildcardOrigin
+
+	router     *gin.Engine
+	routesOnce sync.Once
+	routes     []routePattern
+}
+
+// wildcardOrigin es https://*.anb.com: el origen debe empezar por prefix y terminar en suffix
+type wildcardOrigin struct {
+	prefix, suffix string
+}
+
+type routePattern struct {
+	method  string
+	pattern *regexp.Regexp
+}
+
+// CORS responde los preflight y agrega los headers CORS a las peticiones de orígenes permitidos.
+// Se registra con router.Use antes que las rutas. Como gin no tiene rutas OPTIONS, los preflight
+// llegan sin ruta: los métodos permitidos salen de las rutas registradas para ese path y un
+// preflight a un path que no existe sigue hasta el 404. Un OPTIONS que no es preflight tampoco
+// se intercepta.
+func CORS(router *gin.Engine, config CORSConfig) (gin.HandlerFunc, error) {
+	policy := &corsPolicy{config: config, exact: map[string]bool{}, router: router}
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			policy.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://*.")
+			if err := checkOrigin(scheme + "://" + host); err != nil {
+				return nil, err
+			}
+			policy.wildcards = append(policy.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: "." + host})
+		default:
+			if err := checkOrigin(origin); err != nil {
+				return nil, err
+			}
+			policy.exact[origin] = true
+		}
+	}
+	// El navegador rechaza "*" con credenciales, y reflejar cualquier origen con credenciales
+	// abriría la API a cualquier sitio
+	if policy.anyOrigin && config.AllowCredentials {
+		return nil, fmt.Errorf("cors: allowed origin * cannot be combined with credentials")
+	}
+	return policy.handle, nil
+}
+
+// checkOrigin exige un origen completo: esquema y host, sin ruta
+func checkOrigin(origin string) error {
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" || parsed.RawQuery != "" {
+		return fmt.Errorf("cors: invalid allowed origin %q (expected scheme://host[:port])", origin)
+	}
+	return nil
+}
+
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.exact[origin] {
+		return true
+	}
+	for _, wildcard := range p.wildcards {
+		if strings.HasPrefix(origin, wildcard.prefix) && strings.HasSuffix(origin, wildcard.suffix) {
+			subdomain := origin[len(wildcard.prefix) : len(origin)-len(wildcard.suffix)]
+			if subdomain != "" && !strings.ContainsAny(subdomain, "/:@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) handle(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+	if !p.anyOrigin {
+		// La respuesta cambia según el origen; las cachés intermedias no deben mezclarlas
+		c.Writer.Header().Add("Vary", "Origin")
+	}
+	if origin == "" {
+		c.Next()
+		return
+	}
+
+	if !preflight {
+		if p.allowed(origin) {
+			p.setOrigin(c, origin)
+			if len(p.config.ExposedHeaders) > 0 {
+				c.Header("Access-Control-Expose-Headers", strings.Join(p.config.ExposedHeaders, ", "))
+			}
+		}
+		c.Next()
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+	c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+	methods := p.methodsFor(c.Request.URL.Path)
+	if len(methods) == 0 {
+		// Path sin rutas: el preflight recibe el mismo 404 que cualquier otra petición
+		c.Next()
+		return
+	}
+	if !p.allowed(origin) {
+		apperr.Render(c, errOriginNotAllowed)
+		return
+	}
+
+	p.setOrigin(c, origin)
+	c.Header("Access-Control-Allow-Methods", strings.Join(append(methods, http.MethodOptions), ", "))
+	if len(p.config.AllowedHeaders) > 0 {
+		c.Header("Access-Control-Allow-Headers", strings.Join(p.config.AllowedHeaders, ", "))
+	}
+	if p.config.MaxAge > 0 {
+		c.Header("Access-Control-Max-Age", strconv.Itoa(int(p.config.MaxAge.Seconds())))
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+func (p *corsPolicy) setOrigin(c *gin.Context, origin string) {
+	if p.anyOrigin {
+		c.Header("Access-Control-Allow-Origin", "*")
+		return
+	}
+	c.Header("Access-Control-Allow-Origin", origin)
+	if p.config.AllowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// methodsFor devuelve los métodos registrados para un path. Las rutas se leen en el primer
+// preflight, cuando el router ya tiene todas registradas.
+func (p *corsPolicy) methodsFor(path string) []string {
+	p.routesOnce.Do(func() {
+		for _, route := range p.router.Routes() {
+			p.routes = append(p.routes, routePattern{method: route.Method, pattern: routeRegexp(route.Path)})
+		}
+	})
+
+	var methods []string
+	for _, route := range p.routes {
+		if route.pattern.MatchString(path) && !slices.Contains(methods, route.method) {
+			methods = append(methods, route.method)
+		}
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+var routeParam = regexp.MustCompile(`[:*][A-Za-z0-9_]+`)
+
+// routeRegexp convierte la sintaxis de gin: /videos/:video_id es /videos/[^/]+ y /media/*path
+// acepta cualquier resto
+func routeRegexp(path string) *regexp.Regexp {
+	var pattern strings.Builder
+	last := 0
+	for _, match := range routeParam.FindAllStringIndex(path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(path[last:match[0]]))
+		if path[match[0]] == '*' {
+			pattern.WriteString(".*")
+		} else {
+			pattern.WriteString("[^/]+")
+		}
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]))
+	return regexp.MustCompile("^" + pattern.String() + "$")
+}
@@ -0,0 +1,160 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCORSRouter(t *testing.T, config CORSConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	cors, err := CORS(router, config)
+	require.NoError(t, err)
+	router.Use(cors)
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	router.GET("/api/v1/videos/:video_id", ok)
+	router.DELETE("/api/v1/videos/:video_id", ok)
+	router.GET("/media/*path", ok)
+	return router
+}
+
+func serve(router *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSConfig(t *testing.T) {
+	router := gin.New()
+	for _, origin := range []string{"anb.com", "https://anb.com/app", "https://*."} {
+		_, err := CORS(router, CORSConfig{AllowedOrigins: []string{origin}})
+		assert.Error(t, err, origin)
+	}
+	_, err := CORS(router, CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	assert.ErrorContains(t, err, "credentials")
+	_, err = CORS(router, CORSConfig{AllowedOrigins: []string{"https://anb.com/", "https://*.anb.com", "http://localhost:4200"}, AllowCredentials: true})
+	assert.NoError(t, err)
+}
+
+func TestCORSOrigins(t *testing.T) {
+	router := newCORSRouter(t, CORSConfig{
+		AllowedOrigins:   []string{"https://anb.com", "https://*.anb.com"},
+		AllowCredentials: true,
+		ExposedHeaders:   DefaultExposedHeaders,
+	})
+
+	cases := map[string]bool{
+		"https://anb.com":          true,
+		"https://APP.anb.com":      true,
+		"https://a.b.anb.com":      true,
+		"http://app.anb.com":       false,
+		"https://evil-anb.com":     false,
+		"https://anb.com.evil.com": false,
+		"https://.anb.com":         false,
+	}
+	for origin, allowed := range cases {
+		w := serve(router, http.MethodGet, "/api/v1/videos/1", map[string]string{"Origin": origin})
+		assert.Equal(t, http.StatusOK, w.Code, origin)
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+		if !allowed {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+			continue
+		}
+		assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "Content-Range")
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	router := newCORSRouter(t, CORSConfig{AllowedOrigins: []string{"*"}})
+
+	w := serve(router, http.MethodGet, "/api/v1/videos/1", map[string]string{"Origin": "https://otro.com"})
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, w.Header().Values("Vary"))
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter(t, CORSConfig{
+		AllowedOrigins: []string{"https://anb.com"},
+		MaxAge:         10 * time.Minute,
+		AllowedHeaders: DefaultAllowedHeaders,
+	})
+	preflight := func(origin string) map[string]string {
+		return map[string]string{"Origin": origin, "Access-Control-Request-Method": http.MethodDelete}
+	}
+
+	t.Run("RegisteredPath", func(t *testing.T) {
+		w := serve(router, http.MethodOptions, "/api/v1/videos/7", preflight("https://anb.com"))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://anb.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "DELETE, GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+		assert.Contains(t, w.Header().Values("Vary"), "Access-Control-Request-Method")
+	})
+
+	t.Run("WildcardRoute", func(t *testing.T) {
+		w := serve(router, http.MethodOptions, "/media/processed/1.mp4", preflight("https://anb.com"))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("UnknownPath", func(t *testing.T) {
+		w := serve(router, http.MethodOptions, "/api/v1/nothing", preflight("https://anb.com"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("OriginNotAllowed", func(t *testing.T) {
+		w := serve(router, http.MethodOptions, "/api/v1/videos/7", preflight("https://evil.com"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "origin_not_allowed")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("NotAPreflight", func(t *testing.T) {
+		w := serve(router, http.MethodOptions, "/api/v1/videos/7", map[string]string{"Origin": "https://anb.com"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SecurityHeaders(HeadersConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameDeny:             true,
+		ContentSecurityPolicy: DefaultContentSecurityPolicy,
+	}))
+	router.GET("/json", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	router.GET("/html", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<h1>ANB</h1>"))
+	})
+
+	w := serve(router, http.MethodGet, "/json", nil)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	// HSTS solo por HTTPS
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	w = serve(router, http.MethodGet, "/html", map[string]string{"X-Forwarded-Proto": "https"})
+	assert.Equal(t, DefaultContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
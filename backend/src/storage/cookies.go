@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CloudFrontCookieSigner entrega URLs estables (https://domain/key, sin query) para que el
+// CDN y los navegadores las cacheen. El acceso lo dan las cookies firmadas CloudFront-Policy,
+// CloudFront-Signature y CloudFront-Key-Pair-Id, con una política que cubre todo prefix.
+type CloudFrontCookieSigner struct {
+	domain       string
+	keyPairID    string
+	key          *rsa.PrivateKey
+	prefix       string
+	cookieDomain string
+	ttl          time.Duration
+	now          func() time.Time
+}
+
+// NewCloudFrontCookieSigner recibe el dominio de la distribución, el par de claves, el
+// prefijo de claves que cubren las cookies (p. ej. processed/) y el dominio de las cookies,
+// que debe abarcar a la API y al CDN (p. ej. .anb.com)
+func NewCloudFrontCookieSigner(domain, keyPairID string, privateKeyPEM []byte, prefix, cookieDomain string, ttl time.Duration) (*CloudFrontCookieSigner, error) {
+	if domain == "" || keyPairID == "" {
+		return nil, errors.New("cloudfront signer requires a domain and a key pair ID")
+	}
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudFrontCookieSigner{
+		domain:       strings.TrimSuffix(strings.TrimPrefix(domain, "https://"), "/"),
+		keyPairID:    keyPairID,
+		key:          key,
+		prefix:       prefix,
+		cookieDomain: cookieDomain,
+		ttl:          ttl,
+		now:          time.Now,
+	}, nil
+}
+
+// Sign no firma la URL: es la misma para todos los usuarios y en todas las peticiones
+func (s *CloudFrontCookieSigner) Sign(key string) (SignedURL, error) {
+	resource := (&url.URL{Scheme: "https", Host: s.domain, Path: "/" + key}).String()
+	return SignedURL{URL: resource, ExpiresAt: s.now().Add(s.ttl)}, nil
+}
+
+// Cookies firma una política personalizada sobre https://domain/prefix* y devuelve las
+// tres cookies que CloudFront verifica en el borde
+func (s *CloudFrontCookieSigner) Cookies() ([]*http.Cookie, time.Time, error) {
+	expiresAt := s.now().Add(s.ttl).Truncate(time.Second)
+	resource := "https://" + s.domain + "/" + s.prefix + "*"
+
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"%s","Condition":{"DateLessThan":{"AWS:EpochTime":%d}}}]}`,
+		resource, expiresAt.Unix())
+	digest := sha1.Sum([]byte(policy))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, digest[:])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to sign cloudfront cookies: %w", err)
+	}
+
+	values := []struct{ name, value string }{
+		{"CloudFront-Policy", cloudFrontEncode([]byte(policy))},
+		{"CloudFront-Signature", cloudFrontEncode(signature)},
+		{"CloudFront-Key-Pair-Id", s.keyPairID},
+	}
+	cookies := make([]*http.Cookie, 0, len(values))
+	for _, v := range values {
+		cookies = append(cookies, &http.Cookie{
+			Name:     v.name,
+			Value:    v.value,
+			Domain:   s.cookieDomain,
+			Path:     "/",
+			Expires:  expiresAt,
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return cookies, expiresAt, nil
+}
+
+// prefixURLSigner firma con signer las claves bajo prefix y con fallback el resto
+type prefixURLSigner struct {
+	prefix   string
+	signer   URLSigner
+	fallback URLSigner
+}
+
+// NewPrefixURLSigner permite entregar los archivos públicos (processed/) por el CDN y
+// mantener las URLs prefirmadas para los originales
+func NewPrefixURLSigner(prefix string, signer, fallback URLSigner) URLSigner {
+	return &prefixURLSigner{prefix: prefix, signer: signer, fallback: fallback}
+}
+
+func (s *prefixURLSigner) Sign(key string) (SignedURL, error) {
+	if strings.HasPrefix(key, s.prefix) {
+		return s.signer.Sign(key)
+	}
+	return s.fallback.Sign(key)
+}
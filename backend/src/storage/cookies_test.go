@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudFrontCookieSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	signer, err := NewCloudFrontCookieSigner("media.anb.com", "K2JCJMDEHXQW5F", keyPEM, "processed/", ".anb.com", time.Hour)
+	require.NoError(t, err)
+	signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	t.Run("StableURL", func(t *testing.T) {
+		first, _ := signer.Sign("processed/1.mp4")
+		signer.now = func() time.Time { return time.Unix(1700000500, 0) }
+		second, _ := signer.Sign("processed/1.mp4")
+
+		assert.Equal(t, "https://media.anb.com/processed/1.mp4", first.URL)
+		assert.Equal(t, first.URL, second.URL)
+	})
+
+	t.Run("CookiesCoverPrefix", func(t *testing.T) {
+		signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+		cookies, expiresAt, err := signer.Cookies()
+		require.NoError(t, err)
+		require.Len(t, cookies, 3)
+		assert.Equal(t, time.Unix(1700003600, 0), expiresAt)
+
+		values := map[string]string{}
+		for _, cookie := range cookies {
+			values[cookie.Name] = cookie.Value
+			assert.Equal(t, ".anb.com", cookie.Domain)
+			assert.True(t, cookie.Secure)
+			assert.True(t, cookie.HttpOnly)
+		}
+		assert.Equal(t, "K2JCJMDEHXQW5F", values["CloudFront-Key-Pair-Id"])
+
+		decode := strings.NewReplacer("-", "+", "_", "=", "~", "/")
+		policy, err := base64.StdEncoding.DecodeString(decode.Replace(values["CloudFront-Policy"]))
+		require.NoError(t, err)
+		assert.Contains(t, string(policy), `"Resource":"https://media.anb.com/processed/*"`)
+		assert.Contains(t, string(policy), `"AWS:EpochTime":1700003600`)
+
+		signature, err := base64.StdEncoding.DecodeString(decode.Replace(values["CloudFront-Signature"]))
+		require.NoError(t, err)
+		digest := sha1.Sum(policy)
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, digest[:], signature))
+	})
+}
+
+func TestPrefixURLSigner(t *testing.T) {
+	cdn := &countingSigner{ttl: time.Hour, now: time.Now()}
+	presigned := &countingSigner{ttl: time.Hour, now: time.Now()}
+	signer := NewPrefixURLSigner("processed/", cdn, presigned)
+
+	signer.Sign("processed/1.mp4")
+	signer.Sign("originals/1.mp4")
+
+	assert.Equal(t, 1, cdn.calls)
+	assert.Equal(t, 1, presigned.calls)
+}
+
+func TestLocalStorageServiceRejectsTraversal(t *testing.T) {
+	store, err := NewLocalStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../secret", "processed/../../secret", "/processed/1.mp4", ""} {
+		_, err := store.Path(key)
+		assert.Error(t, err, key)
+	}
+	_, err = store.Path("processed/1.mp4")
+	assert.NoError(t, err)
+}
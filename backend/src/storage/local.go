@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorageService guarda los objetos en un directorio con la misma estructura de
+// claves que el bucket. Sirve para desarrollo y pruebas junto con el servidor de media local.
+type LocalStorageService struct {
+	root string
+}
+
+func NewLocalStorageService(root string) (*LocalStorageService, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage dir: %w", err)
+	}
+	return &LocalStorageService{root: root}, nil
+}
+
+// Path devuelve la ruta en disco de una clave; rechaza claves que salen del directorio
+func (s *LocalStorageService) Path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "\\") || strings.TrimPrefix(clean, "/") != key {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+func (s *LocalStorageService) Upload(file multipart.File, key string) error {
+	path, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to upload to local storage: %w", err)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to upload to local storage: %w", err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, file); err != nil {
+		return fmt.Errorf("failed to upload to local storage: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorageService) Delete(key string) error {
+	path, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete from local storage: %w", err)
+	}
+	return nil
+}
+
+// GetPresignedURL no firma nada: devuelve la ruta local. La entrega pública con
+// firma la hace el servidor de media (ver el paquete media).
+func (s *LocalStorageService) GetPresignedURL(key string, _ time.Duration) (string, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + filepath.ToSlash(path), nil
+}
+
+// Open abre el objeto para leerlo
+func (s *LocalStorageService) Open(key string) (*os.File, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalStorageService) HeadObject(_ context.Context, key string) (ObjectInfo, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		if err == nil || errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat local object: %w", err)
+	}
+	// Igual que S3 para un objeto sin cambios: el ETag cambia si cambia el archivo
+	return ObjectInfo{
+		Size:         info.Size(),
+		ETag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorageService) GetObject(_ context.Context, key string, offset int64) (io.ReadCloser, error) {
+	file, err := s.Open(key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *LocalStorageService) Ping(_ context.Context) error {
+	info, err := os.Stat(s.root)
+	if err != nil {
+		return fmt.Errorf("failed to access local storage: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("local storage %s is not a directory", s.root)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SignedURL es una URL firmada y el instante en que deja de ser válida
+type SignedURL struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// URLSigner firma el acceso de lectura a un objeto del bucket
+type URLSigner interface {
+	Sign(key string) (SignedURL, error)
+}
+
+// s3URLSigner firma con URLs prefirmadas de S3
+type s3URLSigner struct {
+	storage StorageService
+	ttl     time.Duration
+}
+
+func NewS3URLSigner(storage StorageService, ttl time.Duration) URLSigner {
+	return &s3URLSigner{storage: storage, ttl: ttl}
+}
+
+func (s *s3URLSigner) Sign(key string) (SignedURL, error) {
+	expiresAt := time.Now().Add(s.ttl)
+	signed, err := s.storage.GetPresignedURL(key, s.ttl)
+	if err != nil {
+		return SignedURL{}, err
+	}
+	return SignedURL{URL: signed, ExpiresAt: expiresAt}, nil
+}
+
+// cloudFrontURLSigner firma URLs de una distribución de CloudFront con política
+// predefinida (canned policy): Expires, Signature y Key-Pair-Id en la query
+type cloudFrontURLSigner struct {
+	domain    string
+	keyPairID string
+	key       *rsa.PrivateKey
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewCloudFrontURLSigner recibe el dominio de la distribución (p. ej. d111111abcdef8.cloudfront.net),
+// el ID del par de claves público registrado en CloudFront y la clave privada en PEM
+func NewCloudFrontURLSigner(domain, keyPairID string, privateKeyPEM []byte, ttl time.Duration) (URLSigner, error) {
+	if domain == "" || keyPairID == "" {
+		return nil, errors.New("cloudfront signer requires a domain and a key pair ID")
+	}
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudFrontURLSigner{
+		domain:    strings.TrimSuffix(strings.TrimPrefix(domain, "https://"), "/"),
+		keyPairID: keyPairID,
+		key:       key,
+		ttl:       ttl,
+		now:       time.Now,
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("cloudfront private key is not valid PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloudfront private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("cloudfront private key must be RSA")
+	}
+	return key, nil
+}
+
+func (s *cloudFrontURLSigner) Sign(key string) (SignedURL, error) {
+	expiresAt := s.now().Add(s.ttl).Truncate(time.Second)
+	resource := (&url.URL{Scheme: "https", Host: s.domain, Path: "/" + key}).String()
+
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"%s","Condition":{"DateLessThan":{"AWS:EpochTime":%d}}}]}`,
+		resource, expiresAt.Unix())
+	digest := sha1.Sum([]byte(policy))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, digest[:])
+	if err != nil {
+		return SignedURL{}, fmt.Errorf("failed to sign cloudfront URL: %w", err)
+	}
+
+	query := fmt.Sprintf("Expires=%d&Signature=%s&Key-Pair-Id=%s",
+		expiresAt.Unix(), cloudFrontEncode(signature), url.QueryEscape(s.keyPairID))
+	return SignedURL{URL: resource + "?" + query, ExpiresAt: expiresAt}, nil
+}
+
+// cloudFrontEncode es base64 con los reemplazos que exige CloudFront para usarlo en la query
+func cloudFrontEncode(data []byte) string {
+	return strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(data))
+}
+
+// Máximo de URLs guardadas; al llenarse se descartan las vencidas o, si no hay, todas
+const maxCachedURLs = 10000
+
+// CachingURLSigner reutiliza la URL firmada de cada clave hasta refreshBefore antes
+// de que venza, para que los listados no vuelvan a firmar lo mismo en cada petición
+type CachingURLSigner struct {
+	signer        URLSigner
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]SignedURL
+}
+
+func NewCachingURLSigner(signer URLSigner, refreshBefore time.Duration) *CachingURLSigner {
+	return &CachingURLSigner{
+		signer:        signer,
+		refreshBefore: refreshBefore,
+		now:           time.Now,
+		entries:       make(map[string]SignedURL),
+	}
+}
+
+func (c *CachingURLSigner) Sign(key string) (SignedURL, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Add(c.refreshBefore).Before(cached.ExpiresAt) {
+		return cached, nil
+	}
+
+	signed, err := c.signer.Sign(key)
+	if err != nil {
+		return SignedURL{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedURLs {
+		c.evict()
+	}
+	c.entries[key] = signed
+	return signed, nil
+}
+
+// evict descarta las URLs vencidas; si no libera espacio vacía la caché
+func (c *CachingURLSigner) evict() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.ExpiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= maxCachedURLs {
+		c.entries = make(map[string]SignedURL)
+	}
+}
+
+// SignAll firma una sola vez cada clave no vacía y devuelve clave → URL. Las claves que
+// no se pudieron firmar se registran y quedan fuera del mapa.
+func SignAll(signer URLSigner, keys ...string) map[string]string {
+	urls := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, done := urls[key]; done {
+			continue
+		}
+		signed, err := signer.Sign(key)
+		if err != nil {
+			log.Printf("Error signing URL for %s: %v", key, err)
+			continue
+		}
+		urls[key] = signed.URL
+	}
+	return urls
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSigner firma con un contador para distinguir cada firma nueva
+type countingSigner struct {
+	calls int
+	ttl   time.Duration
+	now   time.Time
+}
+
+func (s *countingSigner) Sign(key string) (SignedURL, error) {
+	s.calls++
+	if key == "broken" {
+		return SignedURL{}, errors.New("cannot sign")
+	}
+	return SignedURL{URL: fmt.Sprintf("https://signed/%s?v=%d", key, s.calls), ExpiresAt: s.now.Add(s.ttl)}, nil
+}
+
+func TestCachingURLSigner(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	inner := &countingSigner{ttl: time.Hour, now: now}
+	cache := NewCachingURLSigner(inner, 15*time.Minute)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.Sign("processed/1.mp4")
+	require.NoError(t, err)
+
+	// Dentro de la vigencia se reutiliza la misma firma
+	now = now.Add(40 * time.Minute)
+	second, _ := cache.Sign("processed/1.mp4")
+	assert.Equal(t, first.URL, second.URL)
+	assert.Equal(t, 1, inner.calls)
+
+	// A menos de refreshBefore del vencimiento se vuelve a firmar
+	now = now.Add(10 * time.Minute)
+	inner.now = now
+	third, _ := cache.Sign("processed/1.mp4")
+	assert.NotEqual(t, first.URL, third.URL)
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestSignAll(t *testing.T) {
+	inner := &countingSigner{ttl: time.Hour, now: time.Now()}
+
+	urls := SignAll(inner, "a.jpg", "", "a.jpg", "b.mp4", "broken")
+
+	assert.Len(t, urls, 2)
+	assert.Contains(t, urls, "a.jpg")
+	assert.Contains(t, urls, "b.mp4")
+	assert.Equal(t, 3, inner.calls)
+}
+
+func TestCloudFrontURLSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	signer, err := NewCloudFrontURLSigner("https://d111111abcdef8.cloudfront.net/", "K2JCJMDEHXQW5F", keyPEM, time.Hour)
+	require.NoError(t, err)
+	cf := signer.(*cloudFrontURLSigner)
+	cf.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	signed, err := signer.Sign("processed/clip 1.mp4")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(signed.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "d111111abcdef8.cloudfront.net", parsed.Host)
+	assert.Equal(t, "/processed/clip%201.mp4", parsed.EscapedPath())
+	assert.Equal(t, "1700003600", parsed.Query().Get("Expires"))
+	assert.Equal(t, "K2JCJMDEHXQW5F", parsed.Query().Get("Key-Pair-Id"))
+	assert.Equal(t, time.Unix(1700003600, 0), signed.ExpiresAt)
+
+	// La firma es RSA-SHA1 de la política predefinida con el base64 de CloudFront
+	resource := strings.SplitN(signed.URL, "?", 2)[0]
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"%s","Condition":{"DateLessThan":{"AWS:EpochTime":1700003600}}}]}`, resource)
+	raw := strings.NewReplacer("-", "+", "_", "=", "~", "/").Replace(parsed.Query().Get("Signature"))
+	signature, err := base64.StdEncoding.DecodeString(raw)
+	require.NoError(t, err)
+	digest := sha1.Sum([]byte(policy))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, digest[:], signature))
+}
+
+func TestNewCloudFrontURLSignerRejectsBadKey(t *testing.T) {
+	_, err := NewCloudFrontURLSigner("d111111abcdef8.cloudfront.net", "K2JCJMDEHXQW5F", []byte("not a key"), time.Hour)
+
+	assert.Error(t, err)
+}
@@ -2,19 +2,39 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// ErrObjectNotFound indica que la clave no existe en el almacenamiento
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo son los metadatos de un objeto que necesita una descarga
+type ObjectInfo struct {
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	ContentType  string
+}
+
 type StorageService interface {
 	Upload(file multipart.File, s3Key string) error
 	Delete(s3Key string) error
 	GetPresignedURL(s3Key string, expiration time.Duration) (string, error)
+	// HeadObject devuelve los metadatos del objeto o ErrObjectNotFound
+	HeadObject(ctx context.Context, s3Key string) (ObjectInfo, error)
+	// GetObject abre el objeto desde offset hasta el final
+	GetObject(ctx context.Context, s3Key string, offset int64) (io.ReadCloser, error)
+	// Ping comprueba que el bucket exista y sea accesible con las credenciales actuales
+	Ping(ctx context.Context) error
 }
 
 type s3StorageService struct {
@@ -88,3 +108,54 @@ func (s *s3StorageService) GetPresignedURL(s3Key string, expiration time.Duratio
 
 	return request.URL, nil
 }
+
+func (s *s3StorageService) HeadObject(ctx context.Context, s3Key string) (ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(s3Key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to head S3 object: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:         aws.ToInt64(output.ContentLength),
+		ETag:         aws.ToString(output.ETag),
+		LastModified: aws.ToTime(output.LastModified),
+		ContentType:  aws.ToString(output.ContentType),
+	}, nil
+}
+
+func (s *s3StorageService) GetObject(ctx context.Context, s3Key string, offset int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(s3Key),
+	}
+	if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	output, err := s.client.GetObject(ctx, input)
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get S3 object: %w", err)
+	}
+	return output.Body, nil
+}
+
+func (s *s3StorageService) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucketName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to access bucket %s: %w", s.bucketName, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ObjectReader lee un objeto como io.ReadSeeker para http.ServeContent. Cada Seek a
+// otra posición cierra la lectura en curso y la siguiente lectura abre una nueva desde
+// ahí, así que un Range solo descarga del almacenamiento los bytes pedidos.
+type ObjectReader struct {
+	ctx     context.Context
+	storage StorageService
+	key     string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+}
+
+func NewObjectReader(ctx context.Context, storage StorageService, key string, size int64) *ObjectReader {
+	return &ObjectReader{ctx: ctx, storage: storage, key: key, size: size}
+}
+
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.storage.GetObject(r.ctx, r.key, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before start of object")
+	}
+	if offset != r.offset {
+		r.Close()
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+func (r *ObjectReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"anb-app/src/apperr"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskService interface {
+	GetByTaskID(taskID string, userID uint) (*TaskResponse, error)
+}
+
+type TaskController struct {
+	taskService TaskService
+}
+
+func NewTaskController(taskService TaskService) *TaskController {
+	return &TaskController{
+		taskService: taskService,
+	}
+}
+
+var errUnauthenticated = apperr.Unauthorized("unauthenticated", "user not authenticated")
+
+func (tc *TaskController) GetTask(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.Error(errUnauthenticated)
+		return
+	}
+
+	task, err := tc.taskService.GetByTaskID(c.Param("task_id"), userID.(uint))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
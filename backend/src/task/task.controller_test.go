@@ -0,0 +1,134 @@
+package task
+
+import (
+	"anb-app/src/apperr"
+	"anb-app/src/apperr/apperrtest"
+	"anb-app/src/openapi"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTaskService struct {
+	mock.Mock
+}
+
+func (m *MockTaskService) GetByTaskID(taskID string, userID uint) (*TaskResponse, error) {
+	args := m.Called(taskID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TaskResponse), args.Error(1)
+}
+
+func TestTaskController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("GetTask_Success", func(t *testing.T) {
+		mockSvc := new(MockTaskService)
+		controller := NewTaskController(mockSvc)
+
+		mockSvc.On("GetByTaskID", "abc", uint(1)).Return(&TaskResponse{TaskID: "abc", Status: StatusProcessing, Step: "scale", Progress: 50}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/tasks/abc", nil)
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "task_id", Value: "abc"}}
+
+		apperrtest.Handle(controller.GetTask, c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response TaskResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "scale", response.Step)
+		assert.Equal(t, 50.0, response.Progress)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("GetTask_Unauthorized", func(t *testing.T) {
+		mockSvc := new(MockTaskService)
+		controller := NewTaskController(mockSvc)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/tasks/abc", nil)
+		c.Params = []gin.Param{{Key: "task_id", Value: "abc"}}
+
+		apperrtest.Handle(controller.GetTask, c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "unauthenticated", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("GetTask_OtherUserIsNotFound", func(t *testing.T) {
+		mockSvc := new(MockTaskService)
+		controller := NewTaskController(mockSvc)
+
+		mockSvc.On("GetByTaskID", "abc", uint(1)).Return(nil, ErrTaskNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/tasks/abc", nil)
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "task_id", Value: "abc"}}
+
+		apperrtest.Handle(controller.GetTask, c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "task_not_found", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("GetTask_InternalError", func(t *testing.T) {
+		mockSvc := new(MockTaskService)
+		controller := NewTaskController(mockSvc)
+
+		mockSvc.On("GetByTaskID", "abc", uint(1)).Return(nil, errors.New("connection refused"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/tasks/abc", nil)
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "task_id", Value: "abc"}}
+
+		apperrtest.Handle(controller.GetTask, c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_error", apperrtest.ProblemCode(t, w))
+	})
+}
+
+// TestTaskContract compara las respuestas de la ruta real con Operations
+func TestTaskContract(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTaskService)
+	mockSvc.On("GetByTaskID", "3f2a9c", uint(1)).Return(&TaskResponse{TaskID: "3f2a9c", VideoID: 3, Status: StatusProcessing, Step: "scale", Progress: 50}, nil)
+	mockSvc.On("GetByTaskID", "missing", uint(1)).Return(nil, ErrTaskNotFound)
+
+	spec := openapi.New("Test", "1")
+	spec.Add("/api/v1", Operations...)
+	router := gin.New()
+	router.Use(spec.ValidationMiddleware(func(c *gin.Context, err error) { t.Error(err) }), apperr.Middleware())
+	auth := func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	}
+	SignUpTaskRoutes(router.Group("/api/v1"), NewTaskController(mockSvc), auth)
+	_, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+
+	for path, status := range map[string]int{"/api/v1/tasks/3f2a9c": http.StatusOK, "/api/v1/tasks/missing": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, status, w.Code, path)
+	}
+}
@@ -0,0 +1,17 @@
+package task
+
+import "time"
+
+type TaskResponse struct {
+	TaskID     string     `json:"task_id"`
+	VideoID    uint       `json:"video_id"`
+	Status     string     `json:"status"`
+	Step       string     `json:"step,omitempty"`
+	Progress   float64    `json:"progress"`
+	Attempts   int        `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,49 @@
+package task
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Estados de un trabajo de procesamiento
+const (
+	StatusQueued     = "queued"
+	StatusProcessing = "processing"
+	// El intento falló y la cola lo volverá a entregar
+	StatusRetrying  = "retrying"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// ProcessingJob registra el avance de una tarea de procesamiento de video. La API lo
+// crea al encolar la tarea y el worker lo actualiza en cada paso del pipeline.
+type ProcessingJob struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	TaskID  string `json:"task_id" gorm:"uniqueIndex;not null"`
+	VideoID uint   `json:"video_id" gorm:"index;not null"`
+	UserID  uint   `json:"user_id" gorm:"index;not null"`
+	Status  string `json:"status" gorm:"default:'queued'"`
+	// Paso actual ("download", "scale", "upload"...) y avance total en porcentaje
+	Step       string     `json:"step,omitempty"`
+	Progress   float64    `json:"progress"`
+	Attempts   int        `json:"attempts" gorm:"default:0"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (ProcessingJob) TableName() string {
+	return "processing_jobs"
+}
+
+// NewTaskID genera un identificador opaco para consultar la tarea desde el cliente
+func NewTaskID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,6 @@
+package task
+
+import "anb-app/src/apperr"
+
+// Errores de tareas con código estable; una tarea ajena también es ErrTaskNotFound
+var ErrTaskNotFound = apperr.NotFound("task_not_found", "the task does not exist or does not belong to the user")
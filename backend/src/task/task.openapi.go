@@ -0,0 +1,17 @@
+package task
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpTaskRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodGet, Path: "/tasks/:task_id", Tags: []string{"videos"}, Auth: true,
+		Summary:    "Estado del procesamiento de un video",
+		PathParams: []openapi.Parameter{{Name: "task_id", Description: "task_id devuelto al subir el video"}},
+		Response:   TaskResponse{},
+		Errors:     []int{http.StatusNotFound},
+	},
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"anb-app/src/events"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type TaskRepository interface {
+	Create(job *ProcessingJob) (*ProcessingJob, error)
+	FindByTaskID(taskID string) (*ProcessingJob, error)
+	Update(job *ProcessingJob) error
+}
+
+type taskRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(db *gorm.DB) TaskRepository {
+	return &taskRepository{
+		db: db,
+	}
+}
+
+// Create y Update publican el estado del trabajo en el stream de eventos del
+// usuario dentro de la misma transacción
+func (r *taskRepository) Create(job *ProcessingJob) (*ProcessingJob, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return events.Publish(tx, jobEvent(job))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *taskRepository) FindByTaskID(taskID string) (*ProcessingJob, error) {
+	var job ProcessingJob
+	result := r.db.Where("task_id = ?", taskID).First(&job)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (r *taskRepository) Update(job *ProcessingJob) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(job).Error; err != nil {
+			return err
+		}
+		return events.Publish(tx, jobEvent(job))
+	})
+}
+
+func jobEvent(job *ProcessingJob) *events.VideoEvent {
+	return &events.VideoEvent{
+		UserID:   job.UserID,
+		VideoID:  job.VideoID,
+		TaskID:   job.TaskID,
+		Type:     events.TypeTask,
+		Status:   job.Status,
+		Step:     job.Step,
+		Progress: job.Progress,
+		Error:    job.Error,
+	}
+}
@@ -0,0 +1,11 @@
+package task
+
+import "github.com/gin-gonic/gin"
+
+func SignUpTaskRoutes(router *gin.RouterGroup, tc *TaskController, authMiddleware gin.HandlerFunc) {
+
+	taskRoutes := router.Group("/tasks", authMiddleware)
+	{
+		taskRoutes.GET("/:task_id", tc.GetTask)
+	}
+}
@@ -0,0 +1,36 @@
+package task
+
+type taskService struct {
+	taskRepo TaskRepository
+}
+
+func NewTaskService(taskRepo TaskRepository) TaskService {
+	return &taskService{
+		taskRepo: taskRepo,
+	}
+}
+
+func (s *taskService) GetByTaskID(taskID string, userID uint) (*TaskResponse, error) {
+	job, err := s.taskRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	// Una tarea ajena se reporta como inexistente para no revelar IDs válidos
+	if job == nil || job.UserID != userID {
+		return nil, ErrTaskNotFound
+	}
+
+	return &TaskResponse{
+		TaskID:     job.TaskID,
+		VideoID:    job.VideoID,
+		Status:     job.Status,
+		Step:       job.Step,
+		Progress:   job.Progress,
+		Attempts:   job.Attempts,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package task
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepository) Create(job *ProcessingJob) (*ProcessingJob, error) {
+	args := m.Called(job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProcessingJob), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindByTaskID(taskID string) (*ProcessingJob, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProcessingJob), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(job *ProcessingJob) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func TestTaskService(t *testing.T) {
+	t.Run("GetByTaskID_Success", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		taskSvc := NewTaskService(mockRepo)
+
+		job := &ProcessingJob{TaskID: "abc", VideoID: 3, UserID: 1, Status: StatusProcessing, Step: "scale", Progress: 42.5, Attempts: 2}
+		mockRepo.On("FindByTaskID", "abc").Return(job, nil)
+
+		result, err := taskSvc.GetByTaskID("abc", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", result.TaskID)
+		assert.Equal(t, uint(3), result.VideoID)
+		assert.Equal(t, StatusProcessing, result.Status)
+		assert.Equal(t, "scale", result.Step)
+		assert.Equal(t, 42.5, result.Progress)
+		assert.Equal(t, 2, result.Attempts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetByTaskID_NotFound", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		taskSvc := NewTaskService(mockRepo)
+
+		mockRepo.On("FindByTaskID", "missing").Return(nil, nil)
+
+		result, err := taskSvc.GetByTaskID("missing", 1)
+
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("GetByTaskID_OtherUserIsNotFound", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		taskSvc := NewTaskService(mockRepo)
+
+		mockRepo.On("FindByTaskID", "abc").Return(&ProcessingJob{TaskID: "abc", UserID: 2}, nil)
+
+		result, err := taskSvc.GetByTaskID("abc", 1)
+
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("GetByTaskID_RepositoryError", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		taskSvc := NewTaskService(mockRepo)
+
+		mockRepo.On("FindByTaskID", "abc").Return(nil, errors.New("connection refused"))
+
+		_, err := taskSvc.GetByTaskID("abc", 1)
+
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("NewTaskID_IsUnique", func(t *testing.T) {
+		first, err := NewTaskID()
+		assert.NoError(t, err)
+		second, _ := NewTaskID()
+
+		assert.Len(t, first, 32)
+		assert.NotEqual(t, first, second)
+	})
+}
@@ -1,8 +1,8 @@
 package user
 
 import (
+	"anb-app/src/apperr"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -21,37 +21,26 @@ type UserController struct {
 func NewUserController(userService UserService) *UserController {
 	return &UserController{
 		userService: userService,
-		validate:    validator.New(),
+		validate:    apperr.NewValidator(),
 	}
 }
 
 func (uc *UserController) SignUp(c *gin.Context) {
 	req := new(CreateUserRequest)
 	if err := c.ShouldBindJSON(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or malformed input data."})
+		c.Error(apperr.Validation("invalid_body", "invalid or malformed input data").Wrap(err))
 		return
 	}
 
+	// password2 distinto de password se reporta como eqfield en el campo password2
 	if err := uc.validate.Struct(req); err != nil {
-		if validationErrs, ok := err.(validator.ValidationErrors); ok {
-			for _, fieldErr := range validationErrs {
-				if fieldErr.Field() == "Password2" && fieldErr.Tag() == "eqfield" {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "Passwords do not match."})
-					return
-				}
-			}
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation error. Please check the provided fields."})
+		c.Error(apperr.FromValidation(err))
 		return
 	}
 
 	userResponse, err := uc.userService.SignUp(c, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "email already exists") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "The provided email is already registered."})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An error occurred while registering the user."})
+		c.Error(err)
 		return
 	}
 
@@ -61,17 +50,17 @@ func (uc *UserController) SignUp(c *gin.Context) {
 func (uc *UserController) Login(c *gin.Context) {
 	req := new(LoginRequest)
 	if err := c.ShouldBindJSON(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data."})
+		c.Error(apperr.Validation("invalid_body", "invalid or malformed input data").Wrap(err))
 		return
 	}
 	if err := uc.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperr.FromValidation(err))
 		return
 	}
 
 	tokenResponse, err := uc.userService.Login(c, req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials."})
+		c.Error(err)
 		return
 	}
 
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"anb-app/src/apperr"
+	"anb-app/src/apperr/apperrtest"
+	"anb-app/src/i18n"
 	"bytes"
 	"encoding/json"
 	"net/http"
@@ -66,6 +69,7 @@ func TestUserController(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		router := gin.New()
+		router.Use(apperr.Middleware())
 		router.POST("/auth/signup", controller.SignUp)
 		router.ServeHTTP(w, req)
 
@@ -88,14 +92,14 @@ func TestUserController(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		router := gin.New()
+		router.Use(apperr.Middleware())
 		router.POST("/auth/signup", controller.SignUp)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Contains(t, response["error"], "Invalid or malformed input data")
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, "invalid_body", problem.Code)
 	})
 
 	t.Run("SignUp_PasswordMismatch", func(t *testing.T) {
@@ -118,14 +122,43 @@ func TestUserController(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		router := gin.New()
+		router.Use(apperr.Middleware())
 		router.POST("/auth/signup", controller.SignUp)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Contains(t, response["error"], "Passwords do not match")
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, "Uno o más campos no son válidos", problem.Detail)
+		assert.Equal(t, []apperr.FieldError{{Field: "password2", Code: "eqfield", Message: "password2 debe coincidir con password"}}, problem.Errors)
+	})
+
+	t.Run("SignUp_FieldMessagesInEnglish", func(t *testing.T) {
+		mockSvc := new(MockUserService)
+		controller := NewUserController(mockSvc)
+
+		body, _ := json.Marshal(CreateUserRequest{FirstName: "John", Email: "john", Password: "short", Password2: "short", City: "Bogotá", Country: "Colombia"})
+		req := httptest.NewRequest("POST", "/auth/signup", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "en")
+		w := httptest.NewRecorder()
+
+		router := gin.New()
+		router.Use(i18n.Middleware(), apperr.Middleware())
+		router.POST("/auth/signup", controller.SignUp)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, []apperr.FieldError{
+			{Field: "last_name", Code: "required", Message: "last_name is required"},
+			{Field: "email", Code: "email", Message: "email must be a valid email address"},
+			{Field: "password", Code: "min", Message: "password must be at least 8 characters long"},
+		}, problem.Errors)
+		mockSvc.AssertNotCalled(t, "SignUp", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Login_Success", func(t *testing.T) {
@@ -151,6 +184,7 @@ func TestUserController(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		router := gin.New()
+		router.Use(apperr.Middleware())
 		router.POST("/auth/login", controller.Login)
 		router.ServeHTTP(w, req)
 
@@ -173,7 +207,7 @@ func TestUserController(t *testing.T) {
 			Password: "wrongpass",
 		}
 
-		mockSvc.On("Login", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*user.LoginRequest")).Return(nil, assert.AnError)
+		mockSvc.On("Login", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*user.LoginRequest")).Return(nil, ErrInvalidCredentials)
 
 		body, _ := json.Marshal(loginReq)
 		req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
@@ -181,14 +215,14 @@ func TestUserController(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		router := gin.New()
+		router.Use(apperr.Middleware())
 		router.POST("/auth/login", controller.Login)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Invalid credentials.", response["error"])
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, "invalid_credentials", problem.Code)
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -2,6 +2,12 @@ package user
 
 import "time"
 
+// Roles de usuario. Los administradores se designan con ADMIN_EMAILS al arrancar.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	FirstName string    `json:"first_name" gorm:"not null"`
@@ -10,6 +16,7 @@ type User struct {
 	Password  string    `json:"-" gorm:"not null"`
 	City      string    `json:"city"`
 	Country   string    `json:"country"`
+	Role      string    `json:"role" gorm:"not null;default:'user'"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
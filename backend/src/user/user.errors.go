@@ -0,0 +1,11 @@
+package user
+
+import "anb-app/src/apperr"
+
+// Errores de usuarios con código estable
+var (
+	ErrEmailTaken         = apperr.Conflict("email_taken", "email already exists")
+	ErrInvalidCredentials = apperr.Unauthorized("invalid_credentials", "invalid credentials")
+	ErrUnauthenticated    = apperr.Unauthorized("unauthenticated", "user not authenticated")
+	ErrAdminRequired      = apperr.Forbidden("admin_required", "administrator role required")
+)
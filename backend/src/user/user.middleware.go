@@ -0,0 +1,31 @@
+package user
+
+import (
+	"anb-app/src/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin deja pasar solo a administradores. Va después de AuthMiddleware y
+// consulta el rol en la base de datos, así que quitar el rol surte efecto de inmediato.
+func RequireAdmin(userRepo UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDClaim, exists := c.Get("userID")
+		if !exists {
+			apperr.Render(c, ErrUnauthenticated)
+			return
+		}
+
+		user, err := userRepo.FindByID(userIDClaim.(uint))
+		if err != nil {
+			apperr.Render(c, err)
+			return
+		}
+		if user == nil || user.Role != RoleAdmin {
+			apperr.Render(c, ErrAdminRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(mockRepo *MockUserRepository, userID *uint) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if userID != nil {
+				c.Set("userID", *userID)
+			}
+		})
+		router.GET("/admin", RequireAdmin(mockRepo), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	userID := uint(1)
+
+	t.Run("Admin_Allowed", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("FindByID", userID).Return(&User{ID: userID, Role: RoleAdmin}, nil)
+
+		w := httptest.NewRecorder()
+		newRouter(mockRepo, &userID).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("RegularUser_Forbidden", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("FindByID", userID).Return(&User{ID: userID, Role: RoleUser}, nil)
+
+		w := httptest.NewRecorder()
+		newRouter(mockRepo, &userID).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(new(MockUserRepository), nil).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("FindByID", userID).Return(nil, errors.New("connection refused"))
+
+		w := httptest.NewRecorder()
+		newRouter(mockRepo, &userID).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpUserRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/auth/signup", Tags: []string{"auth"},
+		Summary:  "Registrar un jugador",
+		Request:  CreateUserRequest{},
+		Status:   http.StatusCreated,
+		Response: UserResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/login", Tags: []string{"auth"},
+		Summary:  "Iniciar sesión y obtener el token Bearer",
+		Request:  LoginRequest{},
+		Response: TokenResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	},
+}
@@ -37,3 +37,17 @@ func (r *userRepository) FindByEmail(email string) (*User, error) {
 
 	return &user, nil
 }
+
+func (r *userRepository) FindByID(userID uint) (*User, error) {
+	var user User
+	result := r.db.First(&user, userID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
@@ -12,6 +12,7 @@ import (
 type UserRepository interface {
 	Create(user *User) (*User, error)
 	FindByEmail(email string) (*User, error)
+	FindByID(userID uint) (*User, error)
 }
 
 type userService struct {
@@ -32,7 +33,7 @@ func (s *userService) SignUp(ctx *gin.Context, req *CreateUserRequest) (*UserRes
 		return nil, errors.New("database error while checking email")
 	}
 	if existingUser != nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailTaken
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
@@ -72,12 +73,12 @@ func (s *userService) Login(ctx *gin.Context, req *LoginRequest) (*TokenResponse
 		return nil, errors.New("database error")
 	}
 	if user == nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	expirationTime := time.Now().Add(24 * time.Hour)
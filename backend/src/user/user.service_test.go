@@ -27,6 +27,14 @@ func (m *MockUserRepository) FindByEmail(email string) (*User, error) {
 	return args.Get(0).(*User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByID(userID uint) (*User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
 func TestUserService(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
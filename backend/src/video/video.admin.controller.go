@@ -0,0 +1,85 @@
+package video
+
+import (
+	"anb-app/src/apperr"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type VideoRequeuer interface {
+	Requeue(filter RequeueFilter, opts RequeueOptions) (*RequeueResult, error)
+}
+
+// AdminController expone las operaciones de mantenimiento sobre videos de cualquier usuario
+type AdminController struct {
+	requeuer VideoRequeuer
+	validate *validator.Validate
+}
+
+func NewAdminController(requeuer VideoRequeuer) *AdminController {
+	return &AdminController{
+		requeuer: requeuer,
+		validate: apperr.NewValidator(),
+	}
+}
+
+// RequeueVideo reprocesa un video; ?dry_run=true solo comprueba que se puede
+func (ac *AdminController) RequeueVideo(c *gin.Context) {
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := RequeueFilter{VideoID: videoID, Force: c.Query("force") == "true"}
+	result, err := ac.requeuer.Requeue(filter, RequeueOptions{DryRun: c.Query("dry_run") == "true"})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ac.respond(c, result)
+}
+
+// RequeueVideos reprocesa los videos que cumplen el filtro del cuerpo
+func (ac *AdminController) RequeueVideos(c *gin.Context) {
+	var req RequeueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperr.Validation("invalid_body", "invalid request body").Wrap(err))
+		return
+	}
+	if err := ac.validate.Struct(req); err != nil {
+		c.Error(apperr.FromValidation(err))
+		return
+	}
+
+	filter := RequeueFilter{
+		Status:          req.Status,
+		UploadedFrom:    req.UploadedFrom,
+		UploadedTo:      req.UploadedTo,
+		PipelineVersion: req.PipelineVersion,
+		Force:           req.Force,
+		Limit:           req.Limit,
+	}
+	result, err := ac.requeuer.Requeue(filter, RequeueOptions{DryRun: req.DryRun, RatePerSecond: req.RatePerSecond})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ac.respond(c, result)
+}
+
+func (ac *AdminController) respond(c *gin.Context, result *RequeueResult) {
+	switch {
+	case result.DryRun:
+		c.JSON(http.StatusOK, result)
+	case result.Error != "":
+		// Parte de los videos quedó encolada: se devuelve el detalle con el error
+		c.JSON(http.StatusInternalServerError, result)
+	default:
+		c.JSON(http.StatusAccepted, result)
+	}
+}
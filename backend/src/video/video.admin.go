@@ -0,0 +1,139 @@
+package video
+
+import (
+	"anb-app/src/queue"
+	"anb-app/src/task"
+	"log"
+	"time"
+)
+
+const (
+	// Tareas por segundo que se liberan a la cola si la petición no indica otra cosa
+	DefaultRequeueRate  = 2.0
+	DefaultRequeueLimit = 500
+	MaxRequeueLimit     = 5000
+)
+
+// RequeueFilter selecciona los videos a reprocesar; los campos vacíos no filtran
+type RequeueFilter struct {
+	VideoID         uint
+	Status          string
+	UploadedFrom    *time.Time
+	UploadedTo      *time.Time
+	PipelineVersion string
+	// Incluir videos con una tarea todavía activa (p. ej. un worker que murió a mitad)
+	Force bool
+	Limit int
+}
+
+func (f RequeueFilter) empty() bool {
+	return f.VideoID == 0 && f.Status == "" && f.UploadedFrom == nil && f.UploadedTo == nil && f.PipelineVersion == ""
+}
+
+// RequeueOptions controla cómo se encolan los videos seleccionados
+type RequeueOptions struct {
+	// DryRun solo lista los videos que se reprocesarían
+	DryRun bool
+	// RatePerSecond espacia la publicación de las tareas para no inundar la cola
+	RatePerSecond float64
+}
+
+// Requeuer vuelve a encolar el procesamiento de videos ya subidos. Cada tarea se
+// registra en el outbox; el relay las publica respetando el ritmo pedido.
+type Requeuer struct {
+	videoRepo VideoRepository
+	taskRepo  task.TaskRepository
+	now       func() time.Time
+}
+
+func NewRequeuer(videoRepo VideoRepository, taskRepo task.TaskRepository) *Requeuer {
+	return &Requeuer{
+		videoRepo: videoRepo,
+		taskRepo:  taskRepo,
+		now:       time.Now,
+	}
+}
+
+func (r *Requeuer) Requeue(filter RequeueFilter, opts RequeueOptions) (*RequeueResult, error) {
+	if filter.empty() {
+		return nil, ErrInvalidRequeue.WithMessage("invalid filter: at least one criterion is required")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultRequeueLimit
+	}
+	if filter.Limit > MaxRequeueLimit {
+		return nil, invalidField(ErrInvalidRequeue, "limit", "invalid filter: limit is too high")
+	}
+	if opts.RatePerSecond <= 0 {
+		opts.RatePerSecond = DefaultRequeueRate
+	}
+
+	videos, err := r.videoRepo.FindForRequeue(filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.VideoID != 0 && len(videos) == 0 {
+		return nil, ErrNotRequeueable
+	}
+
+	result := &RequeueResult{DryRun: opts.DryRun, Matched: len(videos), Videos: make([]RequeuedVideo, 0, len(videos))}
+	start := r.now()
+	interval := time.Duration(float64(time.Second) / opts.RatePerSecond)
+
+	for i := range videos {
+		v := &videos[i]
+		item := RequeuedVideo{VideoID: v.ID, Title: v.Title, Status: v.Status, PipelineVersion: v.PipelineVersion}
+		if opts.DryRun {
+			result.Videos = append(result.Videos, item)
+			continue
+		}
+
+		availableAt := start.Add(time.Duration(i) * interval)
+		taskID, err := r.requeueVideo(v, availableAt)
+		if err != nil {
+			// Lo ya encolado se mantiene; se informa hasta dónde se llegó
+			log.Printf("Error requeueing video %d: %v", v.ID, err)
+			result.Error = err.Error()
+			break
+		}
+		item.TaskID = taskID
+		item.AvailableAt = &availableAt
+		result.Videos = append(result.Videos, item)
+		result.Queued++
+	}
+
+	return result, nil
+}
+
+// requeueVideo registra la tarea en el outbox junto con el cambio de estado del video
+func (r *Requeuer) requeueVideo(v *Video, availableAt time.Time) (string, error) {
+	taskID, err := task.NewTaskID()
+	if err != nil {
+		return "", err
+	}
+	msg, err := queue.NewOutboxMessage(TypeVideoProcess, queue.TaskPayload{VideoID: v.ID, TaskID: taskID}, 5, 10*time.Minute)
+	if err != nil {
+		return "", err
+	}
+	msg.AvailableAt = availableAt
+
+	// Un video fallido vuelve a "uploaded"; uno procesado sigue visible con la
+	// versión anterior hasta que el worker termine
+	if v.Status == "failed" {
+		v.Status = "uploaded"
+		v.FailureReason = ""
+	}
+	if err := r.videoRepo.UpdateProcessing(v, msg); err != nil {
+		return "", err
+	}
+
+	if _, err := r.taskRepo.Create(&task.ProcessingJob{
+		TaskID:  taskID,
+		VideoID: v.ID,
+		UserID:  v.UserID,
+		Status:  task.StatusQueued,
+	}); err != nil {
+		log.Printf("Error creating processing job %s for video %d: %v", taskID, v.ID, err)
+	}
+	return taskID, nil
+}
@@ -0,0 +1,188 @@
+package video
+
+import (
+	"anb-app/src/apperr"
+	"anb-app/src/queue"
+	"anb-app/src/task"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequeuer(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Requeue_StaggersTasksByRate", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockTasks := new(MockTaskRepository)
+		requeuer := NewRequeuer(mockRepo, mockTasks)
+		requeuer.now = func() time.Time { return now }
+
+		filter := RequeueFilter{Status: "failed", Limit: DefaultRequeueLimit}
+		mockRepo.On("FindForRequeue", filter).Return([]Video{
+			{ID: 1, UserID: 7, Status: "failed", FailureReason: "silent_audio"},
+			{ID: 2, UserID: 8, Status: "failed"},
+		}, nil)
+		mockRepo.On("UpdateProcessing", mock.AnythingOfType("*video.Video")).Return(nil)
+		mockTasks.On("Create", mock.AnythingOfType("*task.ProcessingJob")).Return(&task.ProcessingJob{}, nil)
+
+		result, err := requeuer.Requeue(RequeueFilter{Status: "failed"}, RequeueOptions{RatePerSecond: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Matched)
+		assert.Equal(t, 2, result.Queued)
+		assert.NotEmpty(t, result.Videos[0].TaskID)
+		// Los videos fallidos vuelven a uploaded y sin motivo de rechazo
+		updated := mockRepo.Calls[1].Arguments.Get(0).(*Video)
+		assert.Equal(t, "uploaded", updated.Status)
+		assert.Empty(t, updated.FailureReason)
+		if assert.Len(t, mockRepo.Messages, 2) {
+			assert.Equal(t, queue.TopicTasks, mockRepo.Messages[0].Topic)
+			assert.Equal(t, now, mockRepo.Messages[0].AvailableAt)
+			assert.Equal(t, now.Add(250*time.Millisecond), mockRepo.Messages[1].AvailableAt)
+		}
+		mockTasks.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("Requeue_DryRunChangesNothing", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockTasks := new(MockTaskRepository)
+		requeuer := NewRequeuer(mockRepo, mockTasks)
+
+		filter := RequeueFilter{PipelineVersion: "anb-default@v1", Limit: 10}
+		mockRepo.On("FindForRequeue", filter).Return([]Video{{ID: 3, Status: "processed", PipelineVersion: "anb-default@v1"}}, nil)
+
+		result, err := requeuer.Requeue(filter, RequeueOptions{DryRun: true})
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 1, result.Matched)
+		assert.Equal(t, 0, result.Queued)
+		assert.Equal(t, uint(3), result.Videos[0].VideoID)
+		mockRepo.AssertNotCalled(t, "UpdateProcessing", mock.Anything)
+		mockTasks.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Requeue_RequiresCriterion", func(t *testing.T) {
+		requeuer := NewRequeuer(new(MockVideoRepository), new(MockTaskRepository))
+
+		_, err := requeuer.Requeue(RequeueFilter{Force: true}, RequeueOptions{})
+
+		assert.ErrorContains(t, err, "invalid filter")
+	})
+
+	t.Run("Requeue_SingleVideoNotFound", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		requeuer := NewRequeuer(mockRepo, new(MockTaskRepository))
+
+		mockRepo.On("FindForRequeue", RequeueFilter{VideoID: 5, Limit: DefaultRequeueLimit}).Return([]Video{}, nil)
+
+		_, err := requeuer.Requeue(RequeueFilter{VideoID: 5}, RequeueOptions{})
+
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("Requeue_StopsOnError", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		requeuer := NewRequeuer(mockRepo, new(MockTaskRepository))
+
+		mockRepo.On("FindForRequeue", mock.Anything).Return([]Video{{ID: 1}, {ID: 2}}, nil)
+		mockRepo.On("UpdateProcessing", mock.AnythingOfType("*video.Video")).Return(assert.AnError)
+
+		result, err := requeuer.Requeue(RequeueFilter{Status: "processed"}, RequeueOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Queued)
+		assert.NotEmpty(t, result.Error)
+		mockRepo.AssertNumberOfCalls(t, "UpdateProcessing", 1)
+	})
+}
+
+type MockVideoRequeuer struct {
+	mock.Mock
+}
+
+func (m *MockVideoRequeuer) Requeue(filter RequeueFilter, opts RequeueOptions) (*RequeueResult, error) {
+	args := m.Called(filter, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*RequeueResult), args.Error(1)
+}
+
+func newAdminTestRouter(requeuer VideoRequeuer) *gin.Engine {
+	router := gin.New()
+	router.Use(apperr.Middleware())
+	pass := func(c *gin.Context) { c.Next() }
+	SignUpVideoAdminRoutes(router.Group("/api/v1"), NewAdminController(requeuer), pass, pass)
+	return router
+}
+
+func TestAdminController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("RequeueVideo_Accepted", func(t *testing.T) {
+		mockRequeuer := new(MockVideoRequeuer)
+		mockRequeuer.On("Requeue", RequeueFilter{VideoID: 4}, RequeueOptions{}).Return(&RequeueResult{Matched: 1, Queued: 1}, nil)
+
+		w := httptest.NewRecorder()
+		newAdminTestRouter(mockRequeuer).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/videos/4/requeue", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockRequeuer.AssertExpectations(t)
+	})
+
+	t.Run("RequeueVideo_NotFound", func(t *testing.T) {
+		mockRequeuer := new(MockVideoRequeuer)
+		mockRequeuer.On("Requeue", mock.Anything, mock.Anything).Return(nil, ErrNotRequeueable)
+
+		w := httptest.NewRecorder()
+		newAdminTestRouter(mockRequeuer).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/videos/4/requeue", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("RequeueVideos_DryRun", func(t *testing.T) {
+		mockRequeuer := new(MockVideoRequeuer)
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockRequeuer.On("Requeue", RequeueFilter{Status: "failed", UploadedFrom: &from}, RequeueOptions{DryRun: true}).
+			Return(&RequeueResult{DryRun: true, Matched: 1, Videos: []RequeuedVideo{{VideoID: 2}}}, nil)
+
+		body, _ := json.Marshal(gin.H{"status": "failed", "uploaded_from": from, "dry_run": true})
+		w := httptest.NewRecorder()
+		newAdminTestRouter(mockRequeuer).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/videos/requeue", bytes.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result RequeueResult
+		json.Unmarshal(w.Body.Bytes(), &result)
+		assert.True(t, result.DryRun)
+		assert.Len(t, result.Videos, 1)
+	})
+
+	t.Run("RequeueVideos_InvalidStatus", func(t *testing.T) {
+		mockRequeuer := new(MockVideoRequeuer)
+
+		w := httptest.NewRecorder()
+		newAdminTestRouter(mockRequeuer).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/videos/requeue", bytes.NewReader([]byte(`{"status":"deleted"}`))))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRequeuer.AssertNotCalled(t, "Requeue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RequeueVideos_EmptyFilter", func(t *testing.T) {
+		mockRequeuer := new(MockVideoRequeuer)
+		mockRequeuer.On("Requeue", RequeueFilter{}, RequeueOptions{}).Return(nil, ErrInvalidRequeue)
+
+		w := httptest.NewRecorder()
+		newAdminTestRouter(mockRequeuer).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/videos/requeue", bytes.NewReader([]byte(`{}`))))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,129 @@
+package video
+
+import (
+	"anb-app/src/openapi"
+	"anb-app/src/pagination"
+	"anb-app/src/storage"
+	"anb-app/src/user"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// echoSigner devuelve una URL que contiene la clave, para detectar qué se firmó
+type echoSigner struct{}
+
+func (echoSigner) Sign(key string) (storage.SignedURL, error) {
+	return storage.SignedURL{URL: "https://cdn.example.com/" + key + "?sig=test", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+// ownerOnlyFields no pueden aparecer en ninguna respuesta pública
+var ownerOnlyFields = []string{
+	"original_url", "email", "status", "failure_reason", "task_id",
+	"pipeline_version", "loudness_lufs", "deleted_at", "password",
+}
+
+// assertPublicContract recorre el JSON y falla si encuentra campos del dueño, emails,
+// originales o claves internas de S3 sin firmar
+func assertPublicContract(t *testing.T, path string, value interface{}) {
+	t.Helper()
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			assert.NotContains(t, ownerOnlyFields, key, "%s serializes owner-only field %q", path, key)
+			assertPublicContract(t, path+"."+key, nested)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			assertPublicContract(t, path, nested)
+		}
+	case string:
+		assert.NotContains(t, v, "originals/", "%s exposes an original: %q", path, v)
+		assert.NotContains(t, v, "@", "%s exposes an email: %q", path, v)
+		assert.False(t, strings.HasPrefix(v, "processed/"), "%s exposes a raw S3 key: %q", path, v)
+	}
+}
+
+func TestPublicRoutesContract(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	processedAt := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	leaky := Video{
+		ID:              1,
+		UserID:          7,
+		Title:           "Mate del año",
+		Status:          "processed",
+		OriginalURL:     "originals/1.mp4",
+		ProcessedURL:    "processed/1.mp4",
+		ThumbnailURL:    "processed/1_thumb.jpg",
+		PreviewURL:      "processed/1_preview.mp4",
+		VoteCount:       42,
+		ProcessedAt:     &processedAt,
+		Category:        "dunk",
+		Tags:            []string{"final"},
+		PipelineVersion: "anb-default@v1",
+		User:            user.User{ID: 7, FirstName: "Ana", LastName: "Pérez", Email: "ana@anb.com", Password: "hash"},
+	}
+
+	mockRepo := new(MockVideoRepository)
+	mockRepo.On("FindPublic", mock.Anything, mock.Anything).Return([]Video{leaky}, nil)
+	mockRepo.On("GetRankings", mock.Anything).Return([]RankingResponse{{
+		Position: 1, VideoID: 1, Title: leaky.Title, AuthorName: "Ana Pérez", VoteCount: 42,
+		ThumbnailURL: leaky.ThumbnailURL, PreviewURL: leaky.PreviewURL,
+	}}, nil)
+	mockRepo.On("Search", mock.Anything, mock.Anything).Return([]SearchResult{{
+		VideoID: 1, Title: leaky.Title, VoteCount: 42, PlayerName: "Ana Pérez",
+		ThumbnailURL: leaky.ThumbnailURL, PreviewURL: leaky.PreviewURL,
+		TitleHighlight: "<mark>Mate</mark> del año", PlayerHighlight: "Ana Pérez",
+	}}, int64(1), nil)
+
+	videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), echoSigner{})
+	router := gin.New()
+	// Las respuestas también se comparan con el documento OpenAPI
+	spec := openapi.New("ANB API", "test")
+	spec.Add("/api/v1", Operations...)
+	router.Use(spec.ValidationMiddleware(func(c *gin.Context, err error) { t.Error(err) }))
+	noAuth := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	SignUpVideoRoutes(router.Group("/api/v1"), NewVideoController(videoSvc), noAuth)
+	_, err := spec.Build(router.Routes(), "/api/")
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/api/v1/public/videos",
+		"/api/v1/public/videos?category=dunk&tags=final",
+		"/api/v1/public/rankings",
+		"/api/v1/public/search?q=mate",
+	} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assertPublicContract(t, path, body)
+
+			var page pagination.Page[map[string]interface{}]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			require.Len(t, page.Items, 1)
+			assert.Contains(t, page.Items[0]["thumbnail_url"], "https://cdn.example.com/processed/")
+		})
+	}
+
+	t.Run("PublicResponseHasNoOwnerFields", func(t *testing.T) {
+		data, err := json.Marshal(toPublicResponse(&leaky, map[string]string{}))
+		require.NoError(t, err)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &body))
+		assertPublicContract(t, "PublicVideoResponse", body)
+	})
+}
@@ -1,11 +1,14 @@
 package video
 
 import (
-	"fmt"
+	"anb-app/src/apperr"
+	"anb-app/src/i18n"
+	"anb-app/src/pagination"
+	"context"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,81 +17,114 @@ import (
 
 type VideoService interface {
 	Upload(ctx *gin.Context, req *UploadVideoRequest, file *multipart.FileHeader, userID uint) (*VideoResponse, error)
-	ListByUserID(userID uint) ([]VideoResponse, error)
+	ListByUserID(userID uint, page pagination.Request) (pagination.Page[VideoResponse], error)
 	GetByID(videoID uint, userID uint) (*VideoResponse, error)
+	Update(videoID uint, userID uint, req *UpdateVideoRequest) (*VideoResponse, error)
 	Delete(videoID uint, userID uint) error
-	ListPublic() ([]VideoResponse, error)
-	MarkAsProcessed(videoID uint, userID uint) (*VideoResponse, error)
-	GetRankings() ([]RankingResponse, error)
+	ListTrash(userID uint, page pagination.Request) (pagination.Page[TrashedVideoResponse], error)
+	Restore(videoID uint, userID uint) error
+	ListPublic(filter PublicVideoFilter, page pagination.Request) (pagination.Page[PublicVideoResponse], error)
+	GetRankings(page pagination.Request) (pagination.Page[RankingResponse], error)
+	Search(query SearchQuery, page pagination.Request) (*SearchResponse, error)
+	OpenDownload(ctx context.Context, videoID uint, userID uint, variant string) (*Download, error)
 }
 
 type VideoController struct {
 	videoService VideoService
 	validate     *validator.Validate
+	// Bytes por segundo de cada descarga por la API; 0 no limita
+	downloadRate int64
 }
 
 func NewVideoController(videoService VideoService) *VideoController {
 	return &VideoController{
 		videoService: videoService,
-		validate:     validator.New(),
+		validate:     apperr.NewValidator(),
 	}
 }
 
-func (vc *VideoController) Upload(c *gin.Context) {
+// SetDownloadRate limita el ancho de banda de cada descarga (bytes por segundo; 0 no limita)
+func (vc *VideoController) SetDownloadRate(bytesPerSec int64) {
+	vc.downloadRate = bytesPerSec
+}
+
+// Los handlers registran los errores con c.Error y el middleware de apperr arma la
+// respuesta problem+json con el código HTTP según el tipo de error
+
+var errUnauthenticated = apperr.Unauthorized("unauthenticated", "user not authenticated")
+
+// currentUser devuelve el usuario autenticado por el middleware de auth
+func currentUser(c *gin.Context) (uint, error) {
 	userIDClaim, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
+		return 0, errUnauthenticated
 	}
 	userID, ok := userIDClaim.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format in token"})
+		return 0, errUnauthenticated
+	}
+	return userID, nil
+}
+
+func parseVideoID(c *gin.Context) (uint, error) {
+	videoID, err := strconv.ParseUint(c.Param("video_id"), 10, 32)
+	if err != nil {
+		return 0, ErrInvalidVideoID
+	}
+	return uint(videoID), nil
+}
+
+func (vc *VideoController) Upload(c *gin.Context) {
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	req := new(UploadVideoRequest)
 	if err := c.ShouldBind(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+		c.Error(apperr.Validation("invalid_body", "invalid form data").Wrap(err))
 		return
 	}
 	if err := vc.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperr.FromValidation(err))
 		return
 	}
 
 	file, err := c.FormFile("video")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Video file is required"})
+		c.Error(ErrVideoFileRequired)
 		return
 	}
 
 	videoResponse, err := vc.videoService.Upload(c, req, file, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Video subido correctamente. Procesamiento en curso.",
-		"task_id": fmt.Sprintf("%d-%d", videoResponse.ID, time.Now().Unix()),
+	c.JSON(http.StatusCreated, UploadVideoResponse{
+		Message: i18n.Message(c, "video.uploaded"),
+		TaskID:  videoResponse.TaskID,
 	})
 }
 
 func (vc *VideoController) ListMyVideos(c *gin.Context) {
-	userIDClaim, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
-	userID, ok := userIDClaim.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format in token"})
+
+	page, err := pagination.Parse(c, MyVideosSpec)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	videos, err := vc.videoService.ListByUserID(userID)
+	videos, err := vc.videoService.ListByUserID(userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve videos"})
+		c.Error(err)
 		return
 	}
 
@@ -96,121 +132,239 @@ func (vc *VideoController) ListMyVideos(c *gin.Context) {
 }
 
 func (vc *VideoController) GetVideoByID(c *gin.Context) {
-	videoIDStr := c.Param("video_id")
-	videoID, err := strconv.ParseUint(videoIDStr, 10, 32)
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	video, err := vc.videoService.GetByID(videoID, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID format"})
+		c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, video)
+}
 
-	userIDClaim, _ := c.Get("userID")
-	userID := userIDClaim.(uint)
+// UpdateVideo cambia los metadatos del video (título, descripción, categoría, tags y fecha de grabación)
+func (vc *VideoController) UpdateVideo(c *gin.Context) {
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	video, err := vc.videoService.GetByID(uint(videoID), userID)
+	req := new(UpdateVideoRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.Error(apperr.Validation("invalid_body", "invalid request body").Wrap(err))
+		return
+	}
+	if err := vc.validate.Struct(req); err != nil {
+		c.Error(apperr.FromValidation(err))
+		return
+	}
+
+	video, err := vc.videoService.Update(videoID, userID, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "The video with the specified ID does not exist or does not belong to the user."}) // <-- English
-			return
-		}
-		if strings.Contains(err.Error(), "permission") {
-			c.JSON(http.StatusForbidden, gin.H{"error": "The authenticated user does not have permission to access this video."}) // <-- English
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Error(err)
 		return
 	}
+
 	c.JSON(http.StatusOK, video)
 }
 
-// Download endpoint no longer needed - videos are downloaded directly from S3 using presigned URLs
-// The presigned URLs in OriginalURL and ProcessedURL can be used directly for downloads
+// Download entrega un archivo del dueño a través de la API, para clientes que no pueden usar
+// las URLs firmadas. ?variant=original (por defecto) o processed.
 func (vc *VideoController) Download(c *gin.Context) {
-	c.JSON(http.StatusGone, gin.H{
-		"error":   "This endpoint is deprecated",
-		"message": "Videos are now served directly from S3. Use the OriginalURL or ProcessedURL from the video details.",
-	})
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	vc.serveDownload(c, videoID, userID, c.DefaultQuery("variant", VariantOriginal))
 }
 
-func (vc *VideoController) DeleteVideo(c *gin.Context) {
-	videoIDStr := c.Param("video_id")
-	videoID, err := strconv.ParseUint(videoIDStr, 10, 32)
+// DownloadPublic entrega el video procesado de un video público; los originales nunca
+func (vc *VideoController) DownloadPublic(c *gin.Context) {
+	videoID, err := parseVideoID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID format"})
+		c.Error(err)
 		return
 	}
 
-	userIDClaim, _ := c.Get("userID")
-	userID := userIDClaim.(uint)
+	vc.serveDownload(c, videoID, 0, c.DefaultQuery("variant", VariantProcessed))
+}
 
-	err = vc.videoService.Delete(uint(videoID), userID)
+// serveDownload hace el proxy desde el almacenamiento: http.ServeContent resuelve Range,
+// If-Range, If-None-Match contra el ETag del objeto y HEAD
+func (vc *VideoController) serveDownload(c *gin.Context, videoID uint, userID uint, variant string) {
+	download, err := vc.videoService.OpenDownload(c.Request.Context(), videoID, userID, variant)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video with id video_id does not exist or don't belongs to user."})
-			return
-		}
-		if strings.Contains(err.Error(), "permission") {
-			c.JSON(http.StatusForbidden, gin.H{"error": "User authenticated does not have permitions on this video."})
-			return
-		}
-		if strings.Contains(err.Error(), "cannot delete") {
-			// 400 Bad Request
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Video can not be deleted because conditions are'nt matched."})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Error(err)
 		return
 	}
+	defer download.Content.Close()
 
-	// Éxito: 200 OK con el mensaje y video_id
-	c.JSON(http.StatusOK, gin.H{
-		"message":  "El video ha sido eliminado exitosamente.",
-		"video_id": videoID,
+	header := c.Writer.Header()
+	header.Set("Content-Type", download.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	if download.ETag != "" {
+		header.Set("ETag", download.ETag)
+	}
+	if userID != 0 {
+		header.Set("Cache-Control", "private, no-cache")
+	} else {
+		header.Set("Cache-Control", "public, max-age=300")
+	}
+
+	writer := newThrottledWriter(c.Request.Context(), c.Writer, vc.downloadRate)
+	http.ServeContent(writer, c.Request, download.Filename, download.ModTime, download.Content)
+}
+
+func (vc *VideoController) DeleteVideo(c *gin.Context) {
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID, err := currentUser(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := vc.videoService.Delete(videoID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Éxito: 200 OK con el mensaje, video_id y el plazo para restaurarlo
+	c.JSON(http.StatusOK, DeleteVideoResponse{
+		Message:      i18n.Message(c, "video.deleted"),
+		VideoID:      videoID,
+		RestoreUntil: time.Now().Add(RestoreWindow),
 	})
 }
 
-func (vc *VideoController) ListPublicVideos(c *gin.Context) {
-	videos, err := vc.videoService.ListPublic()
+func (vc *VideoController) ListTrash(c *gin.Context) {
+	userID, err := currentUser(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Videos can't be fetched"})
+		c.Error(err)
+		return
+	}
+
+	page, err := pagination.Parse(c, TrashSpec)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	videos, err := vc.videoService.ListTrash(userID, page)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, videos)
 }
 
-func (vc *VideoController) MarkVideoAsProcessed(c *gin.Context) {
-	videoIDStr := c.Param("video_id")
-	videoID, err := strconv.ParseUint(videoIDStr, 10, 32)
+func (vc *VideoController) RestoreVideo(c *gin.Context) {
+	videoID, err := parseVideoID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID, err := currentUser(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID format"})
+		c.Error(err)
 		return
 	}
-	userIDClaim, _ := c.Get("userID")
-	userID := userIDClaim.(uint)
 
-	video, err := vc.videoService.MarkAsProcessed(uint(videoID), userID)
+	if err := vc.videoService.Restore(videoID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RestoreVideoResponse{
+		Message: i18n.Message(c, "video.restored"),
+		VideoID: videoID,
+	})
+}
+
+// ListPublicVideos admite ?category= y ?tags= (separados por comas o repetidos)
+func (vc *VideoController) ListPublicVideos(c *gin.Context) {
+	filter := PublicVideoFilter{
+		Category: c.Query("category"),
+		Tags:     c.QueryArray("tags"),
+	}
+
+	page, err := pagination.Parse(c, PublicListSpec)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		if strings.Contains(err.Error(), "permission") {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, video)
+	videos, err := vc.videoService.ListPublic(filter, page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, videos)
 }
 
 func (vc *VideoController) GetRankings(c *gin.Context) {
-	rankings, err := vc.videoService.GetRankings()
+	page, err := pagination.Parse(c, RankingsSpec)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	rankings, err := vc.videoService.GetRankings(page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve rankings."})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, rankings)
 }
+
+// Search busca en los videos públicos por título, descripción, jugador y ciudad.
+// Parámetros: q (obligatorio), limit y cursor.
+func (vc *VideoController) Search(c *gin.Context) {
+	query, err := NewSearchQuery(c.Query("q"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := pagination.Parse(c, SearchSpec)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	results, err := vc.videoService.Search(query, page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
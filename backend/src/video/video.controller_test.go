@@ -1,12 +1,15 @@
 package video
 
 import (
+	"anb-app/src/apperr/apperrtest"
+	"anb-app/src/pagination"
+	"context"
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -25,9 +28,9 @@ func (m *MockVideoService) Upload(ctx *gin.Context, req *UploadVideoRequest, fil
 	return args.Get(0).(*VideoResponse), args.Error(1)
 }
 
-func (m *MockVideoService) ListByUserID(userID uint) ([]VideoResponse, error) {
-	args := m.Called(userID)
-	return args.Get(0).([]VideoResponse), args.Error(1)
+func (m *MockVideoService) ListByUserID(userID uint, page pagination.Request) (pagination.Page[VideoResponse], error) {
+	args := m.Called(userID, page)
+	return args.Get(0).(pagination.Page[VideoResponse]), args.Error(1)
 }
 
 func (m *MockVideoService) GetByID(videoID uint, userID uint) (*VideoResponse, error) {
@@ -43,22 +46,55 @@ func (m *MockVideoService) Delete(videoID uint, userID uint) error {
 	return args.Error(0)
 }
 
-func (m *MockVideoService) ListPublic() ([]VideoResponse, error) {
-	args := m.Called()
-	return args.Get(0).([]VideoResponse), args.Error(1)
+func (m *MockVideoService) ListTrash(userID uint, page pagination.Request) (pagination.Page[TrashedVideoResponse], error) {
+	args := m.Called(userID, page)
+	return args.Get(0).(pagination.Page[TrashedVideoResponse]), args.Error(1)
 }
 
-func (m *MockVideoService) MarkAsProcessed(videoID uint, userID uint) (*VideoResponse, error) {
+func (m *MockVideoService) Restore(videoID uint, userID uint) error {
 	args := m.Called(videoID, userID)
+	return args.Error(0)
+}
+
+func (m *MockVideoService) Update(videoID uint, userID uint, req *UpdateVideoRequest) (*VideoResponse, error) {
+	args := m.Called(videoID, userID, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*VideoResponse), args.Error(1)
 }
 
-func (m *MockVideoService) GetRankings() ([]RankingResponse, error) {
-	args := m.Called()
-	return args.Get(0).([]RankingResponse), args.Error(1)
+func (m *MockVideoService) ListPublic(filter PublicVideoFilter, page pagination.Request) (pagination.Page[PublicVideoResponse], error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(pagination.Page[PublicVideoResponse]), args.Error(1)
+}
+
+func (m *MockVideoService) GetRankings(page pagination.Request) (pagination.Page[RankingResponse], error) {
+	args := m.Called(page)
+	return args.Get(0).(pagination.Page[RankingResponse]), args.Error(1)
+}
+
+func (m *MockVideoService) Search(query SearchQuery, page pagination.Request) (*SearchResponse, error) {
+	args := m.Called(query, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SearchResponse), args.Error(1)
+}
+
+func (m *MockVideoService) OpenDownload(ctx context.Context, videoID uint, userID uint, variant string) (*Download, error) {
+	args := m.Called(videoID, userID, variant)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Download), args.Error(1)
+}
+
+// anyPage acepta cualquier petición de página con el límite indicado
+func anyPage(limit int) interface{} {
+	return mock.MatchedBy(func(page pagination.Request) bool {
+		return page.Limit == limit
+	})
 }
 
 // Helper para crear contexto con userID
@@ -78,12 +114,12 @@ func TestVideoController(t *testing.T) {
 		controller := NewVideoController(mockSvc)
 
 		userID := uint(1)
-		videos := []VideoResponse{
+		videos := pagination.Page[VideoResponse]{Items: []VideoResponse{
 			{ID: 1, UserID: userID, Title: "Test Video", Status: "processed"},
 			{ID: 2, UserID: userID, Title: "Test Video 2", Status: "uploaded"},
-		}
+		}, Limit: pagination.DefaultLimit}
 
-		mockSvc.On("ListByUserID", userID).Return(videos, nil)
+		mockSvc.On("ListByUserID", userID, anyPage(pagination.DefaultLimit)).Return(videos, nil)
 
 		req := httptest.NewRequest("GET", "/videos", nil)
 		w := httptest.NewRecorder()
@@ -92,14 +128,15 @@ func TestVideoController(t *testing.T) {
 		c.Request = req
 		c.Set("userID", userID)
 
-		controller.ListMyVideos(c)
+		apperrtest.Handle(controller.ListMyVideos, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response []VideoResponse
+		var response pagination.Page[VideoResponse]
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Len(t, response, 2)
-		assert.Equal(t, "Test Video", response[0].Title)
+		assert.Len(t, response.Items, 2)
+		assert.Equal(t, "Test Video", response.Items[0].Title)
+		assert.Nil(t, response.NextCursor)
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -115,13 +152,12 @@ func TestVideoController(t *testing.T) {
 		c.Request = req
 		// No se establece userID
 
-		controller.ListMyVideos(c)
+		apperrtest.Handle(controller.ListMyVideos, c)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "User not authenticated", response["error"])
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, "unauthenticated", problem.Code)
 	})
 
 	t.Run("GetVideoByID_Success", func(t *testing.T) {
@@ -147,7 +183,7 @@ func TestVideoController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.GetVideoByID(c)
+		apperrtest.Handle(controller.GetVideoByID, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
@@ -176,7 +212,7 @@ func TestVideoController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "999"}}
 
-		controller.GetVideoByID(c)
+		apperrtest.Handle(controller.GetVideoByID, c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 
@@ -195,13 +231,13 @@ func TestVideoController(t *testing.T) {
 		c.Set("userID", uint(1))
 		c.Params = []gin.Param{{Key: "video_id", Value: "invalid"}}
 
-		controller.GetVideoByID(c)
+		apperrtest.Handle(controller.GetVideoByID, c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Invalid video ID format", response["error"])
+		problem := apperrtest.Decode(t, w)
+		assert.Equal(t, "invalid_video_id", problem.Code)
+		assert.Equal(t, "video_id", problem.Errors[0].Field)
 	})
 
 	t.Run("DeleteVideo_Success", func(t *testing.T) {
@@ -221,7 +257,7 @@ func TestVideoController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.DeleteVideo(c)
+		apperrtest.Handle(controller.DeleteVideo, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
@@ -233,82 +269,197 @@ func TestVideoController(t *testing.T) {
 		mockSvc.AssertExpectations(t)
 	})
 
+	t.Run("RestoreVideo_Success", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+
+		mockSvc.On("Restore", uint(1), uint(1)).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/videos/1/restore", nil)
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
+
+		apperrtest.Handle(controller.RestoreVideo, c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("RestoreVideo_WindowExpired", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+
+		mockSvc.On("Restore", uint(1), uint(1)).Return(ErrRestoreExpired)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/videos/1/restore", nil)
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
+
+		apperrtest.Handle(controller.RestoreVideo, c)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+	})
+
 	t.Run("ListPublicVideos_Success", func(t *testing.T) {
 		mockSvc := new(MockVideoService)
 		controller := NewVideoController(mockSvc)
 
-		videos := []VideoResponse{
-			{ID: 1, Title: "Public Video 1", Status: "processed", VoteCount: 10},
-			{ID: 2, Title: "Public Video 2", Status: "processed", VoteCount: 5},
-		}
+		next := "next-page"
+		videos := pagination.Page[PublicVideoResponse]{Items: []PublicVideoResponse{
+			{ID: 1, Title: "Public Video 1", VoteCount: 10},
+			{ID: 2, Title: "Public Video 2", VoteCount: 5},
+		}, NextCursor: &next, Limit: 2}
 
-		mockSvc.On("ListPublic").Return(videos, nil)
+		mockSvc.On("ListPublic", PublicVideoFilter{}, anyPage(2)).Return(videos, nil)
 
-		req := httptest.NewRequest("GET", "/public/videos", nil)
+		req := httptest.NewRequest("GET", "/public/videos?limit=2", nil)
 		w := httptest.NewRecorder()
 
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		controller.ListPublicVideos(c)
+		apperrtest.Handle(controller.ListPublicVideos, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response []VideoResponse
+		var response pagination.Page[PublicVideoResponse]
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Len(t, response, 2)
-		assert.Equal(t, "Public Video 1", response[0].Title)
+		assert.Len(t, response.Items, 2)
+		assert.Equal(t, "Public Video 1", response.Items[0].Title)
+		assert.Equal(t, "next-page", *response.NextCursor)
 
 		mockSvc.AssertExpectations(t)
 	})
 
-	t.Run("MarkVideoAsProcessed_Success", func(t *testing.T) {
+	t.Run("ListPublicVideos_Filters", func(t *testing.T) {
 		mockSvc := new(MockVideoService)
 		controller := NewVideoController(mockSvc)
 
-		videoID := uint(1)
-		userID := uint(1)
-		now := time.Now()
-		videoResp := &VideoResponse{
-			ID:          videoID,
-			UserID:      userID,
-			Title:       "Test Video",
-			Status:      "processed",
-			ProcessedAt: &now,
-		}
+		expected := PublicVideoFilter{Category: "dunk", Tags: []string{"final,u18", "mvp"}}
+		mockSvc.On("ListPublic", expected, anyPage(pagination.DefaultLimit)).Return(pagination.Page[PublicVideoResponse]{Items: []PublicVideoResponse{}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/public/videos?category=dunk&tags=final,u18&tags=mvp", nil)
+
+		apperrtest.Handle(controller.ListPublicVideos, c)
 
-		mockSvc.On("MarkAsProcessed", videoID, userID).Return(videoResp, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("ListPublicVideos_InvalidSort", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
 
-		req := httptest.NewRequest("POST", "/videos/1/mark-processed", nil)
 		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/public/videos?sort=title", nil)
+
+		apperrtest.Handle(controller.ListPublicVideos, c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "ListPublic", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UpdateVideo_Success", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
 
+		mockSvc.On("Update", uint(1), uint(1), mock.MatchedBy(func(req *UpdateVideoRequest) bool {
+			return req.Category != nil && *req.Category == "dunk" && req.Title == nil
+		})).Return(&VideoResponse{ID: 1, Title: "Clip", Category: "dunk", Tags: []string{}}, nil)
+
+		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Set("userID", userID)
+		c.Request = httptest.NewRequest("PATCH", "/videos/1", strings.NewReader(`{"category":"dunk"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("userID", uint(1))
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.MarkVideoAsProcessed(c)
+		apperrtest.Handle(controller.UpdateVideo, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-
 		var response VideoResponse
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "processed", response.Status)
-		assert.NotNil(t, response.ProcessedAt)
+		assert.Equal(t, "dunk", response.Category)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("UpdateVideo_InvalidMetadata", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+
+		mockSvc.On("Update", uint(1), uint(1), mock.Anything).Return(nil, invalidField(ErrInvalidMetadata, "category", "invalid category: must be one of dunk"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PATCH", "/videos/1", strings.NewReader(`{"category":"slam"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("userID", uint(1))
+		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
+
+		apperrtest.Handle(controller.UpdateVideo, c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Search_Success", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+
+		query := SearchQuery{Text: "mate giro"}
+		mockSvc.On("Search", query, anyPage(5)).Return(&SearchResponse{
+			Page: pagination.Page[SearchResult]{
+				Items: []SearchResult{{VideoID: 3, TitleHighlight: "Dunk de Luis - <mark>Mate</mark> con <mark>giro</mark>"}},
+				Limit: 5,
+			},
+			Query: "mate giro",
+			Total: 6,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/public/search?q=mate+giro&limit=5", nil)
 
+		apperrtest.Handle(controller.Search, c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response SearchResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, int64(6), response.Total)
+		assert.Contains(t, response.Items[0].TitleHighlight, "<mark>Mate</mark>")
 		mockSvc.AssertExpectations(t)
 	})
 
+	t.Run("Search_MissingQuery", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/public/search", nil)
+
+		apperrtest.Handle(controller.Search, c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+
 	t.Run("GetRankings_Success", func(t *testing.T) {
 		mockSvc := new(MockVideoService)
 		controller := NewVideoController(mockSvc)
 
-		rankings := []RankingResponse{
+		rankings := pagination.Page[RankingResponse]{Items: []RankingResponse{
 			{Position: 1, VideoID: 1, Title: "Top Video", VoteCount: 100},
 			{Position: 2, VideoID: 2, Title: "Second Video", VoteCount: 50},
-		}
+		}, Limit: pagination.DefaultLimit}
 
-		mockSvc.On("GetRankings").Return(rankings, nil)
+		mockSvc.On("GetRankings", anyPage(pagination.DefaultLimit)).Return(rankings, nil)
 
 		req := httptest.NewRequest("GET", "/public/rankings", nil)
 		w := httptest.NewRecorder()
@@ -316,15 +467,15 @@ func TestVideoController(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		controller.GetRankings(c)
+		apperrtest.Handle(controller.GetRankings, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response []RankingResponse
+		var response pagination.Page[RankingResponse]
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Len(t, response, 2)
-		assert.Equal(t, 1, response[0].Position)
-		assert.Equal(t, "Top Video", response[0].Title)
+		assert.Len(t, response.Items, 2)
+		assert.Equal(t, 1, response.Items[0].Position)
+		assert.Equal(t, "Top Video", response.Items[0].Title)
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -0,0 +1,174 @@
+package video
+
+import (
+	"anb-app/src/storage"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Archivos que se pueden descargar por la API
+const (
+	VariantOriginal  = "original"
+	VariantProcessed = "processed"
+)
+
+// Download es un archivo listo para entregarse con http.ServeContent. Content lee del
+// almacenamiento solo los rangos que se sirven; quien lo recibe debe cerrarlo.
+type Download struct {
+	Filename    string
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+	Size        int64
+	Content     io.ReadSeekCloser
+}
+
+// OpenDownload aplica las reglas de acceso y abre el archivo. userID 0 es una descarga
+// pública: solo del video procesado, y los videos sin procesar se reportan como inexistentes.
+// El dueño puede descargar el original y el procesado.
+func (s *videoService) OpenDownload(ctx context.Context, videoID uint, userID uint, variant string) (*Download, error) {
+	if variant != VariantOriginal && variant != VariantProcessed {
+		return nil, ErrInvalidVariant
+	}
+
+	video, err := s.videoRepo.FindByID(videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video == nil {
+		return nil, ErrVideoNotFound
+	}
+
+	public := userID == 0
+	if !public && video.UserID != userID {
+		return nil, ErrVideoForbidden.WithMessage("user does not have permission to download this video")
+	}
+
+	key := video.ProcessedURL
+	switch {
+	case variant == VariantOriginal && public:
+		return nil, ErrOriginalForbidden
+	case variant == VariantOriginal:
+		key = video.OriginalURL
+	case video.Status != "processed" || key == "":
+		if public {
+			return nil, ErrVideoNotFound
+		}
+		return nil, ErrProcessedNotReady
+	}
+
+	info, err := s.storageSvc.HeadObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, ErrVideoFileNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &Download{
+		Filename:    downloadFilename(video.Title, variant, key),
+		ContentType: cmp.Or(info.ContentType, mime.TypeByExtension(path.Ext(key)), "application/octet-stream"),
+		ETag:        info.ETag,
+		ModTime:     info.LastModified,
+		Size:        info.Size,
+		Content:     storage.NewObjectReader(ctx, s.storageSvc, key, info.Size),
+	}, nil
+}
+
+// downloadFilename arma un nombre legible a partir del título: "mate-del-año.mp4"
+// o "mate-del-año-original.mov"
+func downloadFilename(title, variant, key string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			slug.WriteRune(r)
+			dash = false
+		case !dash && slug.Len() > 0:
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(cmp.Or(slug.String(), "video"), "-")
+	if variant == VariantOriginal {
+		name += "-original"
+	}
+	return name + path.Ext(key)
+}
+
+// throttledWriter limita los bytes por segundo de una respuesta. Escribe en bloques y
+// duerme lo necesario para no adelantarse al ritmo permitido.
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx          context.Context
+	bytesPerSec  int64
+	started      time.Time
+	written      int64
+	throttleFrom int64
+	now          func() time.Time
+	sleep        func(ctx context.Context, d time.Duration) error
+}
+
+const throttleChunk = 32 * 1024
+
+func newThrottledWriter(ctx context.Context, w http.ResponseWriter, bytesPerSec int64) http.ResponseWriter {
+	if bytesPerSec <= 0 {
+		return w
+	}
+	return &throttledWriter{
+		ResponseWriter: w,
+		ctx:            ctx,
+		bytesPerSec:    bytesPerSec,
+		// El primer segundo sale sin espera para que el reproductor arranque rápido
+		throttleFrom: bytesPerSec,
+		now:          time.Now,
+		sleep:        sleepContext,
+	}
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if w.started.IsZero() {
+		w.started = w.now()
+	}
+	total := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), throttleChunk)]
+		n, err := w.ResponseWriter.Write(chunk)
+		total += n
+		w.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+
+		if w.written > w.throttleFrom {
+			expected := time.Duration(float64(w.written-w.throttleFrom) / float64(w.bytesPerSec) * float64(time.Second))
+			if wait := expected - w.now().Sub(w.started); wait > 0 {
+				if err := w.sleep(w.ctx, wait); err != nil {
+					return total, err
+				}
+			}
+		}
+	}
+	return total, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,258 @@
+package video
+
+import (
+	"anb-app/src/apperr"
+	"anb-app/src/storage"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// readSeekNopCloser permite usar un bytes.Reader como contenido de una descarga
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error { return nil }
+
+func TestOpenDownload(t *testing.T) {
+	ctx := context.Background()
+	processed := &Video{ID: 1, UserID: 7, Title: "Mate del Año!", Status: "processed", OriginalURL: "originals/1.mov", ProcessedURL: "processed/1.mp4"}
+	uploaded := &Video{ID: 2, UserID: 7, Title: "Pendiente", Status: "uploaded", OriginalURL: "originals/2.mov"}
+
+	newService := func() (VideoService, *MockVideoRepository, *MockStorageService) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		mockRepo.On("FindByID", uint(1)).Return(processed, nil)
+		mockRepo.On("FindByID", uint(2)).Return(uploaded, nil)
+		return NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), new(MockURLSigner)), mockRepo, mockStorage
+	}
+
+	t.Run("PublicProcessed", func(t *testing.T) {
+		svc, _, mockStorage := newService()
+		modified := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+		mockStorage.On("HeadObject", ctx, "processed/1.mp4").Return(storage.ObjectInfo{Size: 10, ETag: `"abc"`, LastModified: modified}, nil)
+
+		download, err := svc.OpenDownload(ctx, 1, 0, VariantProcessed)
+
+		require.NoError(t, err)
+		assert.Equal(t, "mate-del-año.mp4", download.Filename)
+		assert.Equal(t, "video/mp4", download.ContentType)
+		assert.Equal(t, `"abc"`, download.ETag)
+		assert.Equal(t, int64(10), download.Size)
+	})
+
+	t.Run("OwnerOriginal", func(t *testing.T) {
+		svc, _, mockStorage := newService()
+		mockStorage.On("HeadObject", ctx, "originals/1.mov").Return(storage.ObjectInfo{Size: 20, ContentType: "video/quicktime"}, nil)
+
+		download, err := svc.OpenDownload(ctx, 1, 7, VariantOriginal)
+
+		require.NoError(t, err)
+		assert.Equal(t, "mate-del-año-original.mov", download.Filename)
+		assert.Equal(t, "video/quicktime", download.ContentType)
+	})
+
+	t.Run("PublicOriginalForbidden", func(t *testing.T) {
+		svc, _, mockStorage := newService()
+
+		_, err := svc.OpenDownload(ctx, 1, 0, VariantOriginal)
+
+		assert.ErrorContains(t, err, "permission")
+		mockStorage.AssertNotCalled(t, "HeadObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("OtherUserForbidden", func(t *testing.T) {
+		svc, _, _ := newService()
+
+		_, err := svc.OpenDownload(ctx, 1, 8, VariantProcessed)
+
+		assert.ErrorContains(t, err, "permission")
+	})
+
+	t.Run("PublicUnprocessedIsNotFound", func(t *testing.T) {
+		svc, _, _ := newService()
+
+		_, err := svc.OpenDownload(ctx, 2, 0, VariantProcessed)
+
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("OwnerUnprocessedNotAvailable", func(t *testing.T) {
+		svc, _, _ := newService()
+
+		_, err := svc.OpenDownload(ctx, 2, 7, VariantProcessed)
+
+		assert.ErrorContains(t, err, "not available")
+	})
+
+	t.Run("MissingObject", func(t *testing.T) {
+		svc, _, mockStorage := newService()
+		mockStorage.On("HeadObject", ctx, "processed/1.mp4").Return(storage.ObjectInfo{}, storage.ErrObjectNotFound)
+
+		_, err := svc.OpenDownload(ctx, 1, 0, VariantProcessed)
+
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("InvalidVariant", func(t *testing.T) {
+		svc, _, _ := newService()
+
+		_, err := svc.OpenDownload(ctx, 1, 7, "thumbnail")
+
+		assert.ErrorContains(t, err, "invalid variant")
+	})
+
+	t.Run("ContentReadsFromOffset", func(t *testing.T) {
+		svc, _, mockStorage := newService()
+		mockStorage.On("HeadObject", ctx, "processed/1.mp4").Return(storage.ObjectInfo{Size: 10}, nil)
+		mockStorage.On("GetObject", ctx, "processed/1.mp4", int64(4)).Return(io.NopCloser(strings.NewReader("456789")), nil)
+
+		download, err := svc.OpenDownload(ctx, 1, 0, VariantProcessed)
+		require.NoError(t, err)
+		download.Content.Seek(4, io.SeekStart)
+		data, err := io.ReadAll(download.Content)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "456789", string(data))
+		mockStorage.AssertExpectations(t)
+	})
+}
+
+func TestDownloadController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	modified := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	newDownload := func() *Download {
+		return &Download{
+			Filename:    "mate-del-año.mp4",
+			ContentType: "video/mp4",
+			ETag:        `"abc"`,
+			ModTime:     modified,
+			Size:        10,
+			Content:     readSeekNopCloser{bytes.NewReader([]byte("0123456789"))},
+		}
+	}
+	serve := func(controller *VideoController, handler gin.HandlerFunc, req *http.Request, userID uint) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
+		if userID != 0 {
+			c.Set("userID", userID)
+		}
+		handler(c)
+		apperr.Middleware()(c)
+		// gin escribe el estado pendiente al terminar la cadena de handlers
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	t.Run("FullFile", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+		mockSvc.On("OpenDownload", uint(1), uint(0), VariantProcessed).Return(newDownload(), nil)
+
+		w := serve(controller, controller.DownloadPublic, httptest.NewRequest("GET", "/public/videos/1/download", nil), 0)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0123456789", w.Body.String())
+		assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "attachment; filename*=utf-8''mate-del-a%C3%B1o.mp4", w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "public")
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+		mockSvc.On("OpenDownload", uint(1), uint(7), VariantOriginal).Return(newDownload(), nil)
+		req := httptest.NewRequest("GET", "/videos/1/download", nil)
+		req.Header.Set("Range", "bytes=2-5")
+
+		w := serve(controller, controller.Download, req, 7)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "2345", w.Body.String())
+		assert.Equal(t, "bytes 2-5/10", w.Header().Get("Content-Range"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "private")
+	})
+
+	t.Run("UnsatisfiableRange", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+		mockSvc.On("OpenDownload", uint(1), uint(0), VariantProcessed).Return(newDownload(), nil)
+		req := httptest.NewRequest("GET", "/public/videos/1/download", nil)
+		req.Header.Set("Range", "bytes=20-30")
+
+		w := serve(controller, controller.DownloadPublic, req, 0)
+
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	})
+
+	t.Run("IfNoneMatch", func(t *testing.T) {
+		mockSvc := new(MockVideoService)
+		controller := NewVideoController(mockSvc)
+		mockSvc.On("OpenDownload", uint(1), uint(0), VariantProcessed).Return(newDownload(), nil)
+		req := httptest.NewRequest("GET", "/public/videos/1/download", nil)
+		req.Header.Set("If-None-Match", `"abc"`)
+
+		w := serve(controller, controller.DownloadPublic, req, 0)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("ErrorMapping", func(t *testing.T) {
+		cases := map[error]int{
+			ErrInvalidVariant:              http.StatusBadRequest,
+			ErrVideoNotFound:               http.StatusNotFound,
+			ErrOriginalForbidden:           http.StatusForbidden,
+			ErrProcessedNotReady:           http.StatusConflict,
+			errors.New("connection reset"): http.StatusInternalServerError,
+		}
+		for err, status := range cases {
+			mockSvc := new(MockVideoService)
+			controller := NewVideoController(mockSvc)
+			mockSvc.On("OpenDownload", uint(1), uint(0), "original").Return(nil, err)
+
+			w := serve(controller, controller.DownloadPublic, httptest.NewRequest("GET", "/public/videos/1/download?variant=original", nil), 0)
+
+			assert.Equal(t, status, w.Code, err.Error())
+			assert.Equal(t, apperr.ContentType, w.Header().Get("Content-Type"))
+		}
+	})
+}
+
+func TestThrottledWriter(t *testing.T) {
+	var slept time.Duration
+	now := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	rec := httptest.NewRecorder()
+	writer := newThrottledWriter(context.Background(), rec, 64*1024).(*throttledWriter)
+	writer.now = func() time.Time { return now }
+	writer.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		now = now.Add(d)
+		return nil
+	}
+
+	// 64 KB de ráfaga inicial más 128 KB a 64 KB/s: unos dos segundos de espera
+	n, err := writer.Write(make([]byte, 192*1024))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 192*1024, n)
+	assert.Equal(t, 192*1024, rec.Body.Len())
+	assert.InDelta(t, 2*time.Second, slept, float64(200*time.Millisecond))
+
+	assert.Same(t, rec, newThrottledWriter(context.Background(), rec, 0))
+}
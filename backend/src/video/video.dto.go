@@ -1,27 +1,166 @@
 package video
 
-import "time"
+import (
+	"anb-app/src/pagination"
+	"time"
+)
 
 type UploadVideoRequest struct {
-	Title string `json:"title" form:"title" validate:"required"`
+	Title       string `json:"title" form:"title" validate:"required"`
+	Description string `json:"description" form:"description" validate:"max=2000"`
+	Category    string `json:"category" form:"category"`
+	// Se aceptan varios campos tags o uno solo separado por comas
+	Tags       []string   `json:"tags" form:"tags"`
+	RecordedAt *time.Time `json:"recorded_at" form:"recorded_at" time_format:"2006-01-02"`
 }
 
+// UpdateVideoRequest es el cuerpo de PATCH /videos/:id; solo se cambian los campos presentes
+type UpdateVideoRequest struct {
+	Title       *string    `json:"title" validate:"omitempty,min=1,max=200"`
+	Description *string    `json:"description" validate:"omitempty,max=2000"`
+	Category    *string    `json:"category"`
+	Tags        *[]string  `json:"tags"`
+	RecordedAt  *time.Time `json:"recorded_at"`
+}
+
+// UploadVideoResponse es la respuesta de POST /videos/upload; task_id sirve para consultar
+// GET /tasks/:task_id
+type UploadVideoResponse struct {
+	Message string `json:"message"`
+	TaskID  string `json:"task_id"`
+}
+
+// DeleteVideoResponse indica hasta cuándo se puede restaurar el video retirado
+type DeleteVideoResponse struct {
+	Message      string    `json:"message"`
+	VideoID      uint      `json:"video_id"`
+	RestoreUntil time.Time `json:"restore_until"`
+}
+
+type RestoreVideoResponse struct {
+	Message string `json:"message"`
+	VideoID uint   `json:"video_id"`
+}
+
+// PublicVideoFilter filtra el listado público; con varios tags el video debe tenerlos todos
+type PublicVideoFilter struct {
+	Category string
+	Tags     []string
+}
+
+// VideoResponse es el video visto por su dueño: incluye el original, el estado del
+// procesamiento y el motivo de rechazo. Las rutas públicas usan PublicVideoResponse.
 type VideoResponse struct {
 	ID           uint       `json:"id"`
 	UserID       uint       `json:"user_id"`
 	Title        string     `json:"title"`
 	Status       string     `json:"status"`
-	OriginalURL  string     `json:"original_url"`
+	OriginalURL  string     `json:"original_url,omitempty"`
 	ProcessedURL string     `json:"processed_url,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	PreviewURL   string     `json:"preview_url,omitempty"`
 	VoteCount    int        `json:"votes"`
 	UploadedAt   time.Time  `json:"uploaded_at"`
 	ProcessedAt  *time.Time `json:"processed_at,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	Tags         []string   `json:"tags"`
+	RecordedAt   *time.Time `json:"recorded_at,omitempty"`
+	// Motivo del rechazo cuando Status es "failed"
+	FailureReason string `json:"failure_reason,omitempty"`
+	// Tarea de procesamiento creada al subir el video (solo en la respuesta de upload)
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// PublicVideoResponse es un video en las rutas públicas. No lleva el original, el estado
+// ni datos del procesamiento; las URLs son siempre de los archivos procesados.
+type PublicVideoResponse struct {
+	ID           uint       `json:"id"`
+	UserID       uint       `json:"user_id"`
+	Title        string     `json:"title"`
+	ProcessedURL string     `json:"processed_url,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	PreviewURL   string     `json:"preview_url,omitempty"`
+	VoteCount    int        `json:"votes"`
+	ProcessedAt  *time.Time `json:"processed_at,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	Tags         []string   `json:"tags"`
+	RecordedAt   *time.Time `json:"recorded_at,omitempty"`
+}
+
+// SearchResult es un video de la búsqueda pública. Los campos *_highlight traen el texto
+// escapado con las coincidencias entre <mark> y </mark>.
+type SearchResult struct {
+	VideoID              uint    `json:"video_id"`
+	Title                string  `json:"title"`
+	Category             string  `json:"category,omitempty"`
+	VoteCount            int     `json:"votes"`
+	ThumbnailURL         string  `json:"thumbnail_url,omitempty"`
+	PreviewURL           string  `json:"preview_url,omitempty"`
+	PlayerName           string  `json:"player_name"`
+	City                 string  `json:"city,omitempty"`
+	Rank                 float64 `json:"rank"`
+	TitleHighlight       string  `json:"title_highlight"`
+	DescriptionHighlight string  `json:"description_highlight,omitempty"`
+	PlayerHighlight      string  `json:"player_highlight"`
+	CityHighlight        string  `json:"city_highlight,omitempty"`
+}
+
+// SearchResponse es el sobre de paginación común más el texto buscado y el total de coincidencias
+type SearchResponse struct {
+	pagination.Page[SearchResult]
+	Query string `json:"query"`
+	Total int64  `json:"total"`
+}
+
+// TrashedVideoResponse es un video retirado que todavía se puede restaurar
+type TrashedVideoResponse struct {
+	ID           uint      `json:"id"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	VoteCount    int       `json:"votes"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	RestoreUntil time.Time `json:"restore_until"`
 }
 
 type RankingResponse struct {
-    Position    int    `json:"position"`
-    VideoID     uint   `json:"video_id"`
-    Title       string `json:"title"`
-    AuthorName  string `json:"author_name"`
-    VoteCount   int    `json:"votes"`
-}
\ No newline at end of file
+    Position     int    `json:"position"`
+    VideoID      uint   `json:"video_id"`
+    Title        string `json:"title"`
+    AuthorName   string `json:"author_name"`
+    VoteCount    int    `json:"votes"`
+    ThumbnailURL string `json:"thumbnail_url,omitempty"`
+    PreviewURL   string `json:"preview_url,omitempty"`
+}
+
+// RequeueRequest es el filtro de POST /admin/videos/requeue; al menos un criterio es obligatorio
+type RequeueRequest struct {
+	Status          string     `json:"status" validate:"omitempty,oneof=uploaded processed failed"`
+	UploadedFrom    *time.Time `json:"uploaded_from"`
+	UploadedTo      *time.Time `json:"uploaded_to"`
+	PipelineVersion string     `json:"pipeline_version"`
+	Limit           int        `json:"limit" validate:"omitempty,min=1"`
+	RatePerSecond   float64    `json:"rate_per_second" validate:"omitempty,gt=0,lte=50"`
+	DryRun          bool       `json:"dry_run"`
+	Force           bool       `json:"force"`
+}
+
+type RequeuedVideo struct {
+	VideoID         uint       `json:"video_id"`
+	Title           string     `json:"title"`
+	Status          string     `json:"status"`
+	PipelineVersion string     `json:"pipeline_version,omitempty"`
+	TaskID          string     `json:"task_id,omitempty"`
+	AvailableAt     *time.Time `json:"available_at,omitempty"`
+}
+
+type RequeueResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Matched int             `json:"matched"`
+	Queued  int             `json:"queued"`
+	Videos  []RequeuedVideo `json:"videos"`
+	// Error detiene la operación a mitad; los videos listados sí quedaron encolados
+	Error string `json:"error,omitempty"`
+}
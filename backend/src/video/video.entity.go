@@ -1,10 +1,17 @@
 package video
 
 import (
+	"anb-app/src/outbox"
 	"anb-app/src/user"
+	"anb-app/src/webhook"
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// Categorías de jugada admitidas
+var Categories = []string{"dunk", "three-pointer", "defense", "assist", "crossover", "block", "layup", "free-throw", "other"}
+
 type Video struct {
 	ID           uint       `json:"id" gorm:"primaryKey"`
 	UserID       uint       `json:"user_id" gorm:"not null"`
@@ -12,9 +19,49 @@ type Video struct {
 	Status       string     `json:"status" gorm:"default:'uploaded'"`
 	OriginalURL  string     `json:"original_url"`
 	ProcessedURL string     `json:"processed_url,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	PreviewURL   string     `json:"preview_url,omitempty"`
 	VoteCount    int        `json:"votes" gorm:"default:0"`
 	UploadedAt   time.Time  `json:"uploaded_at"`
 	ProcessedAt  *time.Time `json:"processed_at,omitempty"`
+	// Metadatos editables por el dueño
+	Description string `json:"description,omitempty" gorm:"type:text"`
+	// Categoría de la jugada (ver Categories); se filtra en el listado público
+	Category string `json:"category,omitempty" gorm:"index"`
+	// Etiquetas libres en minúsculas; el índice GIN resuelve los filtros tags @> '["..."]'
+	Tags       []string   `json:"tags" gorm:"type:jsonb;serializer:json;default:'[]';index:idx_videos_tags,type:gin"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+	// Pipeline que generó el video procesado (p. ej. "anb-default@v1")
+	PipelineVersion string `json:"pipeline_version,omitempty" gorm:"index"`
+	// Loudness integrada (EBU R128) medida antes de normalizar el audio
+	LoudnessLUFS *float64 `json:"loudness_lufs,omitempty"`
+	// Motivo por el que el worker rechazó el video (corrupto, sin contenido, silencioso)
+	FailureReason string `json:"failure_reason,omitempty"`
+	// Retirado por el dueño: oculto en todas las consultas hasta que se restaure o se purgue
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	// Último intento fallido de purgarlo (p. ej. S3 no respondió)
+	PurgeAttemptedAt *time.Time `json:"-"`
 
 	User user.User `json:"-" gorm:"foreignKey:UserID"`
 }
+
+// Columnas que edita el dueño del video (PATCH /api/videos/:id)
+var metadataColumns = []string{"title", "description", "category", "tags", "recorded_at"}
+
+// Columnas que escriben el worker y el reencolado; el resto de la fila no lo tocan
+var processingColumns = []string{
+	"status", "failure_reason", "processed_url", "thumbnail_url", "preview_url",
+	"processed_at", "pipeline_version", "loudness_lufs",
+}
+
+// NewWebhookEvent arma el mensaje del outbox para un evento video.* de webhooks
+func NewWebhookEvent(eventType string, v *Video) (outbox.Message, error) {
+	return webhook.NewEvent(eventType, webhook.VideoData{
+		VideoID:         v.ID,
+		UserID:          v.UserID,
+		Title:           v.Title,
+		Status:          v.Status,
+		PipelineVersion: v.PipelineVersion,
+		FailureReason:   v.FailureReason,
+	})
+}
@@ -0,0 +1,30 @@
+package video
+
+import "anb-app/src/apperr"
+
+// Errores de videos con código estable; los mensajes se mantienen para los logs y los tests
+var (
+	ErrVideoNotFound      = apperr.NotFound("video_not_found", "video not found")
+	ErrVideoForbidden     = apperr.Forbidden("video_forbidden", "user does not have permission to access this video")
+	ErrVideoProcessing    = apperr.Conflict("video_processing", "cannot delete a video while it is being processed")
+	ErrNotInTrash         = apperr.NotFound("video_not_in_trash", "video not found in trash")
+	ErrRestoreExpired     = apperr.Gone("restore_window_expired", "cannot restore: the restore window has expired")
+	ErrProcessedNotReady  = apperr.Conflict("video_not_processed", "processed video not available yet")
+	ErrOriginalForbidden  = apperr.Forbidden("original_forbidden", "user does not have permission to download the original video")
+	ErrVideoFileNotFound  = apperr.NotFound("video_file_not_found", "video file not found")
+	ErrVideoFileRequired  = apperr.Validation("video_file_required", "video file is required", apperr.FieldError{Field: "video", Code: "required", Message: "video is required"})
+	ErrInvalidVideoID     = apperr.Validation("invalid_video_id", "video_id must be a positive integer", apperr.FieldError{Field: "video_id", Code: "number", Message: "video_id must be a positive integer"})
+	ErrNotRequeueable     = apperr.NotFound("video_not_requeueable", "video not found or already being processed")
+	ErrInvalidRequeue     = apperr.Validation("invalid_requeue_filter", "invalid filter")
+	ErrInvalidMetadata    = apperr.Validation("invalid_metadata", "invalid metadata")
+	ErrInvalidSearchQuery = apperr.Validation("invalid_query", "invalid query")
+	ErrInvalidVariant     = apperr.Validation("invalid_variant", "invalid variant: must be original or processed",
+		apperr.FieldError{Field: "variant", Code: "oneof", Message: "variant must be one of: original, processed", Param: "original, processed"})
+)
+
+// invalidField arma el error de validación de un campo con el código de base
+func invalidField(base *apperr.Error, field, message string) *apperr.Error {
+	invalid := base.WithMessage(message)
+	invalid.Fields = []apperr.FieldError{{Field: field, Code: "invalid", Message: message}}
+	return invalid
+}
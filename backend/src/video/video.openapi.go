@@ -0,0 +1,158 @@
+package video
+
+import (
+	"anb-app/src/openapi"
+	"anb-app/src/pagination"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Operations documenta las rutas de SignUpVideoRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/videos/upload", Tags: []string{"videos"}, Auth: true,
+		Summary: "Subir un video",
+		Form:    UploadVideoRequest{}, Files: []string{"video"},
+		Status: http.StatusCreated, Response: UploadVideoResponse{},
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/videos", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Mis videos",
+		Query:    pageQuery(MyVideosSpec, openapi.Parameter{Name: "status", Description: "Estado del video", Enum: []string{"uploaded", "processing", "processed", "failed"}}),
+		Response: pagination.Page[VideoResponse]{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/videos/trash", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Papelera: videos retirados que todavía se pueden restaurar",
+		Query:    pageQuery(TrashSpec),
+		Response: pagination.Page[TrashedVideoResponse]{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/videos/:video_id", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Video por ID",
+		Response: VideoResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	downloadOperation(http.MethodGet, "/videos/:video_id/download", VariantOriginal, true),
+	downloadOperation(http.MethodHead, "/videos/:video_id/download", VariantOriginal, true),
+	{
+		Method: http.MethodPatch, Path: "/videos/:video_id", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Editar los metadatos; solo cambian los campos enviados",
+		Request:  UpdateVideoRequest{},
+		Response: VideoResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/videos/:video_id", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Enviar el video a la papelera",
+		Response: DeleteVideoResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/videos/:video_id/restore", Tags: []string{"videos"}, Auth: true,
+		Summary:  "Restaurar un video de la papelera",
+		Response: RestoreVideoResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusGone},
+	},
+	{
+		Method: http.MethodGet, Path: "/public/videos", Tags: []string{"public"},
+		Summary: "Videos públicos",
+		Query: pageQuery(PublicListSpec,
+			openapi.Parameter{Name: "category", Description: "Categoría del video", Enum: Categories},
+			openapi.Parameter{Name: "tags", Description: "Tags separados por comas o repetidos; el video debe tenerlos todos"},
+			openapi.Parameter{Name: "user_id", Description: "Videos de un jugador", Type: "integer"},
+		),
+		Response: pagination.Page[PublicVideoResponse]{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	downloadOperation(http.MethodGet, "/public/videos/:video_id/download", VariantProcessed, false),
+	downloadOperation(http.MethodHead, "/public/videos/:video_id/download", VariantProcessed, false),
+	{
+		Method: http.MethodGet, Path: "/public/rankings", Tags: []string{"public"},
+		Summary:  "Ranking de videos por votos",
+		Query:    pageQuery(RankingsSpec),
+		Response: pagination.Page[RankingResponse]{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/public/search", Tags: []string{"public"},
+		Summary: "Búsqueda de texto completo por título, descripción, jugador y ciudad",
+		Query: append([]openapi.Parameter{{Name: "q", Description: "Texto a buscar", Required: true}},
+			pageQuery(SearchSpec)...),
+		Response: SearchResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+}
+
+// AdminOperations documenta las rutas de SignUpVideoAdminRoutes
+var AdminOperations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/admin/videos/requeue", Tags: []string{"admin"}, Auth: true,
+		Summary:   "Reprocesar los videos que cumplan el filtro",
+		Request:   RequeueRequest{},
+		Status:    http.StatusAccepted,
+		Response:  RequeueResult{},
+		Responses: map[int]any{http.StatusOK: RequeueResult{}, http.StatusInternalServerError: RequeueResult{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/videos/:video_id/requeue", Tags: []string{"admin"}, Auth: true,
+		Summary: "Reprocesar un video",
+		Query: []openapi.Parameter{
+			{Name: "dry_run", Description: "Solo comprueba qué se reprocesaría", Type: "boolean"},
+			{Name: "force", Description: "Ignora las tareas activas", Type: "boolean"},
+		},
+		Status:    http.StatusAccepted,
+		Response:  RequeueResult{},
+		Responses: map[int]any{http.StatusOK: RequeueResult{}, http.StatusInternalServerError: RequeueResult{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+}
+
+func downloadOperation(method, path, variant string, owner bool) openapi.Operation {
+	operation := openapi.Operation{
+		Method: method, Path: path, Tags: []string{"public"},
+		Summary:     "Descargar el video procesado (Range, ETag)",
+		Query:       []openapi.Parameter{{Name: "variant", Description: "Archivo a descargar (" + variant + " por defecto)", Enum: []string{VariantProcessed}}},
+		ContentType: "application/octet-stream",
+		Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	}
+	if owner {
+		operation.Tags, operation.Auth = []string{"videos"}, true
+		operation.Summary = "Descargar el original o el procesado por la API (Range, ETag)"
+		operation.Query[0].Enum = []string{VariantOriginal, VariantProcessed}
+		operation.Errors = append(operation.Errors, http.StatusConflict)
+	}
+	return operation
+}
+
+// pageQuery documenta los parámetros comunes de paginación de un listado
+func pageQuery(spec pagination.Spec, extra ...openapi.Parameter) []openapi.Parameter {
+	params := []openapi.Parameter{
+		{Name: "limit", Description: fmt.Sprintf("Tamaño de página (%d por defecto, máximo %d)", pagination.DefaultLimit, pagination.MaxLimit), Type: "integer"},
+		{Name: "cursor", Description: "El next_cursor de la página anterior"},
+	}
+	if len(spec.Fields) > 0 {
+		keys := make([]string, 0, len(spec.Fields))
+		for key := range spec.Fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		params = append(params, openapi.Parameter{
+			Name:        "sort",
+			Description: "Claves separadas por comas, con - para descendente: " + strings.Join(keys, ", "),
+		})
+	}
+	if !spec.Offset {
+		params = append(params,
+			openapi.Parameter{Name: "from", Description: "Desde esta fecha de subida (YYYY-MM-DD o RFC 3339)"},
+			openapi.Parameter{Name: "to", Description: "Hasta esta fecha de subida, incluida si es YYYY-MM-DD"},
+		)
+	}
+	return append(params, extra...)
+}
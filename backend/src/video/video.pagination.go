@@ -0,0 +1,60 @@
+package video
+
+import "anb-app/src/pagination"
+
+// Claves de orden comunes de los listados de videos (?sort=-votes,uploaded_at)
+var videoSortFields = map[string]pagination.Field{
+	"votes":        {Column: "videos.vote_count", Kind: pagination.KindInt},
+	"uploaded_at":  {Column: "videos.uploaded_at", Kind: pagination.KindTime},
+	"processed_at": {Column: "COALESCE(videos.processed_at, 'epoch'::timestamptz)", Kind: pagination.KindTime},
+}
+
+// PublicListSpec: listado público, por defecto los más votados primero
+var PublicListSpec = pagination.Spec{
+	Fields:      videoSortFields,
+	IDColumn:    "videos.id",
+	DefaultSort: []pagination.SortKey{{Name: "votes", Desc: true}},
+}
+
+// MyVideosSpec: videos del usuario, por defecto los más recientes primero
+var MyVideosSpec = pagination.Spec{
+	Fields:      videoSortFields,
+	IDColumn:    "videos.id",
+	DefaultSort: []pagination.SortKey{{Name: "uploaded_at", Desc: true}},
+}
+
+// TrashSpec: papelera, por defecto los retirados más recientemente primero
+var TrashSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"votes":       videoSortFields["votes"],
+		"uploaded_at": videoSortFields["uploaded_at"],
+		"deleted_at":  {Column: "videos.deleted_at", Kind: pagination.KindTime},
+	},
+	IDColumn:    "videos.id",
+	DefaultSort: []pagination.SortKey{{Name: "deleted_at", Desc: true}},
+}
+
+// RankingsSpec: el orden es fijo y el cursor guarda la posición para seguir numerando
+var RankingsSpec = pagination.Spec{Offset: true}
+
+// SearchSpec: la búsqueda ordena por relevancia, así que también pagina por posición
+var SearchSpec = pagination.Spec{Offset: true}
+
+func videoID(v Video) uint {
+	return v.ID
+}
+
+// videoSortKey devuelve el valor de la clave de orden name para armar el cursor
+func videoSortKey(v Video, name string) interface{} {
+	switch name {
+	case "votes":
+		return v.VoteCount
+	case "uploaded_at":
+		return v.UploadedAt
+	case "processed_at":
+		return v.ProcessedAt
+	case "deleted_at":
+		return v.DeletedAt.Time
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package video
+
+import (
+	"anb-app/src/storage"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	purgeInterval  = time.Hour
+	purgeBatchSize = 100
+	// Un video que no se pudo purgar sale de los lotes durante este tiempo, para que los
+	// que siguen fallando no ocupen el lote y dejen sin purgar a los demás
+	purgeRetryDelay = 6 * time.Hour
+)
+
+// Purger borra de S3 y de la base de datos los videos que llevan en la papelera más
+// de RestoreWindow. Los votos del video se eliminan con él.
+type Purger struct {
+	videoRepo  VideoRepository
+	storageSvc storage.StorageService
+	now        func() time.Time
+}
+
+func NewPurger(videoRepo VideoRepository, storageSvc storage.StorageService) *Purger {
+	return &Purger{
+		videoRepo:  videoRepo,
+		storageSvc: storageSvc,
+		now:        time.Now,
+	}
+}
+
+// Run purga cada hora hasta que ctx termina
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := p.PurgeExpired(); err != nil {
+			log.Printf("Purge: failed after removing %d videos: %v", purged, err)
+		} else if purged > 0 {
+			log.Printf("Purge: removed %d expired videos", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired elimina un lote de videos vencidos y devuelve cuántos borró
+func (p *Purger) PurgeExpired() (int, error) {
+	now := p.now()
+	videos, err := p.videoRepo.FindPurgeable(now.Add(-RestoreWindow), now.Add(-purgeRetryDelay), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, video := range videos {
+		// Si S3 falla el video se queda en la papelera y se reintenta pasado purgeRetryDelay
+		if err := p.deleteObjects(video); err != nil {
+			log.Printf("Purge: could not delete objects of video %d: %v", video.ID, err)
+			if err := p.videoRepo.MarkPurgeFailed(video.ID, now); err != nil {
+				return purged, err
+			}
+			continue
+		}
+		if err := p.videoRepo.Purge(video.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (p *Purger) deleteObjects(video Video) error {
+	for _, key := range []string{video.OriginalURL, video.ProcessedURL, video.ThumbnailURL, video.PreviewURL} {
+		if key == "" {
+			continue
+		}
+		if err := p.storageSvc.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package video
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestPurger(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("PurgeExpired_RemovesObjectsThenRow", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		purger := NewPurger(mockRepo, mockStorage)
+		purger.now = func() time.Time { return now }
+
+		mockRepo.On("FindPurgeable", now.Add(-RestoreWindow), now.Add(-purgeRetryDelay), purgeBatchSize).Return([]Video{
+			{ID: 1, OriginalURL: "originals/1.mp4", ProcessedURL: "processed/1.mp4", ThumbnailURL: "thumbnails/1.jpg"},
+		}, nil)
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+		mockRepo.On("Purge", uint(1)).Return(nil)
+
+		purged, err := purger.PurgeExpired()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		mockStorage.AssertNumberOfCalls(t, "Delete", 3)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PurgeExpired_KeepsRowWhenStorageFails", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		purger := NewPurger(mockRepo, mockStorage)
+		purger.now = func() time.Time { return now }
+
+		mockRepo.On("FindPurgeable", mock.Anything, mock.Anything, purgeBatchSize).Return([]Video{
+			{ID: 1, OriginalURL: "originals/1.mp4"},
+			{ID: 2, OriginalURL: "originals/2.mp4"},
+		}, nil)
+		mockStorage.On("Delete", "originals/1.mp4").Return(assert.AnError)
+		mockStorage.On("Delete", "originals/2.mp4").Return(nil)
+		mockRepo.On("MarkPurgeFailed", uint(1), now).Return(nil)
+		mockRepo.On("Purge", uint(2)).Return(nil)
+
+		purged, err := purger.PurgeExpired()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		mockRepo.AssertNotCalled(t, "Purge", uint(1))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PurgeExpired_FailuresDoNotStarveNewerVideos", func(t *testing.T) {
+		repo := &trashRepository{}
+		mockStorage := new(MockStorageService)
+		purger := NewPurger(repo, mockStorage)
+		clock := now
+		purger.now = func() time.Time { return clock }
+
+		// Un lote entero de videos cuyo borrado en S3 falla siempre, más uno nuevo sano
+		expired := now.Add(-RestoreWindow - 48*time.Hour)
+		for i := 1; i <= purgeBatchSize; i++ {
+			repo.videos = append(repo.videos, Video{ID: uint(i), OriginalURL: "originals/stuck.mp4", DeletedAt: gorm.DeletedAt{Time: expired.Add(time.Duration(i) * time.Second), Valid: true}})
+		}
+		repo.videos = append(repo.videos, Video{ID: 500, OriginalURL: "originals/500.mp4", DeletedAt: gorm.DeletedAt{Time: now.Add(-RestoreWindow - time.Hour), Valid: true}})
+		mockStorage.On("Delete", "originals/stuck.mp4").Return(assert.AnError)
+		mockStorage.On("Delete", "originals/500.mp4").Return(nil)
+
+		purged, err := purger.PurgeExpired()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, purged)
+
+		clock = clock.Add(purgeInterval)
+		purged, err = purger.PurgeExpired()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []uint{500}, repo.purged)
+
+		// Pasado purgeRetryDelay los que fallaron vuelven a intentarse
+		clock = now.Add(purgeRetryDelay + time.Minute)
+		_, err = purger.PurgeExpired()
+		assert.NoError(t, err)
+		mockStorage.AssertNumberOfCalls(t, "Delete", 2*purgeBatchSize+1)
+	})
+}
+
+// trashRepository guarda la papelera en memoria y aplica los mismos filtros que FindPurgeable
+type trashRepository struct {
+	MockVideoRepository
+	videos []Video
+	purged []uint
+}
+
+func (r *trashRepository) FindPurgeable(deletedBefore, failedBefore time.Time, limit int) ([]Video, error) {
+	var found []Video
+	for _, video := range r.videos {
+		failedRecently := video.PurgeAttemptedAt != nil && !video.PurgeAttemptedAt.Before(failedBefore)
+		if video.DeletedAt.Time.Before(deletedBefore) && !failedRecently && len(found) < limit {
+			found = append(found, video)
+		}
+	}
+	return found, nil
+}
+
+func (r *trashRepository) MarkPurgeFailed(videoID uint, at time.Time) error {
+	for i := range r.videos {
+		if r.videos[i].ID == videoID {
+			r.videos[i].PurgeAttemptedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *trashRepository) Purge(videoID uint) error {
+	r.videos = slices.DeleteFunc(r.videos, func(video Video) bool { return video.ID == videoID })
+	r.purged = append(r.purged, videoID)
+	return nil
+}
@@ -1,6 +1,14 @@
 package video
 
-import "gorm.io/gorm"
+import (
+	"anb-app/src/outbox"
+	"anb-app/src/pagination"
+	"anb-app/src/task"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type videoRepository struct {
 	db *gorm.DB
@@ -21,10 +29,31 @@ func (r *videoRepository) Create(video *Video) (*Video, error) {
 	return video, nil
 }
 
-func (r *videoRepository) FindByUserID(userID uint) ([]Video, error) {
+// CreateWithOutbox inserta el video y los mensajes que build arma a partir de él
+// (ya con ID) en una sola transacción
+func (r *videoRepository) CreateWithOutbox(video *Video, build func(*Video) ([]outbox.Message, error)) (*Video, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(video).Error; err != nil {
+			return err
+		}
+		messages, err := build(video)
+		if err != nil {
+			return err
+		}
+		return outbox.Add(tx, messages...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+func (r *videoRepository) FindByUserID(userID uint, page pagination.Request) ([]Video, error) {
 	var videos []Video
 
-	result := r.db.Where("user_id = ?", userID).Order("uploaded_at DESC").Find(&videos)
+	query := applyFilters(r.db.Where("user_id = ?", userID), page.Filters)
+	result := page.Apply(query).Find(&videos)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -60,35 +89,218 @@ func (r *videoRepository) Delete(videoID uint) error {
 	return nil
 }
 
-func (r *videoRepository) FindPublic() ([]Video, error) {
+// FindDeletedByUserID devuelve la papelera del usuario: los videos retirados después de deletedAfter
+func (r *videoRepository) FindDeletedByUserID(userID uint, deletedAfter time.Time, page pagination.Request) ([]Video, error) {
 	var videos []Video
 
-	result := r.db.Where("status = ?", "processed").Order("vote_count DESC").Find(&videos)
+	query := r.db.Unscoped().Where("user_id = ? AND deleted_at > ?", userID, deletedAfter)
+	result := page.Apply(applyFilters(query, page.Filters)).Find(&videos)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+
 	return videos, nil
 }
 
-func (r *videoRepository) Update(video *Video) error {
-	result := r.db.Save(video)
-	return result.Error
+func (r *videoRepository) FindDeletedByID(videoID uint) (*Video, error) {
+	var video Video
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&video, videoID)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &video, nil
 }
 
+func (r *videoRepository) Restore(videoID uint) error {
+	result := r.db.Unscoped().Model(&Video{}).Where("id = ? AND deleted_at IS NOT NULL", videoID).Update("deleted_at", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FindPurgeable devuelve los videos retirados antes de deletedBefore, salvo los que
+// fallaron al purgarse después de failedBefore
+func (r *videoRepository) FindPurgeable(deletedBefore, failedBefore time.Time, limit int) ([]Video, error) {
+	var videos []Video
+
+	result := r.db.Unscoped().
+		Where("deleted_at < ? AND (purge_attempted_at IS NULL OR purge_attempted_at < ?)", deletedBefore, failedBefore).
+		Order("deleted_at").Limit(limit).Find(&videos)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return videos, nil
+}
+
+// MarkPurgeFailed registra un intento fallido de purgar el video
+func (r *videoRepository) MarkPurgeFailed(videoID uint, at time.Time) error {
+	return r.db.Unscoped().Model(&Video{}).Where("id = ?", videoID).Update("purge_attempted_at", at).Error
+}
+
+// Purge borra definitivamente un video retirado junto con sus votos
+func (r *videoRepository) Purge(videoID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM votes WHERE video_id = ?", videoID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&Video{}, videoID).Error
+	})
+}
+
+// HasActiveTask indica si el video tiene una tarea de procesamiento pendiente o en curso
+func (r *videoRepository) HasActiveTask(videoID uint) (bool, error) {
+	var count int64
+
+	result := r.db.Model(&task.ProcessingJob{}).
+		Where("video_id = ? AND status IN ?", videoID, []string{task.StatusQueued, task.StatusProcessing, task.StatusRetrying}).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+func (r *videoRepository) FindPublic(filter PublicVideoFilter, page pagination.Request) ([]Video, error) {
+	var videos []Video
+
+	query := applyFilters(r.db.Where("status = ?", "processed"), page.Filters)
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("tags @> ?::jsonb", string(tags))
+	}
+
+	result := page.Apply(query).Find(&videos)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return videos, nil
+}
+
+// applyFilters aplica los filtros comunes de los listados; el rango de fechas es sobre uploaded_at
+func applyFilters(query *gorm.DB, filters pagination.Filters) *gorm.DB {
+	if filters.Status != "" {
+		query = query.Where("videos.status = ?", filters.Status)
+	}
+	if filters.UserID != 0 {
+		query = query.Where("videos.user_id = ?", filters.UserID)
+	}
+	if filters.From != nil {
+		query = query.Where("videos.uploaded_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("videos.uploaded_at < ?", *filters.To)
+	}
+	return query
+}
+
+// UpdateMetadata guarda solo los metadatos que edita el dueño, así no pisa el estado ni
+// los votos que cambiaron desde que se leyó el video
+func (r *videoRepository) UpdateMetadata(video *Video) error {
+	result := r.db.Model(&Video{}).
+		Where("id = ? AND user_id = ?", video.ID, video.UserID).
+		Select(metadataColumns).
+		Updates(video)
+	return updatedOrNotFound(result)
+}
+
+// UpdateProcessing guarda las columnas del procesamiento y registra los mensajes en la
+// misma transacción. No toca los metadatos ni los votos, que pueden cambiar mientras el
+// worker procesa el video.
+func (r *videoRepository) UpdateProcessing(video *Video, messages ...outbox.Message) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Video{}).
+			Where("id = ?", video.ID).
+			Select(processingColumns).
+			Updates(video)
+		if err := updatedOrNotFound(result); err != nil {
+			return err
+		}
+		return outbox.Add(tx, messages...)
+	})
+}
+
+// updatedOrNotFound devuelve gorm.ErrRecordNotFound si el UPDATE no tocó ninguna fila:
+// el video se retiró después de leerlo (p. ej. mientras el worker lo procesaba)
+func updatedOrNotFound(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+
+// FindForRequeue devuelve los videos que cumplen el filtro, por orden de subida.
+// Salvo con Force, se omiten los que ya tienen una tarea de procesamiento activa.
+func (r *videoRepository) FindForRequeue(filter RequeueFilter) ([]Video, error) {
+	var videos []Video
+
+	query := r.db.Model(&Video{})
+	if filter.VideoID != 0 {
+		query = query.Where("id = ?", filter.VideoID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UploadedFrom != nil {
+		query = query.Where("uploaded_at >= ?", *filter.UploadedFrom)
+	}
+	if filter.UploadedTo != nil {
+		query = query.Where("uploaded_at < ?", *filter.UploadedTo)
+	}
+	if filter.PipelineVersion != "" {
+		query = query.Where("pipeline_version = ?", filter.PipelineVersion)
+	}
+	if !filter.Force {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM processing_jobs pj WHERE pj.video_id = videos.id AND pj.status IN ?)",
+			[]string{task.StatusQueued, task.StatusProcessing, task.StatusRetrying})
+	}
+
+	result := query.Order("uploaded_at, id").Limit(filter.Limit).Find(&videos)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return videos, nil
+}
 
-func (r *videoRepository) GetRankings() ([]RankingResponse, error) {
+// GetRankings devuelve una página del ranking; las posiciones siguen a partir del cursor
+func (r *videoRepository) GetRankings(page pagination.Request) ([]RankingResponse, error) {
     var results []struct {
-        VideoID    uint
-        Title      string
-        AuthorName string
-        VoteCount  int
+        VideoID      uint
+        Title        string
+        AuthorName   string
+        VoteCount    int
+        ThumbnailURL string
+        PreviewURL   string
     }
 
-    queryResult := r.db.Table("videos").
-        Select("videos.id as video_id, videos.title, users.first_name || ' ' || users.last_name as author_name, videos.vote_count").
+    query := r.db.Table("videos").
+        Select("videos.id as video_id, videos.title, users.first_name || ' ' || users.last_name as author_name, videos.vote_count, videos.thumbnail_url, videos.preview_url").
         Joins("JOIN users ON users.id = videos.user_id").
-        Where("videos.status = ?", "processed").
-        Order("videos.vote_count DESC").
+        Where("videos.status = ? AND videos.deleted_at IS NULL", "processed")
+    queryResult := page.Apply(applyFilters(query, page.Filters)).
+        Order("videos.vote_count DESC, videos.id").
         Scan(&results)
 
     if queryResult.Error != nil {
@@ -98,11 +310,13 @@ func (r *videoRepository) GetRankings() ([]RankingResponse, error) {
     rankings := make([]RankingResponse, len(results))
     for i, result := range results {
         rankings[i] = RankingResponse{
-            Position:   i + 1,
-            VideoID:    result.VideoID,
-            Title:      result.Title,
-            AuthorName: result.AuthorName,
-            VoteCount:  result.VoteCount,
+            Position:     page.Offset() + i + 1,
+            VideoID:      result.VideoID,
+            Title:        result.Title,
+            AuthorName:   result.AuthorName,
+            VoteCount:    result.VoteCount,
+            ThumbnailURL: result.ThumbnailURL,
+            PreviewURL:   result.PreviewURL,
         }
     }
 
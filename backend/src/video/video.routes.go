@@ -10,20 +10,44 @@ func SignUpVideoRoutes(router *gin.RouterGroup, vc *VideoController, authMiddlew
 
 		protectedRoutes.GET("", vc.ListMyVideos)
 
+		// Papelera: videos retirados que todavía se pueden restaurar
+		protectedRoutes.GET("/trash", vc.ListTrash)
+
 		protectedRoutes.GET("/:video_id", vc.GetVideoByID)
 
-		// Download endpoint that streams the video file as an attachment
+		// Descarga por la API con Range y ETag; ?variant=original|processed
 		protectedRoutes.GET("/:video_id/download", vc.Download)
+		protectedRoutes.HEAD("/:video_id/download", vc.Download)
+
+		protectedRoutes.PATCH("/:video_id", vc.UpdateVideo)
 
 		protectedRoutes.DELETE("/:video_id", vc.DeleteVideo)
 
-		protectedRoutes.POST("/:video_id/mark-processed", vc.MarkVideoAsProcessed)
+		protectedRoutes.POST("/:video_id/restore", vc.RestoreVideo)
 	}
 
 	publicRoutes := router.Group("/public")
 	{
 		publicRoutes.GET("/videos", vc.ListPublicVideos)
 
+		// Solo el video procesado
+		publicRoutes.GET("/videos/:video_id/download", vc.DownloadPublic)
+		publicRoutes.HEAD("/videos/:video_id/download", vc.DownloadPublic)
+
 		publicRoutes.GET("/rankings", vc.GetRankings)
+
+		publicRoutes.GET("/search", vc.Search)
+	}
+}
+
+// SignUpVideoAdminRoutes registra las operaciones de administración; adminMiddleware
+// se aplica después de authMiddleware
+func SignUpVideoAdminRoutes(router *gin.RouterGroup, ac *AdminController, authMiddleware, adminMiddleware gin.HandlerFunc) {
+
+	adminRoutes := router.Group("/admin/videos", authMiddleware, adminMiddleware)
+	{
+		adminRoutes.POST("/requeue", ac.RequeueVideos)
+
+		adminRoutes.POST("/:video_id/requeue", ac.RequeueVideo)
 	}
 }
@@ -0,0 +1,119 @@
+package video
+
+import (
+	"anb-app/src/pagination"
+	"anb-app/src/storage"
+	"strings"
+)
+
+// SearchConfig es la configuración de búsqueda de texto que crea database.MigrateSearch:
+// la de español con unaccent, para que "Bogota" encuentre "Bogotá"
+const SearchConfig = "es_unaccent"
+
+const maxSearchQueryLength = 200
+
+// Las coincidencias se marcan con <mark>; los textos se escapan antes de resaltarlos
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+
+// Para la descripción solo se devuelven los fragmentos con coincidencias
+const descriptionHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" … \""
+
+// SearchQuery es una búsqueda ya validada
+type SearchQuery struct {
+	Text string
+}
+
+// searchSQL busca en el título y la descripción del video y en el nombre y la ciudad del
+// jugador. Los vectores son columnas generadas con índice GIN (ver database.MigrateSearch).
+const searchSQL = `
+WITH q AS (SELECT websearch_to_tsquery('` + SearchConfig + `', @text) AS query)
+SELECT videos.id AS video_id,
+	videos.title,
+	videos.category,
+	videos.vote_count,
+	videos.thumbnail_url,
+	videos.preview_url,
+	users.first_name || ' ' || users.last_name AS player_name,
+	users.city,
+	ts_rank(videos.search_vector || users.search_vector, q.query) AS rank,
+	ts_headline('` + SearchConfig + `', replace(replace(replace(videos.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query, @headline) AS title_highlight,
+	CASE WHEN videos.search_vector @@ q.query AND coalesce(videos.description, '') <> ''
+		THEN ts_headline('` + SearchConfig + `', replace(replace(replace(videos.description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query, @description_headline)
+		ELSE '' END AS description_highlight,
+	ts_headline('` + SearchConfig + `', replace(replace(replace(users.first_name || ' ' || users.last_name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query, @headline) AS player_highlight,
+	ts_headline('` + SearchConfig + `', replace(replace(replace(coalesce(users.city, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query, @headline) AS city_highlight,
+	COUNT(*) OVER () AS total
+FROM videos
+JOIN users ON users.id = videos.user_id
+CROSS JOIN q
+WHERE videos.status = 'processed'
+	AND videos.deleted_at IS NULL
+	AND (videos.search_vector @@ q.query OR users.search_vector @@ q.query)
+ORDER BY rank DESC, videos.vote_count DESC, videos.id
+LIMIT @limit OFFSET @offset`
+
+// NewSearchQuery valida el texto de la búsqueda
+func NewSearchQuery(text string) (SearchQuery, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return SearchQuery{}, invalidField(ErrInvalidSearchQuery, "q", "invalid query: q is required")
+	}
+	if len([]rune(text)) > maxSearchQueryLength {
+		return SearchQuery{}, invalidField(ErrInvalidSearchQuery, "q", "invalid query: q is too long")
+	}
+	return SearchQuery{Text: text}, nil
+}
+
+// Search devuelve una página de resultados ordenados por relevancia (con la fila de más que
+// pide la paginación) y el total de coincidencias
+func (r *videoRepository) Search(query SearchQuery, page pagination.Request) ([]SearchResult, int64, error) {
+	var rows []struct {
+		SearchResult
+		Total int64
+	}
+
+	result := r.db.Raw(searchSQL, map[string]interface{}{
+		"text":                 query.Text,
+		"headline":             headlineOptions,
+		"description_headline": descriptionHeadlineOptions,
+		"limit":                page.Limit + 1,
+		"offset":               page.Offset(),
+	}).Scan(&rows)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	results := make([]SearchResult, len(rows))
+	var total int64
+	for i, row := range rows {
+		results[i] = row.SearchResult
+		total = row.Total
+	}
+	return results, total, nil
+}
+
+func (s *videoService) Search(query SearchQuery, page pagination.Request) (*SearchResponse, error) {
+	results, total, err := s.videoRepo.Search(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := pagination.NewPage(results, page, nil, nil)
+
+	// El repositorio devuelve claves de S3; se reemplazan por URLs firmadas
+	var keys []string
+	for _, hit := range result.Items {
+		keys = append(keys, hit.ThumbnailURL, hit.PreviewURL)
+	}
+	urls := storage.SignAll(s.urlSigner, keys...)
+	for i := range result.Items {
+		result.Items[i].ThumbnailURL = urls[result.Items[i].ThumbnailURL]
+		result.Items[i].PreviewURL = urls[result.Items[i].PreviewURL]
+	}
+
+	return &SearchResponse{
+		Page:  result,
+		Query: query.Text,
+		Total: total,
+	}, nil
+}
@@ -1,14 +1,18 @@
 package video
 
 import (
+	"anb-app/src/outbox"
+	"anb-app/src/pagination"
 	"anb-app/src/queue"
 	"anb-app/src/storage"
+	"anb-app/src/task"
+	"anb-app/src/webhook"
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"mime/multipart"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,47 +23,164 @@ var ctx = context.Background()
 
 const (
 	TypeVideoProcess = "task:video:process"
+
+	// RestoreWindow es el tiempo que un video retirado permanece en la papelera
+	RestoreWindow = 30 * 24 * time.Hour
 )
 
 type VideoRepository interface {
 	Create(video *Video) (*Video, error)
-	FindByUserID(userID uint) ([]Video, error)
+	CreateWithOutbox(video *Video, build func(*Video) ([]outbox.Message, error)) (*Video, error)
+	FindByUserID(userID uint, page pagination.Request) ([]Video, error)
 	FindByID(videoID uint) (*Video, error)
 	Delete(videoID uint) error
-	FindPublic() ([]Video, error)
-	Update(video *Video) error
-	GetRankings() ([]RankingResponse, error)
+	FindPublic(filter PublicVideoFilter, page pagination.Request) ([]Video, error)
+	UpdateMetadata(video *Video) error
+	UpdateProcessing(video *Video, messages ...outbox.Message) error
+	GetRankings(page pagination.Request) ([]RankingResponse, error)
+	Search(query SearchQuery, page pagination.Request) ([]SearchResult, int64, error)
+	FindForRequeue(filter RequeueFilter) ([]Video, error)
+	FindDeletedByUserID(userID uint, deletedAfter time.Time, page pagination.Request) ([]Video, error)
+	FindDeletedByID(videoID uint) (*Video, error)
+	Restore(videoID uint) error
+	FindPurgeable(deletedBefore, failedBefore time.Time, limit int) ([]Video, error)
+	MarkPurgeFailed(videoID uint, at time.Time) error
+	Purge(videoID uint) error
+	HasActiveTask(videoID uint) (bool, error)
 }
 
 type videoService struct {
-	videoRepo   VideoRepository
-	queueClient queue.QueueClient
-	storageSvc  storage.StorageService
+	videoRepo  VideoRepository
+	storageSvc storage.StorageService
+	taskRepo   task.TaskRepository
+	urlSigner  storage.URLSigner
 }
 
-func NewVideoService(videoRepo VideoRepository, queueClient queue.QueueClient, storageSvc storage.StorageService) VideoService {
+// NewVideoService recibe el firmador con el que se entregan los archivos (URLs
+// prefirmadas de S3 o de CloudFront, normalmente con caché)
+func NewVideoService(videoRepo VideoRepository, storageSvc storage.StorageService, taskRepo task.TaskRepository, urlSigner storage.URLSigner) VideoService {
 	return &videoService{
-		videoRepo:   videoRepo,
-		queueClient: queueClient,
-		storageSvc:  storageSvc,
+		videoRepo:  videoRepo,
+		storageSvc: storageSvc,
+		taskRepo:   taskRepo,
+		urlSigner:  urlSigner,
+	}
+}
+
+// mediaFields indica qué URLs lleva una respuesta; solo esas se firman
+type mediaFields uint8
+
+const (
+	mediaOriginal mediaFields = 1 << iota
+	mediaProcessed
+	mediaThumbnail
+	mediaPreview
+
+	// El dueño recibe todos sus archivos; el público nunca recibe el original
+	ownerMedia  = mediaOriginal | mediaProcessed | mediaThumbnail | mediaPreview
+	publicMedia = mediaProcessed | mediaThumbnail | mediaPreview
+)
+
+// mediaKeys devuelve las claves de S3 de los campos pedidos
+func mediaKeys(fields mediaFields, videos ...Video) []string {
+	var keys []string
+	for _, video := range videos {
+		if fields&mediaOriginal != 0 {
+			keys = append(keys, video.OriginalURL)
+		}
+		if fields&mediaProcessed != 0 {
+			keys = append(keys, video.ProcessedURL)
+		}
+		if fields&mediaThumbnail != 0 {
+			keys = append(keys, video.ThumbnailURL)
+		}
+		if fields&mediaPreview != 0 {
+			keys = append(keys, video.PreviewURL)
+		}
 	}
+	return keys
 }
 
-// Helper to convert S3 key to presigned URL
-func (s *videoService) getPresignedURL(s3Key string) string {
-	if s3Key == "" {
-		return ""
+// toResponse arma la respuesta de un video; urls trae las firmas de los campos incluidos
+func toResponse(video *Video, urls map[string]string, fields mediaFields) VideoResponse {
+	tags := video.Tags
+	if tags == nil {
+		tags = []string{}
 	}
-	// Generate presigned URL valid for 1 hour
-	url, err := s.storageSvc.GetPresignedURL(s3Key, 1*time.Hour)
-	if err != nil {
-		log.Printf("Error generating presigned URL for %s: %v", s3Key, err)
-		return ""
+	response := VideoResponse{
+		ID:            video.ID,
+		UserID:        video.UserID,
+		Title:         video.Title,
+		Status:        video.Status,
+		VoteCount:     video.VoteCount,
+		UploadedAt:    video.UploadedAt,
+		ProcessedAt:   video.ProcessedAt,
+		Description:   video.Description,
+		Category:      video.Category,
+		Tags:          tags,
+		RecordedAt:    video.RecordedAt,
+		FailureReason: video.FailureReason,
+	}
+	if fields&mediaOriginal != 0 {
+		response.OriginalURL = urls[video.OriginalURL]
+	}
+	if fields&mediaProcessed != 0 {
+		response.ProcessedURL = urls[video.ProcessedURL]
 	}
-	return url
+	if fields&mediaThumbnail != 0 {
+		response.ThumbnailURL = urls[video.ThumbnailURL]
+	}
+	if fields&mediaPreview != 0 {
+		response.PreviewURL = urls[video.PreviewURL]
+	}
+	return response
+}
+
+// toPublicResponse arma la respuesta pública; urls trae las firmas de publicMedia
+func toPublicResponse(video *Video, urls map[string]string) PublicVideoResponse {
+	tags := video.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return PublicVideoResponse{
+		ID:           video.ID,
+		UserID:       video.UserID,
+		Title:        video.Title,
+		ProcessedURL: urls[video.ProcessedURL],
+		ThumbnailURL: urls[video.ThumbnailURL],
+		PreviewURL:   urls[video.PreviewURL],
+		VoteCount:    video.VoteCount,
+		ProcessedAt:  video.ProcessedAt,
+		Description:  video.Description,
+		Category:     video.Category,
+		Tags:         tags,
+		RecordedAt:   video.RecordedAt,
+	}
+}
+
+// toResponses firma de una vez los archivos de la página y arma las respuestas
+func (s *videoService) toResponses(page pagination.Page[Video], fields mediaFields) pagination.Page[VideoResponse] {
+	urls := storage.SignAll(s.urlSigner, mediaKeys(fields, page.Items...)...)
+	return pagination.Map(page, func(video Video) VideoResponse {
+		return toResponse(&video, urls, fields)
+	})
+}
+
+// signedResponse arma la respuesta de un solo video visto por su dueño
+func (s *videoService) signedResponse(video *Video) VideoResponse {
+	urls := storage.SignAll(s.urlSigner, mediaKeys(ownerMedia, *video)...)
+	return toResponse(video, urls, ownerMedia)
 }
 
 func (s *videoService) Upload(ctx *gin.Context, req *UploadVideoRequest, fileHeader *multipart.FileHeader, userID uint) (*VideoResponse, error) {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(req.Category, req.RecordedAt); err != nil {
+		return nil, err
+	}
+
 	ext := filepath.Ext(fileHeader.Filename)
 	newFileName := fmt.Sprintf("%d-%d%s", time.Now().UnixNano(), userID, ext)
 
@@ -81,214 +202,290 @@ func (s *videoService) Upload(ctx *gin.Context, req *UploadVideoRequest, fileHea
 	newVideo := &Video{
 		UserID:      userID,
 		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		Tags:        tags,
+		RecordedAt:  req.RecordedAt,
 		Status:      "uploaded",
 		OriginalURL: s3Key, // Store S3 key
 		UploadedAt:  time.Now(),
 	}
 
-	createdVideo, err := s.videoRepo.Create(newVideo)
+	taskID, err := task.NewTaskID()
 	if err != nil {
-		// Try to cleanup S3 object if DB insert fails
 		s.storageSvc.Delete(s3Key)
 		return nil, err
 	}
 
-	// Encolar tarea en SQS
-	payload := queue.TaskPayload{VideoID: createdVideo.ID}
-
-	taskID, err := s.queueClient.EnqueueTask(
-		context.Background(),
-		TypeVideoProcess,
-		payload,
-		5,              // maxRetry
-		10*time.Minute, // timeout
-	)
+	// La tarea de procesamiento y el evento video.uploaded se registran en el outbox
+	// en la misma transacción que el video: si la cola no responde, el relay los
+	// publica más tarde y no quedan videos sin procesar
+	createdVideo, err := s.videoRepo.CreateWithOutbox(newVideo, func(v *Video) ([]outbox.Message, error) {
+		enqueue, err := queue.NewOutboxMessage(
+			TypeVideoProcess,
+			queue.TaskPayload{VideoID: v.ID, TaskID: taskID},
+			5,              // maxRetry
+			10*time.Minute, // timeout
+		)
+		if err != nil {
+			return nil, err
+		}
+		event, err := NewWebhookEvent(webhook.EventVideoUploaded, v)
+		if err != nil {
+			return nil, err
+		}
+		return []outbox.Message{enqueue, event}, nil
+	})
 	if err != nil {
+		// Try to cleanup S3 object if DB insert fails
+		s.storageSvc.Delete(s3Key)
 		return nil, err
 	}
 
-	log.Printf("---> Enqueued task for video ID: %d, Task ID: %s", createdVideo.ID, taskID)
+	// Registrar el trabajo para que el usuario pueda seguir su avance. Si falla, el
+	// worker lo crea al recibir la tarea, así que no se aborta la subida
+	_, err = s.taskRepo.Create(&task.ProcessingJob{
+		TaskID:  taskID,
+		VideoID: createdVideo.ID,
+		UserID:  userID,
+		Status:  task.StatusQueued,
+	})
+	if err != nil {
+		log.Printf("Error creating processing job %s for video %d: %v", taskID, createdVideo.ID, err)
+	}
 
-	// Generate presigned URLs for response
-	originalPresignedURL := s.getPresignedURL(createdVideo.OriginalURL)
-	processedPresignedURL := s.getPresignedURL(createdVideo.ProcessedURL)
+	log.Printf("---> Recorded processing task for video ID: %d, Task ID: %s", createdVideo.ID, taskID)
 
-	response := &VideoResponse{
-		ID:           createdVideo.ID,
-		UserID:       createdVideo.UserID,
-		Title:        createdVideo.Title,
-		Status:       createdVideo.Status,
-		OriginalURL:  originalPresignedURL,
-		VoteCount:    createdVideo.VoteCount,
-		UploadedAt:   createdVideo.UploadedAt,
-		ProcessedAt:  createdVideo.ProcessedAt,
-		ProcessedURL: processedPresignedURL,
+	// La respuesta del upload solo expone task_id: no hace falta firmar nada
+	response := toResponse(createdVideo, nil, 0)
+	response.TaskID = taskID
+
+	return &response, nil
+}
+
+func (s *videoService) ListByUserID(userID uint, page pagination.Request) (pagination.Page[VideoResponse], error) {
+	// Solo los videos propios, aunque la query pida otro user_id
+	page.Filters.UserID = 0
+
+	videos, err := s.videoRepo.FindByUserID(userID, page)
+	if err != nil {
+		return pagination.Page[VideoResponse]{}, err
 	}
 
-	return response, nil
+	return s.toResponses(pagination.NewPage(videos, page, videoID, videoSortKey), ownerMedia), nil
 }
 
-func (s *videoService) ListByUserID(userID uint) ([]VideoResponse, error) {
-	videos, err := s.videoRepo.FindByUserID(userID)
+func (s *videoService) GetByID(videoID uint, userID uint) (*VideoResponse, error) {
+	video, err := s.videoRepo.FindByID(videoID)
 	if err != nil {
 		return nil, err
 	}
+	if video == nil {
+		return nil, ErrVideoNotFound
+	}
 
-	var videoResponses []VideoResponse
-	for _, video := range videos {
-		response := VideoResponse{
-			ID:           video.ID,
-			UserID:       video.UserID,
-			Title:        video.Title,
-			Status:       video.Status,
-			OriginalURL:  s.getPresignedURL(video.OriginalURL),
-			ProcessedURL: s.getPresignedURL(video.ProcessedURL),
-			VoteCount:    video.VoteCount,
-			UploadedAt:   video.UploadedAt,
-			ProcessedAt:  video.ProcessedAt,
-		}
-		videoResponses = append(videoResponses, response)
+	if video.UserID != userID {
+		return nil, ErrVideoForbidden
 	}
 
-	return videoResponses, nil
+	response := s.signedResponse(video)
+	return &response, nil
 }
 
-func (s *videoService) GetByID(videoID uint, userID uint) (*VideoResponse, error) {
+// Update cambia los metadatos presentes en req; el archivo y el estado no se tocan
+func (s *videoService) Update(videoID uint, userID uint, req *UpdateVideoRequest) (*VideoResponse, error) {
 	video, err := s.videoRepo.FindByID(videoID)
 	if err != nil {
 		return nil, err
 	}
 	if video == nil {
-		return nil, errors.New("video not found")
+		return nil, ErrVideoNotFound
 	}
 
 	if video.UserID != userID {
-		return nil, errors.New("user does not have permission to access this video")
+		return nil, ErrVideoForbidden.WithMessage("user does not have permission to modify this video")
 	}
 
-	response := &VideoResponse{
-		ID:           video.ID,
-		UserID:       video.UserID,
-		Title:        video.Title,
-		Status:       video.Status,
-		OriginalURL:  s.getPresignedURL(video.OriginalURL),
-		ProcessedURL: s.getPresignedURL(video.ProcessedURL),
-		VoteCount:    video.VoteCount,
-		UploadedAt:   video.UploadedAt,
-		ProcessedAt:  video.ProcessedAt,
+	if req.Title != nil {
+		video.Title = strings.TrimSpace(*req.Title)
+		if video.Title == "" {
+			return nil, invalidField(ErrInvalidMetadata, "title", "invalid title: it cannot be empty")
+		}
+	}
+	if req.Description != nil {
+		video.Description = *req.Description
+	}
+	if req.Category != nil {
+		video.Category = *req.Category
+	}
+	if req.Tags != nil {
+		tags, err := normalizeTags(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		video.Tags = tags
+	}
+	if req.RecordedAt != nil {
+		video.RecordedAt = req.RecordedAt
+	}
+	if err := validateMetadata(video.Category, video.RecordedAt); err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	if err := s.videoRepo.UpdateMetadata(video); err != nil {
+		return nil, err
+	}
+
+	response := s.signedResponse(video)
+	return &response, nil
 }
 
+// Delete retira el video: desaparece de inmediato de las listas públicas y los
+// rankings, pero se puede restaurar durante RestoreWindow. Los archivos de S3 los
+// borra el Purger cuando vence ese plazo.
 func (s *videoService) Delete(videoID uint, userID uint) error {
 	video, err := s.videoRepo.FindByID(videoID)
 	if err != nil {
 		return err
 	}
 	if video == nil {
-		return errors.New("video not found")
+		return ErrVideoNotFound
 	}
 
 	if video.UserID != userID {
-		return errors.New("user does not have permission to delete this video")
-	}
-
-	if video.Status != "uploaded" {
-		return errors.New("cannot delete a video that has been processed or published")
+		return ErrVideoForbidden.WithMessage("user does not have permission to delete this video")
 	}
 
-	err = s.videoRepo.Delete(videoID)
+	// El worker guarda el resultado al terminar; retirar el video a mitad dejaría
+	// archivos procesados sin dueño
+	active, err := s.videoRepo.HasActiveTask(videoID)
 	if err != nil {
 		return err
 	}
-
-	// Delete from S3 (video.OriginalURL is now S3 key)
-	if err := s.storageSvc.Delete(video.OriginalURL); err != nil {
-		log.Printf("Warning: Failed to delete S3 object %s: %v", video.OriginalURL, err)
-	}
-
-	// Also delete processed video if exists
-	if video.ProcessedURL != "" {
-		if err := s.storageSvc.Delete(video.ProcessedURL); err != nil {
-			log.Printf("Warning: Failed to delete S3 object %s: %v", video.ProcessedURL, err)
-		}
+	if active {
+		return ErrVideoProcessing
 	}
 
-	return nil
+	return s.videoRepo.Delete(videoID)
 }
 
-func (s *videoService) ListPublic() ([]VideoResponse, error) {
-	videos, err := s.videoRepo.FindPublic()
+// ListTrash devuelve los videos retirados que el usuario todavía puede restaurar
+func (s *videoService) ListTrash(userID uint, page pagination.Request) (pagination.Page[TrashedVideoResponse], error) {
+	page.Filters.UserID = 0
+
+	videos, err := s.videoRepo.FindDeletedByUserID(userID, time.Now().Add(-RestoreWindow), page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[TrashedVideoResponse]{}, err
 	}
 
-	var videoResponses []VideoResponse
-	for _, video := range videos {
-		response := VideoResponse{
+	return pagination.Map(pagination.NewPage(videos, page, videoID, videoSortKey), func(video Video) TrashedVideoResponse {
+		return TrashedVideoResponse{
 			ID:           video.ID,
-			UserID:       video.UserID,
 			Title:        video.Title,
 			Status:       video.Status,
-			OriginalURL:  s.getPresignedURL(video.OriginalURL),
-			ProcessedURL: s.getPresignedURL(video.ProcessedURL),
 			VoteCount:    video.VoteCount,
 			UploadedAt:   video.UploadedAt,
-			ProcessedAt:  video.ProcessedAt,
+			DeletedAt:    video.DeletedAt.Time,
+			RestoreUntil: video.DeletedAt.Time.Add(RestoreWindow),
 		}
-		videoResponses = append(videoResponses, response)
-	}
-
-	return videoResponses, nil
+	}), nil
 }
 
-func (s *videoService) MarkAsProcessed(videoID uint, userID uint) (*VideoResponse, error) {
-	video, err := s.videoRepo.FindByID(videoID)
+func (s *videoService) Restore(videoID uint, userID uint) error {
+	video, err := s.videoRepo.FindDeletedByID(videoID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if video == nil {
-		return nil, errors.New("video not found")
+		return ErrNotInTrash
 	}
 
 	if video.UserID != userID {
-		return nil, errors.New("user does not have permission to modify this video")
+		return ErrVideoForbidden.WithMessage("user does not have permission to restore this video")
 	}
 
-	video.Status = "processed"
-	now := time.Now()
-	video.ProcessedAt = &now
-	baseName := strings.TrimSuffix(filepath.Base(video.OriginalURL), filepath.Ext(video.OriginalURL))
-	// Store S3 key for processed video
-	video.ProcessedURL = fmt.Sprintf("processed/%s.mp4", baseName)
+	if time.Since(video.DeletedAt.Time) > RestoreWindow {
+		return ErrRestoreExpired
+	}
 
-	if err := s.videoRepo.Update(video); err != nil {
-		return nil, err
+	return s.videoRepo.Restore(videoID)
+}
+
+func (s *videoService) ListPublic(filter PublicVideoFilter, page pagination.Request) (pagination.Page[PublicVideoResponse], error) {
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return pagination.Page[PublicVideoResponse]{}, err
 	}
+	filter.Tags = tags
 
-	response := &VideoResponse{
-		ID:           video.ID,
-		UserID:       video.UserID,
-		Title:        video.Title,
-		Status:       video.Status,
-		OriginalURL:  s.getPresignedURL(video.OriginalURL),
-		ProcessedURL: s.getPresignedURL(video.ProcessedURL),
-		VoteCount:    video.VoteCount,
-		UploadedAt:   video.UploadedAt,
-		ProcessedAt:  video.ProcessedAt,
+	videos, err := s.videoRepo.FindPublic(filter, page)
+	if err != nil {
+		return pagination.Page[PublicVideoResponse]{}, err
 	}
 
-	return response, nil
+	result := pagination.NewPage(videos, page, videoID, videoSortKey)
+	urls := storage.SignAll(s.urlSigner, mediaKeys(publicMedia, result.Items...)...)
+	return pagination.Map(result, func(video Video) PublicVideoResponse {
+		return toPublicResponse(&video, urls)
+	}), nil
 }
 
-func (s *videoService) GetRankings() ([]RankingResponse, error) {
+func (s *videoService) GetRankings(page pagination.Request) (pagination.Page[RankingResponse], error) {
 	// Sin Redis, obtenemos rankings directamente de la DB
 	// En el futuro se puede implementar cache con ElastiCache si es necesario
-	rankings, err := s.videoRepo.GetRankings()
+	rankings, err := s.videoRepo.GetRankings(page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[RankingResponse]{}, err
+	}
+
+	result := pagination.NewPage(rankings, page, nil, nil)
+
+	// El repositorio devuelve claves de S3; se reemplazan por URLs firmadas
+	var keys []string
+	for _, ranking := range result.Items {
+		keys = append(keys, ranking.ThumbnailURL, ranking.PreviewURL)
+	}
+	urls := storage.SignAll(s.urlSigner, keys...)
+	for i := range result.Items {
+		result.Items[i].ThumbnailURL = urls[result.Items[i].ThumbnailURL]
+		result.Items[i].PreviewURL = urls[result.Items[i].PreviewURL]
 	}
 
-	return rankings, nil
+	return result, nil
+}
+
+const (
+	maxTags      = 10
+	maxTagLength = 30
+)
+
+// normalizeTags separa por comas, pasa a minúsculas y elimina vacíos y duplicados
+func normalizeTags(raw []string) ([]string, error) {
+	tags := []string{}
+	for _, value := range raw {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || slices.Contains(tags, tag) {
+				continue
+			}
+			if len([]rune(tag)) > maxTagLength {
+				return nil, invalidField(ErrInvalidMetadata, "tags", fmt.Sprintf("invalid tags: %q is longer than %d characters", tag, maxTagLength))
+			}
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxTags {
+		return nil, invalidField(ErrInvalidMetadata, "tags", fmt.Sprintf("invalid tags: at most %d tags are allowed", maxTags))
+	}
+	return tags, nil
+}
+
+func validateMetadata(category string, recordedAt *time.Time) error {
+	if category != "" && !slices.Contains(Categories, category) {
+		return invalidField(ErrInvalidMetadata, "category", "invalid category: must be one of "+strings.Join(Categories, ", "))
+	}
+	if recordedAt != nil && recordedAt.After(time.Now()) {
+		return invalidField(ErrInvalidMetadata, "recorded_at", "invalid recorded_at: it cannot be in the future")
+	}
+	return nil
 }
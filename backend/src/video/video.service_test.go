@@ -1,18 +1,30 @@
 package video
 
 import (
+	"anb-app/src/outbox"
+	"anb-app/src/pagination"
 	"anb-app/src/queue"
+	"anb-app/src/storage"
+	"anb-app/src/task"
+	"anb-app/src/webhook"
+	"bytes"
 	"context"
+	"io"
 	"mime/multipart"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockVideoRepository struct {
 	mock.Mock
+	// Mensajes del outbox registrados junto con el último cambio
+	Messages []outbox.Message
 }
 
 func (m *MockVideoRepository) Create(video *Video) (*Video, error) {
@@ -20,8 +32,23 @@ func (m *MockVideoRepository) Create(video *Video) (*Video, error) {
 	return args.Get(0).(*Video), args.Error(1)
 }
 
-func (m *MockVideoRepository) FindByUserID(userID uint) ([]Video, error) {
-	args := m.Called(userID)
+// CreateWithOutbox arma los mensajes con build sobre el video que devuelve el mock
+func (m *MockVideoRepository) CreateWithOutbox(video *Video, build func(*Video) ([]outbox.Message, error)) (*Video, error) {
+	args := m.Called(video)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	created := args.Get(0).(*Video)
+	messages, err := build(created)
+	if err != nil {
+		return nil, err
+	}
+	m.Messages = append(m.Messages, messages...)
+	return created, nil
+}
+
+func (m *MockVideoRepository) FindByUserID(userID uint, page pagination.Request) ([]Video, error) {
+	args := m.Called(userID, page)
 	return args.Get(0).([]Video), args.Error(1)
 }
 
@@ -38,22 +65,86 @@ func (m *MockVideoRepository) Delete(videoID uint) error {
 	return args.Error(0)
 }
 
-func (m *MockVideoRepository) FindPublic() ([]Video, error) {
-	args := m.Called()
+func (m *MockVideoRepository) FindPublic(filter PublicVideoFilter, page pagination.Request) ([]Video, error) {
+	args := m.Called(filter, page)
 	return args.Get(0).([]Video), args.Error(1)
 }
 
-func (m *MockVideoRepository) Update(video *Video) error {
+func (m *MockVideoRepository) UpdateMetadata(video *Video) error {
 	args := m.Called(video)
 	return args.Error(0)
 }
 
-func (m *MockVideoRepository) GetRankings() ([]RankingResponse, error) {
-	args := m.Called()
+func (m *MockVideoRepository) UpdateProcessing(video *Video, messages ...outbox.Message) error {
+	args := m.Called(video)
+	m.Messages = append(m.Messages, messages...)
+	return args.Error(0)
+}
+
+func (m *MockVideoRepository) Search(query SearchQuery, page pagination.Request) ([]SearchResult, int64, error) {
+	args := m.Called(query, page)
+	return args.Get(0).([]SearchResult), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockVideoRepository) FindForRequeue(filter RequeueFilter) ([]Video, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]Video), args.Error(1)
+}
+
+func (m *MockVideoRepository) FindDeletedByUserID(userID uint, deletedAfter time.Time, page pagination.Request) ([]Video, error) {
+	args := m.Called(userID, deletedAfter, page)
+	return args.Get(0).([]Video), args.Error(1)
+}
+
+func (m *MockVideoRepository) FindDeletedByID(videoID uint) (*Video, error) {
+	args := m.Called(videoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Video), args.Error(1)
+}
+
+func (m *MockVideoRepository) Restore(videoID uint) error {
+	args := m.Called(videoID)
+	return args.Error(0)
+}
+
+func (m *MockVideoRepository) FindPurgeable(deletedBefore, failedBefore time.Time, limit int) ([]Video, error) {
+	args := m.Called(deletedBefore, failedBefore, limit)
+	return args.Get(0).([]Video), args.Error(1)
+}
+
+func (m *MockVideoRepository) MarkPurgeFailed(videoID uint, at time.Time) error {
+	args := m.Called(videoID, at)
+	return args.Error(0)
+}
+
+func (m *MockVideoRepository) Purge(videoID uint) error {
+	args := m.Called(videoID)
+	return args.Error(0)
+}
+
+func (m *MockVideoRepository) HasActiveTask(videoID uint) (bool, error) {
+	args := m.Called(videoID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockVideoRepository) GetRankings(page pagination.Request) ([]RankingResponse, error) {
+	args := m.Called(page)
 	return args.Get(0).([]RankingResponse), args.Error(1)
 }
 
 // Mock para StorageService
+// MockURLSigner falla si un test firma algo que no esperaba
+type MockURLSigner struct {
+	mock.Mock
+}
+
+func (m *MockURLSigner) Sign(key string) (storage.SignedURL, error) {
+	args := m.Called(key)
+	return args.Get(0).(storage.SignedURL), args.Error(1)
+}
+
 type MockStorageService struct {
 	mock.Mock
 }
@@ -73,48 +164,181 @@ func (m *MockStorageService) GetPresignedURL(s3Key string, expiration time.Durat
 	return args.String(0), args.Error(1)
 }
 
-// Mock para QueueClient
-type MockQueueClient struct {
+func (m *MockStorageService) HeadObject(ctx context.Context, s3Key string) (storage.ObjectInfo, error) {
+	args := m.Called(ctx, s3Key)
+	return args.Get(0).(storage.ObjectInfo), args.Error(1)
+}
+
+func (m *MockStorageService) GetObject(ctx context.Context, s3Key string, offset int64) (io.ReadCloser, error) {
+	args := m.Called(ctx, s3Key, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockStorageService) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// Mock para TaskRepository
+type MockTaskRepository struct {
 	mock.Mock
 }
 
-func (m *MockQueueClient) EnqueueTask(ctx context.Context, taskType string, payload queue.TaskPayload, maxRetry int, timeout time.Duration) (string, error) {
-	args := m.Called(ctx, taskType, payload, maxRetry, timeout)
-	return args.String(0), args.Error(1)
+func (m *MockTaskRepository) Create(job *task.ProcessingJob) (*task.ProcessingJob, error) {
+	args := m.Called(job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*task.ProcessingJob), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindByTaskID(taskID string) (*task.ProcessingJob, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*task.ProcessingJob), args.Error(1)
 }
 
-func (m *MockQueueClient) Close() error {
-	args := m.Called()
+func (m *MockTaskRepository) Update(job *task.ProcessingJob) error {
+	args := m.Called(job)
 	return args.Error(0)
 }
 
+// newTestFileHeader construye el *multipart.FileHeader que recibe Upload
+func newTestFileHeader(t *testing.T, filename string) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("video", filename)
+	assert.NoError(t, err)
+	part.Write([]byte("fake video"))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/videos/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	_, header, err := req.FormFile("video")
+	assert.NoError(t, err)
+	return header
+}
+
 func TestVideoService(t *testing.T) {
+	t.Run("Upload_RecordsTaskInOutbox", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		mockTasks := new(MockTaskRepository)
+		videoSvc := NewVideoService(mockRepo, mockStorage, mockTasks, storage.NewS3URLSigner(mockStorage, time.Hour))
+
+		var job *task.ProcessingJob
+		mockStorage.On("Upload", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mockStorage.On("GetPresignedURL", mock.AnythingOfType("string"), time.Hour).Return("https://s3.amazonaws.com/presigned", nil)
+		mockRepo.On("CreateWithOutbox", mock.AnythingOfType("*video.Video")).Return(&Video{ID: 9, UserID: 1, Title: "Jugada", Status: "uploaded", OriginalURL: "originals/9.mp4"}, nil)
+		mockTasks.On("Create", mock.AnythingOfType("*task.ProcessingJob")).Run(func(args mock.Arguments) {
+			job = args.Get(0).(*task.ProcessingJob)
+		}).Return(&task.ProcessingJob{}, nil)
+
+		result, err := videoSvc.Upload(nil, &UploadVideoRequest{Title: "Jugada"}, newTestFileHeader(t, "clip.mp4"), 1)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.TaskID)
+		assert.Equal(t, job.TaskID, result.TaskID)
+		assert.Equal(t, uint(9), job.VideoID)
+		assert.Equal(t, uint(1), job.UserID)
+		assert.Equal(t, task.StatusQueued, job.Status)
+		if assert.Len(t, mockRepo.Messages, 2) {
+			assert.Equal(t, queue.TopicTasks, mockRepo.Messages[0].Topic)
+			assert.JSONEq(t, `{"task_type":"task:video:process","payload":{"video_id":9,"task_id":"`+job.TaskID+`"},"max_retry":5,"timeout_seconds":600}`, mockRepo.Messages[0].Payload)
+			assert.Equal(t, webhook.TopicWebhook, mockRepo.Messages[1].Topic)
+			assert.Equal(t, webhook.EventVideoUploaded, mockRepo.Messages[1].EventType)
+			assert.JSONEq(t, `{"video_id":9,"user_id":1,"title":"Jugada","status":"uploaded"}`, mockRepo.Messages[1].Payload)
+		}
+		mockTasks.AssertExpectations(t)
+	})
+
+	t.Run("Upload_CreateFailureRemovesOriginal", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		mockTasks := new(MockTaskRepository)
+		videoSvc := NewVideoService(mockRepo, mockStorage, mockTasks, storage.NewS3URLSigner(mockStorage, time.Hour))
+
+		mockStorage.On("Upload", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+		mockRepo.On("CreateWithOutbox", mock.AnythingOfType("*video.Video")).Return(nil, assert.AnError)
+
+		result, err := videoSvc.Upload(nil, &UploadVideoRequest{Title: "Jugada"}, newTestFileHeader(t, "clip.mp4"), 1)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockStorage.AssertCalled(t, "Delete", mock.AnythingOfType("string"))
+		mockTasks.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Upload_TaskRecordFailureKeepsUpload", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		mockTasks := new(MockTaskRepository)
+		videoSvc := NewVideoService(mockRepo, mockStorage, mockTasks, storage.NewS3URLSigner(mockStorage, time.Hour))
+
+		mockStorage.On("Upload", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mockStorage.On("GetPresignedURL", mock.AnythingOfType("string"), time.Hour).Return("https://s3.amazonaws.com/presigned", nil)
+		mockRepo.On("CreateWithOutbox", mock.AnythingOfType("*video.Video")).Return(&Video{ID: 9, UserID: 1, OriginalURL: "originals/9.mp4"}, nil)
+		mockTasks.On("Create", mock.AnythingOfType("*task.ProcessingJob")).Return(nil, assert.AnError)
+
+		result, err := videoSvc.Upload(nil, &UploadVideoRequest{Title: "Jugada"}, newTestFileHeader(t, "clip.mp4"), 1)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.TaskID)
+		mockStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
 	t.Run("ListByUserID_Success", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		userID := uint(1)
 		videos := []Video{
 			{ID: 1, UserID: userID, Title: "Test Video", Status: "processed"},
 		}
 
-		mockRepo.On("FindByUserID", userID).Return(videos, nil)
+		page := pagination.NewRequest(MyVideosSpec, 10)
+		mockRepo.On("FindByUserID", userID, page).Return(videos, nil)
+
+		result, err := videoSvc.ListByUserID(userID, page)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, "Test Video", result.Items[0].Title)
+		assert.Nil(t, result.NextCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ListByUserID_NextPageAndOwnVideosOnly", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		// La fila de más indica que hay otra página; el filtro user_id no aplica a "mis videos"
+		page := pagination.NewRequest(MyVideosSpec, 1)
+		page.Filters.UserID = 2
+		mockRepo.On("FindByUserID", uint(1), pagination.NewRequest(MyVideosSpec, 1)).Return([]Video{
+			{ID: 3, UserID: 1, Title: "Nuevo"},
+			{ID: 2, UserID: 1, Title: "Viejo"},
+		}, nil)
 
-		result, err := videoSvc.ListByUserID(userID)
+		result, err := videoSvc.ListByUserID(1, page)
 
 		assert.NoError(t, err)
-		assert.Len(t, result, 1)
-		assert.Equal(t, "Test Video", result[0].Title)
+		assert.Len(t, result.Items, 1)
+		assert.NotNil(t, result.NextCursor)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("GetByID_Success", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		videoID := uint(1)
 		userID := uint(1)
@@ -138,8 +362,7 @@ func TestVideoService(t *testing.T) {
 	t.Run("GetByID_NotFound", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		videoID := uint(999)
 		userID := uint(1)
@@ -157,8 +380,7 @@ func TestVideoService(t *testing.T) {
 	t.Run("GetByID_PermissionDenied", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		videoID := uint(1)
 		userID := uint(1)
@@ -183,79 +405,289 @@ func TestVideoService(t *testing.T) {
 	t.Run("Delete_Success", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		videoID := uint(1)
 		userID := uint(1)
 		video := &Video{
-			ID:          videoID,
-			UserID:      userID,
-			Status:      "uploaded",
-			OriginalURL: "originals/test.mp4",
+			ID:           videoID,
+			UserID:       userID,
+			Status:       "processed",
+			OriginalURL:  "originals/test.mp4",
+			ProcessedURL: "processed/test.mp4",
 		}
 
 		mockRepo.On("FindByID", videoID).Return(video, nil)
-		mockStorage.On("Delete", "originals/test.mp4").Return(nil)
+		mockRepo.On("HasActiveTask", videoID).Return(false, nil)
 		mockRepo.On("Delete", videoID).Return(nil)
 
 		err := videoSvc.Delete(videoID, userID)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
-		mockStorage.AssertExpectations(t)
+		// Los archivos se conservan hasta que el Purger los borre
+		mockStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Delete_WhileProcessing", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		mockRepo.On("FindByID", uint(1)).Return(&Video{ID: 1, UserID: 1, Status: "uploaded"}, nil)
+		mockRepo.On("HasActiveTask", uint(1)).Return(true, nil)
+
+		err := videoSvc.Delete(1, 1)
+
+		assert.ErrorContains(t, err, "cannot delete")
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("ListTrash_OnlyWithinRestoreWindow", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		recent := time.Now().Add(-time.Hour)
+		page := pagination.NewRequest(TrashSpec, 10)
+		withinWindow := mock.MatchedBy(func(deletedAfter time.Time) bool {
+			return time.Since(deletedAfter.Add(RestoreWindow)) < time.Minute
+		})
+		mockRepo.On("FindDeletedByUserID", uint(1), withinWindow, page).Return([]Video{
+			{ID: 1, UserID: 1, DeletedAt: gorm.DeletedAt{Time: recent, Valid: true}},
+		}, nil)
+
+		trash, err := videoSvc.ListTrash(1, page)
+
+		assert.NoError(t, err)
+		if assert.Len(t, trash.Items, 1) {
+			assert.Equal(t, uint(1), trash.Items[0].ID)
+			assert.Equal(t, recent.Add(RestoreWindow), trash.Items[0].RestoreUntil)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Restore_Success", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		deletedAt := gorm.DeletedAt{Time: time.Now().Add(-24 * time.Hour), Valid: true}
+		mockRepo.On("FindDeletedByID", uint(1)).Return(&Video{ID: 1, UserID: 1, DeletedAt: deletedAt}, nil)
+		mockRepo.On("Restore", uint(1)).Return(nil)
+
+		err := videoSvc.Restore(1, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Restore_WindowExpired", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		deletedAt := gorm.DeletedAt{Time: time.Now().Add(-RestoreWindow - time.Minute), Valid: true}
+		mockRepo.On("FindDeletedByID", uint(1)).Return(&Video{ID: 1, UserID: 1, DeletedAt: deletedAt}, nil)
+
+		err := videoSvc.Restore(1, 1)
+
+		assert.ErrorContains(t, err, "expired")
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+	})
+
+	t.Run("Restore_OtherUser", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+		mockRepo.On("FindDeletedByID", uint(1)).Return(&Video{ID: 1, UserID: 2, DeletedAt: deletedAt}, nil)
+
+		err := videoSvc.Restore(1, 1)
+
+		assert.ErrorContains(t, err, "permission")
 	})
 
 	t.Run("ListPublic_Success", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
 		videos := []Video{
 			{ID: 1, Title: "Public Video 1", Status: "processed", VoteCount: 10},
 			{ID: 2, Title: "Public Video 2", Status: "processed", VoteCount: 5},
 		}
 
-		mockRepo.On("FindPublic").Return(videos, nil)
+		page := pagination.NewRequest(PublicListSpec, 10)
+		mockRepo.On("FindPublic", PublicVideoFilter{Tags: []string{}}, page).Return(videos, nil)
 
-		result, err := videoSvc.ListPublic()
+		result, err := videoSvc.ListPublic(PublicVideoFilter{}, page)
 
 		assert.NoError(t, err)
-		assert.Len(t, result, 2)
+		assert.Len(t, result.Items, 2)
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("MarkAsProcessed_Success", func(t *testing.T) {
+	t.Run("ListPublic_SignsOnlyPublicMediaOncePerKey", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockSigner := new(MockURLSigner)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), mockSigner)
+
+		// El original nunca se firma en el listado público (el mock fallaría)
+		videos := []Video{
+			{ID: 1, Status: "processed", OriginalURL: "originals/1.mp4", ProcessedURL: "processed/1.mp4", ThumbnailURL: "processed/shared_thumb.jpg"},
+			{ID: 2, Status: "processed", OriginalURL: "originals/2.mp4", ProcessedURL: "processed/2.mp4", ThumbnailURL: "processed/shared_thumb.jpg"},
+		}
+		page := pagination.NewRequest(PublicListSpec, 10)
+		mockRepo.On("FindPublic", PublicVideoFilter{Tags: []string{}}, page).Return(videos, nil)
+		for _, key := range []string{"processed/1.mp4", "processed/2.mp4", "processed/shared_thumb.jpg"} {
+			mockSigner.On("Sign", key).Return(storage.SignedURL{URL: "https://cdn.example.com/" + key}, nil).Once()
+		}
+
+		result, err := videoSvc.ListPublic(PublicVideoFilter{}, page)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.example.com/processed/1.mp4", result.Items[0].ProcessedURL)
+		assert.Equal(t, "https://cdn.example.com/processed/shared_thumb.jpg", result.Items[1].ThumbnailURL)
+		mockSigner.AssertExpectations(t)
+	})
+
+	t.Run("ListPublic_NormalizesTagFilter", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		expected := PublicVideoFilter{Category: "dunk", Tags: []string{"final", "u18"}}
+		mockRepo.On("FindPublic", expected, pagination.NewRequest(PublicListSpec, 10)).Return([]Video{}, nil)
+
+		_, err := videoSvc.ListPublic(PublicVideoFilter{Category: "dunk", Tags: []string{" Final,u18", "final"}}, pagination.NewRequest(PublicListSpec, 10))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update_ChangesOnlyPresentFields", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		existing := &Video{ID: 1, UserID: 1, Title: "Original", Description: "Se mantiene", Status: "processed"}
+		mockRepo.On("FindByID", uint(1)).Return(existing, nil)
+		mockRepo.On("UpdateMetadata", mock.MatchedBy(func(v *Video) bool {
+			return v.Title == "Nuevo" && v.Description == "Se mantiene" && v.Category == "block" &&
+				len(v.Tags) == 2 && v.Tags[0] == "final" && v.Status == "processed"
+		})).Return(nil)
+
+		title, category, tags := "Nuevo", "block", []string{"Final", "defensa"}
+		result, err := videoSvc.Update(1, 1, &UpdateVideoRequest{Title: &title, Category: &category, Tags: &tags})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Nuevo", result.Title)
+		assert.Equal(t, []string{"final", "defensa"}, result.Tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update_InvalidCategory", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		mockRepo.On("FindByID", uint(1)).Return(&Video{ID: 1, UserID: 1}, nil)
+
+		category := "slam"
+		_, err := videoSvc.Update(1, 1, &UpdateVideoRequest{Category: &category})
+
+		assert.ErrorContains(t, err, "invalid category")
+		mockRepo.AssertNotCalled(t, "UpdateMetadata", mock.Anything)
+	})
+
+	t.Run("Update_FutureRecordedAt", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		mockRepo.On("FindByID", uint(1)).Return(&Video{ID: 1, UserID: 1}, nil)
+
+		recordedAt := time.Now().Add(48 * time.Hour)
+		_, err := videoSvc.Update(1, 1, &UpdateVideoRequest{RecordedAt: &recordedAt})
+
+		assert.ErrorContains(t, err, "invalid recorded_at")
+	})
+
+	t.Run("Update_OtherUser", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		videoSvc := NewVideoService(mockRepo, new(MockStorageService), new(MockTaskRepository), new(MockURLSigner))
+
+		mockRepo.On("FindByID", uint(1)).Return(&Video{ID: 1, UserID: 2}, nil)
+
+		title := "Ajeno"
+		_, err := videoSvc.Update(1, 1, &UpdateVideoRequest{Title: &title})
+
+		assert.ErrorContains(t, err, "permission")
+		mockRepo.AssertNotCalled(t, "UpdateMetadata", mock.Anything)
+	})
+
+	t.Run("Search_PresignsMediaAssets", func(t *testing.T) {
 		mockRepo := new(MockVideoRepository)
 		mockStorage := new(MockStorageService)
-		mockQueue := new(MockQueueClient)
-		videoSvc := NewVideoService(mockRepo, mockQueue, mockStorage)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
 
-		videoID := uint(1)
-		userID := uint(1)
-		video := &Video{
-			ID:          videoID,
-			UserID:      userID,
-			Title:       "Test Video",
-			Status:      "uploaded",
-			OriginalURL: "originals/test-video.mov",
+		query := SearchQuery{Text: "bogota"}
+		page := pagination.NewRequest(SearchSpec, 1)
+		hits := []SearchResult{
+			{VideoID: 3, Title: "Mate", ThumbnailURL: "processed/3_thumb.jpg", CityHighlight: "<mark>Bogotá</mark>"},
+			{VideoID: 5, Title: "Triple"},
 		}
+		mockRepo.On("Search", query, page).Return(hits, int64(2), nil)
+		mockStorage.On("GetPresignedURL", "processed/3_thumb.jpg", time.Hour).Return("https://s3.amazonaws.com/presigned-thumb", nil)
 
-		mockRepo.On("FindByID", videoID).Return(video, nil)
-		mockStorage.On("GetPresignedURL", "originals/test-video.mov", time.Hour).Return("https://s3.amazonaws.com/presigned-url-original", nil)
-		mockStorage.On("GetPresignedURL", "processed/test-video.mp4", time.Hour).Return("https://s3.amazonaws.com/presigned-url-processed", nil)
-		mockRepo.On("Update", mock.AnythingOfType("*video.Video")).Return(nil)
+		result, err := videoSvc.Search(query, page)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Total)
+		assert.Len(t, result.Items, 1)
+		assert.NotNil(t, result.NextCursor)
+		assert.Equal(t, "https://s3.amazonaws.com/presigned-thumb", result.Items[0].ThumbnailURL)
+		assert.Empty(t, result.Items[0].PreviewURL)
+	})
+
+	t.Run("GetRankings_PresignsMediaAssets", func(t *testing.T) {
+		mockRepo := new(MockVideoRepository)
+		mockStorage := new(MockStorageService)
+		videoSvc := NewVideoService(mockRepo, mockStorage, new(MockTaskRepository), storage.NewS3URLSigner(mockStorage, time.Hour))
+
+		rankings := []RankingResponse{
+			{Position: 1, VideoID: 1, Title: "Top", VoteCount: 10, ThumbnailURL: "processed/top_thumb.jpg", PreviewURL: "processed/top_preview.mp4"},
+			{Position: 2, VideoID: 2, Title: "Sin miniatura", VoteCount: 5},
+		}
 
-		result, err := videoSvc.MarkAsProcessed(videoID, userID)
+		page := pagination.NewRequest(RankingsSpec, 10)
+		mockRepo.On("GetRankings", page).Return(rankings, nil)
+		mockStorage.On("GetPresignedURL", "processed/top_thumb.jpg", time.Hour).Return("https://s3.amazonaws.com/presigned-thumb", nil)
+		mockStorage.On("GetPresignedURL", "processed/top_preview.mp4", time.Hour).Return("https://s3.amazonaws.com/presigned-preview", nil)
+
+		result, err := videoSvc.GetRankings(page)
 
 		assert.NoError(t, err)
-		assert.Equal(t, "processed", result.Status)
-		// La respuesta contiene URLs presignadas de S3, no las claves
-		assert.Equal(t, "https://s3.amazonaws.com/presigned-url-processed", result.ProcessedURL)
-		assert.Equal(t, "https://s3.amazonaws.com/presigned-url-original", result.OriginalURL)
-		assert.NotNil(t, result.ProcessedAt)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, "https://s3.amazonaws.com/presigned-thumb", result.Items[0].ThumbnailURL)
+		assert.Equal(t, "https://s3.amazonaws.com/presigned-preview", result.Items[0].PreviewURL)
+		assert.Empty(t, result.Items[1].ThumbnailURL)
+		assert.Empty(t, result.Items[1].PreviewURL)
 		mockRepo.AssertExpectations(t)
 		mockStorage.AssertExpectations(t)
 	})
 }
+
+func TestNewSearchQuery(t *testing.T) {
+	t.Run("TrimsText", func(t *testing.T) {
+		query, err := NewSearchQuery("  triple ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, SearchQuery{Text: "triple"}, query)
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		_, err := NewSearchQuery("   ")
+
+		assert.ErrorContains(t, err, "invalid query")
+	})
+
+	t.Run("QueryTooLong", func(t *testing.T) {
+		_, err := NewSearchQuery(strings.Repeat("a", 201))
+
+		assert.ErrorContains(t, err, "invalid query")
+	})
+}
@@ -1,9 +1,11 @@
 package vote
 
 import (
+	"anb-app/src/apperr"
+	"anb-app/src/i18n"
+	"anb-app/src/video"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,65 +25,48 @@ func NewVoteController(voteService VoteService) *VoteController {
 	}
 }
 
-func (vc *VoteController) Create(c *gin.Context) {
-	videoIDStr := c.Param("video_id")
-	videoID, err := strconv.ParseUint(videoIDStr, 10, 32)
+// voteTarget lee el video de la ruta y el usuario autenticado
+func voteTarget(c *gin.Context) (userID uint, videoID uint, err error) {
+	id, err := strconv.ParseUint(c.Param("video_id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID format"})
-		return
+		return 0, 0, video.ErrInvalidVideoID
 	}
 	userIDClaim, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication"}) // <-- Mensaje ajustado
-		return
+		return 0, 0, apperr.Unauthorized("unauthenticated", "missing authentication")
 	}
-	userID := userIDClaim.(uint)
+	return userIDClaim.(uint), uint(id), nil
+}
 
-	err = vc.voteService.CreateVote(userID, uint(videoID))
+func (vc *VoteController) Create(c *gin.Context) {
+	userID, videoID, err := voteTarget(c)
 	if err != nil {
-		if strings.Contains(err.Error(), "already voted") {
-			// 400 Bad Request
-			c.JSON(http.StatusBadRequest, gin.H{"error": "You have already voted for this video."}) // <-- Mensaje ajustado
-			return
-		}
-		// AÑADIMOS ESTE CASO PARA EL 404
-		if strings.Contains(err.Error(), "video not found") {
-			// 404 Not Found
-			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found."})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
+		return
+	}
+
+	// Ya votó: 409 already_voted; video inexistente: 404 video_not_found
+	if err := vc.voteService.CreateVote(userID, videoID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	// 200 OK
-	c.JSON(http.StatusOK, gin.H{"message": "Vote successfully registered."}) // <-- Mensaje ajustado
+	c.JSON(http.StatusOK, VoteMessageResponse{Message: i18n.Message(c, "vote.created")})
 }
+
 func (vc *VoteController) Delete(c *gin.Context) {
-	videoIDStr := c.Param("video_id")
-	videoID, err := strconv.ParseUint(videoIDStr, 10, 32)
+	userID, videoID, err := voteTarget(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID format"})
+		c.Error(err)
 		return
 	}
-	userIDClaim, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication."})
-		return
-	}
-	userID := userIDClaim.(uint)
 
-	err = vc.voteService.DeleteVote(userID, uint(videoID))
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") {
-			// 404 Not Found
-			c.JSON(http.StatusNotFound, gin.H{"error": "Vote is non existent, cannot be deleted."}) // <-- Mensaje ajustado
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := vc.voteService.DeleteVote(userID, videoID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	// 200 OK
-	c.JSON(http.StatusOK, gin.H{"message": "Voto successfully deleted."}) // <-- Mensaje ajustado
+	c.JSON(http.StatusOK, VoteMessageResponse{Message: i18n.Message(c, "vote.deleted")})
 }
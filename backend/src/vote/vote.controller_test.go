@@ -1,6 +1,7 @@
 package vote
 
 import (
+	"anb-app/src/apperr/apperrtest"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -45,13 +46,13 @@ func TestVoteController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Create(c)
+		apperrtest.Handle(controller.Create, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
 		var response map[string]string
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Vote successfully registered.", response["message"])
+		assert.Equal(t, "Voto registrado correctamente.", response["message"])
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -68,13 +69,11 @@ func TestVoteController(t *testing.T) {
 		// No se establece userID
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Create(c)
+		apperrtest.Handle(controller.Create, c)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Missing authentication", response["error"])
+		assert.Equal(t, "unauthenticated", apperrtest.ProblemCode(t, w))
 	})
 
 	t.Run("Create_InvalidVideoID", func(t *testing.T) {
@@ -91,13 +90,11 @@ func TestVoteController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "invalid"}}
 
-		controller.Create(c)
+		apperrtest.Handle(controller.Create, c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Invalid video ID format", response["error"])
+		assert.Equal(t, "invalid_video_id", apperrtest.ProblemCode(t, w))
 	})
 
 	t.Run("Create_AlreadyVoted", func(t *testing.T) {
@@ -107,7 +104,7 @@ func TestVoteController(t *testing.T) {
 		userID := uint(1)
 		videoID := uint(1)
 
-		mockSvc.On("CreateVote", userID, videoID).Return(assert.AnError)
+		mockSvc.On("CreateVote", userID, videoID).Return(ErrAlreadyVoted)
 
 		req := httptest.NewRequest("POST", "/public/videos/1/vote", nil)
 		w := httptest.NewRecorder()
@@ -117,9 +114,10 @@ func TestVoteController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Create(c)
+		apperrtest.Handle(controller.Create, c)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "already_voted", apperrtest.ProblemCode(t, w))
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -138,16 +136,17 @@ func TestVoteController(t *testing.T) {
 
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
+		c.Request.Header.Set("Accept-Language", "en-US,en;q=0.9,es;q=0.5")
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Delete(c)
+		apperrtest.Handle(controller.Delete, c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
 		var response map[string]string
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Voto successfully deleted.", response["message"])
+		assert.Equal(t, "Vote successfully deleted.", response["message"])
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -164,13 +163,11 @@ func TestVoteController(t *testing.T) {
 		// No se establece userID
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Delete(c)
+		apperrtest.Handle(controller.Delete, c)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Missing authentication.", response["error"])
+		assert.Equal(t, "unauthenticated", apperrtest.ProblemCode(t, w))
 	})
 
 	t.Run("Delete_InvalidVideoID", func(t *testing.T) {
@@ -187,13 +184,11 @@ func TestVoteController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "invalid"}}
 
-		controller.Delete(c)
+		apperrtest.Handle(controller.Delete, c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "Invalid video ID format", response["error"])
+		assert.Equal(t, "invalid_video_id", apperrtest.ProblemCode(t, w))
 	})
 
 	t.Run("Delete_VoteNotExists", func(t *testing.T) {
@@ -203,7 +198,7 @@ func TestVoteController(t *testing.T) {
 		userID := uint(1)
 		videoID := uint(1)
 
-		mockSvc.On("DeleteVote", userID, videoID).Return(assert.AnError)
+		mockSvc.On("DeleteVote", userID, videoID).Return(ErrVoteNotFound)
 
 		req := httptest.NewRequest("DELETE", "/public/videos/1/vote", nil)
 		w := httptest.NewRecorder()
@@ -213,9 +208,10 @@ func TestVoteController(t *testing.T) {
 		c.Set("userID", userID)
 		c.Params = []gin.Param{{Key: "video_id", Value: "1"}}
 
-		controller.Delete(c)
+		apperrtest.Handle(controller.Delete, c)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "vote_not_found", apperrtest.ProblemCode(t, w))
 
 		mockSvc.AssertExpectations(t)
 	})
@@ -11,3 +11,8 @@ type VoteResponse struct {
 	VideoID   uint      `json:"video_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// VoteMessageResponse es la respuesta de registrar o eliminar un voto
+type VoteMessageResponse struct {
+	Message string `json:"message"`
+}
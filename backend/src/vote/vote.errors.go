@@ -0,0 +1,10 @@
+package vote
+
+import "anb-app/src/apperr"
+
+// Errores de votos con código estable. Un video inexistente o retirado se reporta
+// con video.ErrVideoNotFound.
+var (
+	ErrAlreadyVoted = apperr.Conflict("already_voted", "user has already voted for this video")
+	ErrVoteNotFound = apperr.NotFound("vote_not_found", "vote does not exist")
+)
@@ -0,0 +1,22 @@
+package vote
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpVoteRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/public/videos/:video_id/vote", Tags: []string{"votes"}, Auth: true,
+		Summary:  "Votar por un video (un voto por usuario)",
+		Response: VoteMessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/public/videos/:video_id/vote", Tags: []string{"votes"}, Auth: true,
+		Summary:  "Retirar el voto",
+		Response: VoteMessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+}
@@ -1,13 +1,20 @@
 package vote
 
 import (
+	"anb-app/src/outbox"
 	"anb-app/src/video"
+	"anb-app/src/webhook"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// VoteThresholds son los conteos de votos que disparan vote.threshold_reached
+var VoteThresholds = []int{10, 50, 100, 500, 1000}
+
 type voteService struct {
 	voteRepo VoteRepository
 	db       *gorm.DB
@@ -26,7 +33,7 @@ func (s *voteService) CreateVote(userID uint, videoID uint) error {
 		return errors.New("database error when checking for existing vote")
 	}
 	if existingVote != nil {
-		return errors.New("user has already voted for this video")
+		return ErrAlreadyVoted
 	}
 
 	tx := s.db.Begin()
@@ -44,21 +51,65 @@ func (s *voteService) CreateVote(userID uint, videoID uint) error {
 		return err
 	}
 
-	if err := tx.Model(&video.Video{}).Where("id = ?", videoID).UpdateColumn("vote_count", gorm.Expr("vote_count + 1")).Error; err != nil {
+	// El UPDATE excluye los videos retirados: si no toca ninguna fila el video no
+	// existe o está en la papelera, y no admite votos nuevos
+	result := tx.Model(&video.Video{}).Where("id = ?", videoID).UpdateColumn("vote_count", gorm.Expr("vote_count + 1"))
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return video.ErrVideoNotFound
+	}
+
+	// El conteo se lee dentro de la transacción: la fila quedó bloqueada por el
+	// UPDATE, así que solo uno de los votos concurrentes ve el umbral exacto
+	var voteCount int
+	if err := tx.Model(&video.Video{}).Where("id = ?", videoID).Select("vote_count").Scan(&voteCount).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	event, err := thresholdEvent(videoID, voteCount)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if event != nil {
+		if err := outbox.Add(tx, *event); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 
 	return tx.Commit().Error
 }
 
+// thresholdEvent devuelve el evento vote.threshold_reached si votes es uno de los
+// umbrales. La clave de deduplicación evita repetirlo si el conteo baja y vuelve a subir.
+func thresholdEvent(videoID uint, votes int) (*outbox.Message, error) {
+	if !slices.Contains(VoteThresholds, votes) {
+		return nil, nil
+	}
+	event, err := webhook.NewEvent(webhook.EventVoteThresholdReached, webhook.VoteThresholdData{
+		VideoID:   videoID,
+		Threshold: votes,
+		Votes:     votes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	event = event.WithDedupeKey(fmt.Sprintf("vote_threshold:%d:%d", videoID, votes))
+	return &event, nil
+}
+
 func (s *voteService) DeleteVote(userID uint, videoID uint) error {
 	existingVote, err := s.voteRepo.FindByUserAndVideo(userID, videoID)
 	if err != nil {
 		return errors.New("database error when checking for vote to delete")
 	}
 	if existingVote == nil {
-		return errors.New("vote does not exist")
+		return ErrVoteNotFound
 	}
 
 	tx := s.db.Begin()
@@ -66,12 +117,19 @@ func (s *voteService) DeleteVote(userID uint, videoID uint) error {
 		return tx.Error
 	}
 
-	if err := s.voteRepo.DeleteByUserAndVideo(userID, videoID); err != nil {
+	// Los votos de un video retirado quedan congelados: se conservan por si se
+	// restaura y se borran con él al purgarlo
+	result := tx.Model(&video.Video{}).Where("id = ?", videoID).UpdateColumn("vote_count", gorm.Expr("vote_count - 1"))
+	if result.Error != nil {
 		tx.Rollback()
-		return err
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return video.ErrVideoNotFound
 	}
 
-	if err := tx.Model(&video.Video{}).Where("id = ?", videoID).UpdateColumn("vote_count", gorm.Expr("vote_count - 1")).Error; err != nil {
+	if err := s.voteRepo.DeleteByUserAndVideo(userID, videoID); err != nil {
 		tx.Rollback()
 		return err
 	}
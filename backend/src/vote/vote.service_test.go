@@ -1,6 +1,7 @@
 package vote
 
 import (
+	"anb-app/src/webhook"
 	"errors"
 	"testing"
 
@@ -113,3 +114,24 @@ func TestVoteService_Simple(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestThresholdEvent(t *testing.T) {
+	t.Run("NotAThreshold", func(t *testing.T) {
+		event, err := thresholdEvent(7, 11)
+
+		assert.NoError(t, err)
+		assert.Nil(t, event)
+	})
+
+	t.Run("ThresholdReached", func(t *testing.T) {
+		event, err := thresholdEvent(7, 50)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, event) {
+			assert.Equal(t, webhook.TopicWebhook, event.Topic)
+			assert.Equal(t, webhook.EventVoteThresholdReached, event.EventType)
+			assert.JSONEq(t, `{"video_id":7,"threshold":50,"votes":50}`, event.Payload)
+			assert.Equal(t, "vote_threshold:7:50", *event.DedupeKey)
+		}
+	})
+}
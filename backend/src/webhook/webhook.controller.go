@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"anb-app/src/apperr"
+	"anb-app/src/i18n"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type WebhookService interface {
+	CreateEndpoint(req CreateEndpointRequest, adminID uint) (*EndpointResponse, error)
+	ListEndpoints() ([]EndpointResponse, error)
+	DeleteEndpoint(endpointID uint) error
+	ListDeliveries(endpointID uint) ([]Delivery, error)
+	Redeliver(deliveryID uint64) error
+}
+
+type WebhookController struct {
+	webhookService WebhookService
+	validate       *validator.Validate
+}
+
+func NewWebhookController(webhookService WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		validate:       apperr.NewValidator(),
+	}
+}
+
+// Los handlers registran los errores con c.Error y el middleware de apperr arma la
+// respuesta problem+json
+
+func parseEndpointID(c *gin.Context) (uint, error) {
+	endpointID, err := strconv.ParseUint(c.Param("endpoint_id"), 10, 32)
+	if err != nil {
+		return 0, ErrInvalidEndpointID
+	}
+	return uint(endpointID), nil
+}
+
+func (wc *WebhookController) CreateEndpoint(c *gin.Context) {
+	var req CreateEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperr.Validation("invalid_body", "invalid or malformed input data").Wrap(err))
+		return
+	}
+	if err := wc.validate.Struct(req); err != nil {
+		c.Error(apperr.FromValidation(err))
+		return
+	}
+
+	endpoint, err := wc.webhookService.CreateEndpoint(req, c.GetUint("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+func (wc *WebhookController) ListEndpoints(c *gin.Context) {
+	endpoints, err := wc.webhookService.ListEndpoints()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+func (wc *WebhookController) DeleteEndpoint(c *gin.Context) {
+	endpointID, err := parseEndpointID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := wc.webhookService.DeleteEndpoint(endpointID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (wc *WebhookController) ListDeliveries(c *gin.Context) {
+	endpointID, err := parseEndpointID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveries, err := wc.webhookService.ListDeliveries(endpointID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidDeliveryID)
+		return
+	}
+
+	if err := wc.webhookService.Redeliver(deliveryID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, RedeliverResponse{Message: i18n.Message(c, "webhook.redelivery_scheduled")})
+}
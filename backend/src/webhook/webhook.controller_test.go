@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"anb-app/src/apperr"
+	"anb-app/src/apperr/apperrtest"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateEndpoint(req CreateEndpointRequest, adminID uint) (*EndpointResponse, error) {
+	args := m.Called(req, adminID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*EndpointResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListEndpoints() ([]EndpointResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]EndpointResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteEndpoint(endpointID uint) error {
+	args := m.Called(endpointID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(endpointID uint) ([]Delivery, error) {
+	args := m.Called(endpointID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(deliveryID uint64) error {
+	args := m.Called(deliveryID)
+	return args.Error(0)
+}
+
+// newTestRouter registra las rutas reales con un middleware que simula un admin autenticado
+func newTestRouter(svc WebhookService) *gin.Engine {
+	router := gin.New()
+	router.Use(apperr.Middleware())
+	auth := func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	}
+	admin := func(c *gin.Context) { c.Next() }
+	SignUpWebhookRoutes(router.Group("/api/v1"), NewWebhookController(svc), auth, admin)
+	return router
+}
+
+func TestWebhookController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("CreateEndpoint_Success", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		req := CreateEndpointRequest{URL: "https://example.com/hooks"}
+		mockSvc.On("CreateEndpoint", req, uint(1)).Return(&EndpointResponse{ID: 3, URL: req.URL, Secret: "whsec_abc"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/webhooks", bytes.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response EndpointResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "whsec_abc", response.Secret)
+	})
+
+	t.Run("CreateEndpoint_MissingURL", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/webhooks", bytes.NewReader([]byte(`{}`))))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "validation_failed", apperrtest.ProblemCode(t, w))
+		mockSvc.AssertNotCalled(t, "CreateEndpoint", mock.Anything, mock.Anything)
+	})
+
+	t.Run("DeleteEndpoint_NotFound", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		mockSvc.On("DeleteEndpoint", uint(5)).Return(ErrEndpointNotFound)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/admin/webhooks/5", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "endpoint_not_found", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("DeleteEndpoint_InvalidID", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/admin/webhooks/abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_endpoint_id", apperrtest.ProblemCode(t, w))
+		mockSvc.AssertNotCalled(t, "DeleteEndpoint", mock.Anything)
+	})
+
+	t.Run("ListDeliveries_Success", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		mockSvc.On("ListDeliveries", uint(5)).Return([]Delivery{{ID: 1, EndpointID: 5, Status: DeliveryFailed}}, nil)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/webhooks/5/deliveries", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []Delivery
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, DeliveryFailed, response[0].Status)
+	})
+
+	t.Run("Redeliver_Accepted", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		mockSvc.On("Redeliver", uint64(12)).Return(nil)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/webhooks/deliveries/12/redeliver", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Redeliver_InactiveEndpoint", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		mockSvc.On("Redeliver", uint64(12)).Return(ErrEndpointInactive)
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/webhooks/deliveries/12/redeliver", nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "endpoint_inactive", apperrtest.ProblemCode(t, w))
+	})
+
+	t.Run("ListEndpoints_InternalError", func(t *testing.T) {
+		mockSvc := new(MockWebhookService)
+		mockSvc.On("ListEndpoints").Return([]EndpointResponse(nil), errors.New("connection refused"))
+
+		w := httptest.NewRecorder()
+		newTestRouter(mockSvc).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/webhooks", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_error", apperrtest.ProblemCode(t, w))
+	})
+}
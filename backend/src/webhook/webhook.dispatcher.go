@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"anb-app/src/outbox"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// MaxAttempts es el número de intentos antes de dar una entrega por fallida
+	MaxAttempts = 8
+	// Los reintentos empiezan en 30s y se duplican hasta maxRetryDelay
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+
+	requestTimeout = 10 * time.Second
+	// Margen del lease sobre el peor caso del lote (todos los envíos llegan al timeout)
+	deliveryLeaseMargin = time.Minute
+
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 20
+)
+
+// Cabeceras que acompañan cada entrega
+const (
+	HeaderEvent     = "X-ANB-Event"
+	HeaderEventID   = "X-ANB-Event-ID"
+	HeaderDelivery  = "X-ANB-Delivery"
+	HeaderTimestamp = "X-ANB-Timestamp"
+	HeaderSignature = "X-ANB-Signature"
+)
+
+// Envelope es el cuerpo JSON que reciben los endpoints
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Sign calcula la firma de un cuerpo: HMAC-SHA256 de "<timestamp>.<body>" con el
+// secreto del endpoint. Incluir el timestamp permite al receptor rechazar reenvíos viejos.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// FanOut es el handler del outbox para TopicWebhook: crea una entrega pendiente por
+// cada endpoint activo suscrito al evento. Si el relay lo repite con el mismo mensaje, las
+// entregas que ya existen se omiten.
+func FanOut(ctx context.Context, db *gorm.DB, msg *outbox.Message) error {
+	var endpoints []Endpoint
+	if err := db.Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        fmt.Sprintf("evt_%d", msg.ID),
+		Type:      msg.EventType,
+		CreatedAt: msg.CreatedAt.UTC(),
+		Data:      json.RawMessage(msg.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []Delivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribed(msg.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			EndpointID:    endpoint.ID,
+			EventID:       msg.ID,
+			EventType:     msg.EventType,
+			Payload:       string(payload),
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// Dispatcher envía las entregas pendientes y programa los reintentos
+type Dispatcher struct {
+	db           *gorm.DB
+	client       *http.Client
+	pollInterval time.Duration
+	batchSize    int
+	now          func() time.Time
+}
+
+func NewDispatcher(db *gorm.DB) *Dispatcher {
+	return &Dispatcher{
+		db:           db,
+		client:       &http.Client{Timeout: requestTimeout},
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		now:          time.Now,
+	}
+}
+
+// Run envía entregas hasta que ctx termina
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.DispatchBatch(ctx)
+			if err != nil {
+				log.Printf("Webhooks: failed to dispatch batch: %v", err)
+				break
+			}
+			if sent < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchBatch toma las entregas vencidas, las envía y devuelve cuántas intentó
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.claim(ctx)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	endpointIDs := make([]uint, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		endpointIDs = append(endpointIDs, delivery.EndpointID)
+	}
+	var endpoints []Endpoint
+	if err := d.db.WithContext(ctx).Where("id IN ?", endpointIDs).Find(&endpoints).Error; err != nil {
+		return 0, err
+	}
+	byID := make(map[uint]Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byID[endpoint.ID] = endpoint
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		endpoint, ok := byID[delivery.EndpointID]
+		if !ok || !endpoint.Active {
+			// El endpoint se desactivó después de crear la entrega
+			delivery.Status = DeliveryFailed
+			delivery.LastError = "endpoint is no longer active"
+		} else {
+			d.attempt(ctx, endpoint, delivery)
+		}
+		if err := d.db.WithContext(ctx).Save(delivery).Error; err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// lease es el tiempo que una entrega tomada no la ve otro dispatcher. Cubre el lote completo
+// con cada envío agotando requestTimeout, para que no se repita una entrega en curso.
+func (d *Dispatcher) lease() time.Duration {
+	return time.Duration(d.batchSize)*requestTimeout + deliveryLeaseMargin
+}
+
+// claim marca las entregas vencidas con un lease para que otro dispatcher no las repita
+func (d *Dispatcher) claim(ctx context.Context) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := d.now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at").Limit(d.batchSize).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&Delivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(d.lease())).Error
+	})
+	return deliveries, err
+}
+
+// attempt hace un envío y actualiza el estado de la entrega según la respuesta
+func (d *Dispatcher) attempt(ctx context.Context, endpoint Endpoint, delivery *Delivery) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	statusCode, err := d.send(ctx, endpoint, delivery, now)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= MaxAttempts {
+		delivery.Status = DeliveryFailed
+		log.Printf("Webhooks: delivery %d to endpoint %d failed permanently: %v", delivery.ID, endpoint.ID, err)
+		return
+	}
+	delivery.NextAttemptAt = now.Add(RetryDelay(delivery.Attempts))
+}
+
+func (d *Dispatcher) send(ctx context.Context, endpoint Endpoint, delivery *Delivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ANB-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, fmt.Sprintf("evt_%d", delivery.EventID))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// RetryDelay devuelve la espera tras el intento n: 30s, 1m, 2m... hasta maxRetryDelay
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 20 {
+		return maxRetryDelay
+	}
+	return min(baseRetryDelay<<(attempts-1), maxRetryDelay)
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDispatcher(now time.Time) *Dispatcher {
+	d := NewDispatcher(nil)
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func TestSign(t *testing.T) {
+	// Valor calculado con: printf '1700000000.{"a":1}' | openssl dgst -sha256 -hmac whsec_test
+	signature := Sign("whsec_test", 1700000000, []byte(`{"a":1}`))
+
+	assert.Equal(t, "sha256=38877139021993b830af32feea6e18a8da83eb2f6e49ee50bd9e4cf4ca4d3789", signature)
+	assert.NotEqual(t, signature, Sign("whsec_other", 1700000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, signature, Sign("whsec_test", 1700000001, []byte(`{"a":1}`)))
+}
+
+func TestEndpointSubscribed(t *testing.T) {
+	all := Endpoint{}
+	some := Endpoint{Events: []string{EventVideoProcessed}}
+
+	assert.True(t, all.Subscribed(EventVideoFailed))
+	assert.True(t, some.Subscribed(EventVideoProcessed))
+	assert.False(t, some.Subscribed(EventVideoFailed))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 4*time.Minute, RetryDelay(4))
+	assert.Equal(t, 6*time.Hour, RetryDelay(12))
+	assert.Equal(t, 6*time.Hour, RetryDelay(100))
+}
+
+func TestDispatcherAttempt(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Success_SendsSignedRequest", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		endpoint := Endpoint{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true}
+		delivery := &Delivery{ID: 42, EndpointID: 1, EventID: 7, EventType: EventVideoProcessed, Payload: `{"id":"evt_7"}`, Status: DeliveryPending}
+
+		newTestDispatcher(now).attempt(context.Background(), endpoint, delivery)
+
+		assert.Equal(t, DeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+		assert.NotNil(t, delivery.DeliveredAt)
+
+		assert.Equal(t, `{"id":"evt_7"}`, string(body))
+		assert.Equal(t, EventVideoProcessed, received.Header.Get(HeaderEvent))
+		assert.Equal(t, "evt_7", received.Header.Get(HeaderEventID))
+		assert.Equal(t, "42", received.Header.Get(HeaderDelivery))
+		assert.Equal(t, "1740823200", received.Header.Get(HeaderTimestamp))
+		assert.Equal(t, Sign("whsec_test", now.Unix(), body), received.Header.Get(HeaderSignature))
+	})
+
+	t.Run("ErrorStatus_SchedulesRetry", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		endpoint := Endpoint{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true}
+		delivery := &Delivery{ID: 42, Attempts: 2, Payload: `{}`, Status: DeliveryPending}
+
+		newTestDispatcher(now).attempt(context.Background(), endpoint, delivery)
+
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, http.StatusBadGateway, delivery.LastStatusCode)
+		assert.Contains(t, delivery.LastError, "502")
+		assert.Equal(t, now.Add(2*time.Minute), delivery.NextAttemptAt)
+		assert.Nil(t, delivery.DeliveredAt)
+	})
+
+	t.Run("LastAttempt_MarksFailed", func(t *testing.T) {
+		endpoint := Endpoint{ID: 1, URL: "http://127.0.0.1:1", Secret: "whsec_test", Active: true}
+		delivery := &Delivery{ID: 42, Attempts: MaxAttempts - 1, Payload: `{}`, Status: DeliveryPending}
+
+		newTestDispatcher(now).attempt(context.Background(), endpoint, delivery)
+
+		assert.Equal(t, DeliveryFailed, delivery.Status)
+		assert.Equal(t, MaxAttempts, delivery.Attempts)
+		assert.NotEmpty(t, delivery.LastError)
+	})
+}
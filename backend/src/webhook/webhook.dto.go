@@ -0,0 +1,24 @@
+package webhook
+
+import "time"
+
+type CreateEndpointRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+}
+
+// EndpointResponse incluye el secreto solo en la respuesta de creación
+type EndpointResponse struct {
+	ID          uint      `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RedeliverResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import "time"
+
+// Estados de una entrega
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Endpoint es una URL de un sistema externo registrada por un administrador
+type Endpoint struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	URL         string `json:"url" gorm:"not null"`
+	Description string `json:"description"`
+	// Secreto para firmar los payloads; solo se muestra al crear el endpoint
+	Secret string `json:"-" gorm:"not null"`
+	// Eventos a los que está suscrito; vacío significa todos
+	Events    []string  `json:"events" gorm:"serializer:json"`
+	Active    bool      `json:"active" gorm:"not null;default:true"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribed indica si el endpoint debe recibir el tipo de evento
+func (e Endpoint) Subscribed(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, event := range e.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery es el envío de un evento a un endpoint; también sirve de log de entregas
+type Delivery struct {
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+	// Una entrega por endpoint y evento, aunque el relay reparta el mismo mensaje dos veces
+	EndpointID uint `json:"endpoint_id" gorm:"uniqueIndex:idx_webhook_deliveries_endpoint_event,priority:1;not null"`
+	// ID del mensaje del outbox que originó el evento
+	EventID        uint64     `json:"event_id" gorm:"index;uniqueIndex:idx_webhook_deliveries_endpoint_event,priority:2;not null"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"type:jsonb;not null"`
+	Status         string     `json:"status" gorm:"index;not null;default:'pending'"`
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
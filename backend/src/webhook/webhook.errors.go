@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"anb-app/src/apperr"
+	"strings"
+)
+
+// Errores de webhooks con código estable; los mensajes se mantienen para los logs y los tests
+var (
+	ErrInvalidEndpointURL = apperr.Validation("invalid_endpoint_url", "invalid endpoint url",
+		apperr.FieldError{Field: "url", Code: "url", Message: "url must be a valid URL"})
+	ErrInvalidEventType  = apperr.Validation("invalid_event_type", "invalid event type")
+	ErrInvalidEndpointID = apperr.Validation("invalid_endpoint_id", "endpoint_id must be a positive integer",
+		apperr.FieldError{Field: "endpoint_id", Code: "number", Message: "endpoint_id must be a positive integer"})
+	ErrInvalidDeliveryID = apperr.Validation("invalid_delivery_id", "delivery_id must be a positive integer",
+		apperr.FieldError{Field: "delivery_id", Code: "number", Message: "delivery_id must be a positive integer"})
+	ErrEndpointNotFound = apperr.NotFound("endpoint_not_found", "endpoint not found")
+	ErrDeliveryNotFound = apperr.NotFound("delivery_not_found", "delivery not found")
+	ErrEndpointInactive = apperr.Conflict("endpoint_inactive", "endpoint is not active")
+)
+
+// invalidEvent arma el error de un evento desconocido con la lista de los válidos
+func invalidEvent(event string) *apperr.Error {
+	valid := strings.Join(Events, ", ")
+	invalid := ErrInvalidEventType.WithMessage("invalid event type: " + event)
+	invalid.Fields = []apperr.FieldError{{Field: "events", Code: "oneof", Message: "events must be one of: " + valid, Param: valid}}
+	return invalid
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import "anb-app/src/outbox"
+
+// TopicWebhook es el topic del outbox cuyos mensajes se reparten a los endpoints
+const TopicWebhook = "webhook"
+
+// Eventos que se pueden suscribir
+const (
+	EventVideoUploaded        = "video.uploaded"
+	EventVideoProcessed       = "video.processed"
+	EventVideoFailed          = "video.failed"
+	EventVoteThresholdReached = "vote.threshold_reached"
+)
+
+var Events = []string{
+	EventVideoUploaded,
+	EventVideoProcessed,
+	EventVideoFailed,
+	EventVoteThresholdReached,
+}
+
+// VideoData es el contenido de los eventos video.*
+type VideoData struct {
+	VideoID         uint   `json:"video_id"`
+	UserID          uint   `json:"user_id"`
+	Title           string `json:"title"`
+	Status          string `json:"status"`
+	PipelineVersion string `json:"pipeline_version,omitempty"`
+	FailureReason   string `json:"failure_reason,omitempty"`
+}
+
+// VoteThresholdData es el contenido de vote.threshold_reached
+type VoteThresholdData struct {
+	VideoID   uint `json:"video_id"`
+	Threshold int  `json:"threshold"`
+	Votes     int  `json:"votes"`
+}
+
+// NewEvent prepara el mensaje del outbox para un evento de webhook
+func NewEvent(eventType string, data any) (outbox.Message, error) {
+	return outbox.New(TopicWebhook, eventType, data)
+}
+
+func knownEvent(eventType string) bool {
+	for _, event := range Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
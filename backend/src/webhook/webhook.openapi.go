@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"anb-app/src/openapi"
+	"net/http"
+)
+
+// Operations documenta las rutas de SignUpWebhookRoutes para el documento OpenAPI
+var Operations = []openapi.Operation{
+	{
+		Method: http.MethodPost, Path: "/admin/webhooks", Tags: []string{"admin"}, Auth: true,
+		Summary:     "Registrar un endpoint de webhooks",
+		Description: "La respuesta incluye el secreto para verificar las firmas; no se vuelve a mostrar.",
+		Request:     CreateEndpointRequest{},
+		Status:      http.StatusCreated, Response: EndpointResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/webhooks", Tags: []string{"admin"}, Auth: true,
+		Summary:  "Endpoints de webhooks",
+		Response: []EndpointResponse{},
+		Errors:   []int{http.StatusForbidden},
+	},
+	{
+		Method: http.MethodDelete, Path: "/admin/webhooks/:endpoint_id", Tags: []string{"admin"}, Auth: true,
+		Summary: "Desactivar un endpoint; su log de entregas se conserva",
+		Status:  http.StatusNoContent,
+		Errors:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/webhooks/:endpoint_id/deliveries", Tags: []string{"admin"}, Auth: true,
+		Summary:  "Últimas entregas de un endpoint",
+		Response: []Delivery{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/webhooks/deliveries/:delivery_id/redeliver", Tags: []string{"admin"}, Auth: true,
+		Summary: "Reenviar una entrega",
+		Status:  http.StatusAccepted, Response: RedeliverResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	},
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(endpoint *Endpoint) (*Endpoint, error)
+	FindEndpoints() ([]Endpoint, error)
+	FindEndpointByID(endpointID uint) (*Endpoint, error)
+	UpdateEndpoint(endpoint *Endpoint) error
+	FindDeliveries(endpointID uint, limit int) ([]Delivery, error)
+	FindDeliveryByID(deliveryID uint64) (*Delivery, error)
+	ScheduleRedelivery(deliveryID uint64, at time.Time) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) CreateEndpoint(endpoint *Endpoint) (*Endpoint, error) {
+	result := r.db.Create(endpoint)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return endpoint, nil
+}
+
+func (r *webhookRepository) FindEndpoints() ([]Endpoint, error) {
+	var endpoints []Endpoint
+	result := r.db.Order("id").Find(&endpoints)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepository) FindEndpointByID(endpointID uint) (*Endpoint, error) {
+	var endpoint Endpoint
+	result := r.db.First(&endpoint, endpointID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) UpdateEndpoint(endpoint *Endpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+func (r *webhookRepository) FindDeliveries(endpointID uint, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	result := r.db.Where("endpoint_id = ?", endpointID).Order("id DESC").Limit(limit).Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) FindDeliveryByID(deliveryID uint64) (*Delivery, error) {
+	var delivery Delivery
+	result := r.db.First(&delivery, deliveryID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+// ScheduleRedelivery vuelve a poner la entrega en cola con los intentos a cero
+func (r *webhookRepository) ScheduleRedelivery(deliveryID uint64, at time.Time) error {
+	return r.db.Model(&Delivery{}).Where("id = ?", deliveryID).Updates(map[string]any{
+		"status":          DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": at,
+		"last_error":      "",
+	}).Error
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import "github.com/gin-gonic/gin"
+
+// SignUpWebhookRoutes registra la administración de webhooks; adminMiddleware
+// se aplica después de authMiddleware
+func SignUpWebhookRoutes(router *gin.RouterGroup, wc *WebhookController, authMiddleware, adminMiddleware gin.HandlerFunc) {
+
+	webhookRoutes := router.Group("/admin/webhooks", authMiddleware, adminMiddleware)
+	{
+		webhookRoutes.POST("", wc.CreateEndpoint)
+		webhookRoutes.GET("", wc.ListEndpoints)
+		webhookRoutes.DELETE("/:endpoint_id", wc.DeleteEndpoint)
+		webhookRoutes.GET("/:endpoint_id/deliveries", wc.ListDeliveries)
+		webhookRoutes.POST("/deliveries/:delivery_id/redeliver", wc.Redeliver)
+	}
+}
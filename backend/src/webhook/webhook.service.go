@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+)
+
+// Número de entregas que devuelve el log de un endpoint
+const deliveryLogLimit = 100
+
+type webhookService struct {
+	webhookRepo WebhookRepository
+}
+
+func NewWebhookService(webhookRepo WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (s *webhookService) CreateEndpoint(req CreateEndpointRequest, adminID uint) (*EndpointResponse, error) {
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, ErrInvalidEndpointURL
+	}
+	for _, event := range req.Events {
+		if !knownEvent(event) {
+			return nil, invalidEvent(event)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.webhookRepo.CreateEndpoint(&Endpoint{
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      req.Events,
+		Active:      true,
+		CreatedBy:   adminID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toEndpointResponse(endpoint)
+	response.Secret = endpoint.Secret
+	return response, nil
+}
+
+func (s *webhookService) ListEndpoints() ([]EndpointResponse, error) {
+	endpoints, err := s.webhookRepo.FindEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]EndpointResponse, 0, len(endpoints))
+	for i := range endpoints {
+		responses = append(responses, *toEndpointResponse(&endpoints[i]))
+	}
+	return responses, nil
+}
+
+// DeleteEndpoint desactiva el endpoint; se conserva para no perder su log de entregas
+func (s *webhookService) DeleteEndpoint(endpointID uint) error {
+	endpoint, err := s.webhookRepo.FindEndpointByID(endpointID)
+	if err != nil {
+		return err
+	}
+	if endpoint == nil || !endpoint.Active {
+		return ErrEndpointNotFound
+	}
+
+	endpoint.Active = false
+	return s.webhookRepo.UpdateEndpoint(endpoint)
+}
+
+func (s *webhookService) ListDeliveries(endpointID uint) ([]Delivery, error) {
+	endpoint, err := s.webhookRepo.FindEndpointByID(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, ErrEndpointNotFound
+	}
+
+	return s.webhookRepo.FindDeliveries(endpointID, deliveryLogLimit)
+}
+
+func (s *webhookService) Redeliver(deliveryID uint64) error {
+	delivery, err := s.webhookRepo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery == nil {
+		return ErrDeliveryNotFound
+	}
+
+	endpoint, err := s.webhookRepo.FindEndpointByID(delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+	if endpoint == nil || !endpoint.Active {
+		return ErrEndpointInactive
+	}
+
+	return s.webhookRepo.ScheduleRedelivery(deliveryID, time.Now())
+}
+
+func toEndpointResponse(endpoint *Endpoint) *EndpointResponse {
+	events := endpoint.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &EndpointResponse{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		Events:      events,
+		Active:      endpoint.Active,
+		CreatedAt:   endpoint.CreatedAt,
+	}
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
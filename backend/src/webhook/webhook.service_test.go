@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(endpoint *Endpoint) (*Endpoint, error) {
+	args := m.Called(endpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Endpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindEndpoints() ([]Endpoint, error) {
+	args := m.Called()
+	return args.Get(0).([]Endpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindEndpointByID(endpointID uint) (*Endpoint, error) {
+	args := m.Called(endpointID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Endpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateEndpoint(endpoint *Endpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindDeliveries(endpointID uint, limit int) ([]Delivery, error) {
+	args := m.Called(endpointID, limit)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindDeliveryByID(deliveryID uint64) (*Delivery, error) {
+	args := m.Called(deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Delivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ScheduleRedelivery(deliveryID uint64, at time.Time) error {
+	args := m.Called(deliveryID, at)
+	return args.Error(0)
+}
+
+func TestWebhookService(t *testing.T) {
+	t.Run("CreateEndpoint_ReturnsSecretOnce", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepository)
+		svc := NewWebhookService(mockRepo)
+
+		stored := &Endpoint{}
+		mockRepo.On("CreateEndpoint", mock.AnythingOfType("*webhook.Endpoint")).Run(func(args mock.Arguments) {
+			*stored = *args.Get(0).(*Endpoint)
+			stored.ID = 3
+		}).Return(stored, nil)
+
+		created, err := svc.CreateEndpoint(CreateEndpointRequest{URL: "https://example.com/hooks", Events: []string{EventVideoProcessed}}, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), created.ID)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.True(t, created.Active)
+
+		mockRepo.On("FindEndpoints").Return([]Endpoint{{ID: 3, URL: "https://example.com/hooks", Secret: created.Secret}}, nil)
+		listed, err := svc.ListEndpoints()
+		assert.NoError(t, err)
+		assert.Empty(t, listed[0].Secret)
+		assert.Equal(t, []string{}, listed[0].Events)
+	})
+
+	t.Run("CreateEndpoint_InvalidEvent", func(t *testing.T) {
+		svc := NewWebhookService(new(MockWebhookRepository))
+
+		_, err := svc.CreateEndpoint(CreateEndpointRequest{URL: "https://example.com/hooks", Events: []string{"video.deleted"}}, 1)
+
+		assert.ErrorIs(t, err, ErrInvalidEventType)
+		assert.Contains(t, err.Error(), "video.deleted")
+	})
+
+	t.Run("CreateEndpoint_InvalidURL", func(t *testing.T) {
+		svc := NewWebhookService(new(MockWebhookRepository))
+
+		_, err := svc.CreateEndpoint(CreateEndpointRequest{URL: "ftp://example.com"}, 1)
+
+		assert.ErrorIs(t, err, ErrInvalidEndpointURL)
+	})
+
+	t.Run("DeleteEndpoint_Deactivates", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepository)
+		svc := NewWebhookService(mockRepo)
+
+		endpoint := &Endpoint{ID: 3, Active: true}
+		mockRepo.On("FindEndpointByID", uint(3)).Return(endpoint, nil)
+		mockRepo.On("UpdateEndpoint", endpoint).Return(nil)
+
+		err := svc.DeleteEndpoint(3)
+
+		assert.NoError(t, err)
+		assert.False(t, endpoint.Active)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteEndpoint_NotFound", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepository)
+		svc := NewWebhookService(mockRepo)
+
+		mockRepo.On("FindEndpointByID", uint(3)).Return(nil, nil)
+
+		err := svc.DeleteEndpoint(3)
+
+		assert.ErrorIs(t, err, ErrEndpointNotFound)
+	})
+
+	t.Run("Redeliver_Schedules", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepository)
+		svc := NewWebhookService(mockRepo)
+
+		mockRepo.On("FindDeliveryByID", uint64(9)).Return(&Delivery{ID: 9, EndpointID: 3, Status: DeliveryFailed}, nil)
+		mockRepo.On("FindEndpointByID", uint(3)).Return(&Endpoint{ID: 3, Active: true}, nil)
+		mockRepo.On("ScheduleRedelivery", uint64(9), mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := svc.Redeliver(9)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Redeliver_InactiveEndpoint", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepository)
+		svc := NewWebhookService(mockRepo)
+
+		mockRepo.On("FindDeliveryByID", uint64(9)).Return(&Delivery{ID: 9, EndpointID: 3}, nil)
+		mockRepo.On("FindEndpointByID", uint(3)).Return(&Endpoint{ID: 3, Active: false}, nil)
+
+		err := svc.Redeliver(9)
+
+		assert.ErrorIs(t, err, ErrEndpointInactive)
+		mockRepo.AssertNotCalled(t, "ScheduleRedelivery", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"anb-app/src/pipeline"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// rejectedError indica que el clip no se puede procesar y no tiene sentido reintentarlo
+type rejectedError struct {
+	reason string
+}
+
+func (e *rejectedError) Error() string {
+	return "video rejected: " + e.reason
+}
+
+// probeMedia inspecciona un archivo con ffprobe
+func probeMedia(ctx context.Context, path string) (*pipeline.MediaInfo, error) {
+	start := time.Now()
+	output, err := exec.CommandContext(ctx, "ffprobe", pipeline.ProbeArgs(path)...).Output()
+	observeStep("probe", time.Since(start).Seconds(), err)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.ParseProbe(output)
+}
+
+// prepareInput valida el archivo original y, si no tiene pista de audio, le agrega
+// una silenciosa. Devuelve la ruta a usar como entrada del pipeline y si el clip
+// traía audio propio.
+func prepareInput(ctx context.Context, originalPath, workDir, baseName string) (string, bool, error) {
+	info, err := probeMedia(ctx, originalPath)
+	if err != nil {
+		return "", false, &rejectedError{reason: "the uploaded file is corrupt or is not a supported video"}
+	}
+	if !info.HasVideo {
+		return "", false, &rejectedError{reason: "the uploaded file has no video stream"}
+	}
+	if info.Duration <= 0 {
+		return "", false, &rejectedError{reason: "the uploaded video is empty"}
+	}
+	if info.HasAudio {
+		return originalPath, true, nil
+	}
+
+	slog.InfoContext(ctx, "Video has no audio track, inserting silence")
+	withSilence := filepath.Join(workDir, baseName+"_silence.mkv")
+	cmd := exec.CommandContext(ctx, "ffmpeg", pipeline.AddSilenceArgs(originalPath, withSilence)...)
+	if err := runFFmpegCommand(ctx, "add_silence", cmd); err != nil {
+		return "", false, fmt.Errorf("failed to insert silent audio track: %w", err)
+	}
+	return withSilence, false, nil
+}
+
+// runNormalizeAudio mide el audio con una primera pasada de loudnorm y aplica la
+// segunda pasada con esos valores. Si el clip no traía audio (se insertó silencio)
+// no hay nada que medir y se normaliza en una sola pasada.
+// La medición cuenta como la primera mitad del avance del paso.
+func runNormalizeAudio(ctx context.Context, step pipeline.Step, job pipeline.Job, hasAudio bool, expected time.Duration, onProgress func(float64)) (*pipeline.Loudness, error) {
+	var measured *pipeline.Loudness
+	applyProgress := onProgress
+
+	if hasAudio {
+		stderr, err := runFFmpegProgress(ctx, "loudness_scan", pipeline.LoudnessScanArgs(step, job.Input), expected, scaleProgress(onProgress, 0, 0.5))
+		if err != nil {
+			return nil, fmt.Errorf("loudness measurement failed: %w", err)
+		}
+		measured, err = pipeline.ParseLoudness(stderr)
+		if err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "Measured loudness",
+			"integrated_lufs", measured.Integrated, "true_peak_dbtp", measured.TruePeak, "range_lu", measured.Range)
+
+		if measured.IsSilent() {
+			if step.RejectSilent {
+				return nil, &rejectedError{reason: "the audio track is completely silent"}
+			}
+			// loudnorm no puede usar una medición de silencio como referencia
+			job.Loudness = nil
+		} else {
+			job.Loudness = measured
+		}
+		applyProgress = scaleProgress(onProgress, 0.5, 1)
+	}
+
+	args, err := pipeline.BuildArgs(step, job)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := runFFmpegProgress(ctx, step.Type, args, expected, applyProgress); err != nil {
+		return nil, err
+	}
+	return measured, nil
+}
+
+// scaleProgress adapta un callback de avance al tramo [from, to] del paso
+func scaleProgress(onProgress func(float64), from, to float64) func(float64) {
+	if onProgress == nil {
+		return nil
+	}
+	return func(fraction float64) {
+		onProgress(from + fraction*(to-from))
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+type logAttrsKey struct{}
+
+// setupLogging configura logs JSON en stdout. slog.SetDefault también redirige el
+// paquete log, así que los log.Printf existentes salen en el mismo formato.
+func setupLogging() {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+}
+
+// withLogAttrs devuelve un contexto cuyos logs incluyen además los atributos dados
+func withLogAttrs(ctx context.Context, attrs ...any) context.Context {
+	existing, _ := ctx.Value(logAttrsKey{}).([]any)
+	merged := append(append([]any{}, existing...), attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// contextHandler agrega a cada registro los atributos guardados en el contexto
+// (task_id, video_id, attempt), para poder seguir una tarea en los logs.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(logAttrsKey{}).([]any); ok {
+		record.Add(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
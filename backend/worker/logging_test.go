@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogging(t *testing.T) {
+	t.Run("ContextHandler_AddsTaskAttributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(&contextHandler{Handler: slog.NewJSONHandler(&buf, nil)})
+		ctx := withLogAttrs(context.Background(), "task_id", "msg-1", "video_id", uint(42), "attempt", 2)
+
+		logger.InfoContext(ctx, "Processing task")
+
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "msg-1", record["task_id"])
+		assert.Equal(t, float64(42), record["video_id"])
+		assert.Equal(t, float64(2), record["attempt"])
+	})
+
+	t.Run("WithLogAttrs_DoesNotModifyParent", func(t *testing.T) {
+		parent := withLogAttrs(context.Background(), "task_id", "msg-1")
+		withLogAttrs(parent, "attempt", 3)
+
+		attrs, _ := parent.Value(logAttrsKey{}).([]any)
+		assert.Equal(t, []any{"task_id", "msg-1"}, attrs)
+	})
+}
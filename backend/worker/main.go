@@ -1,79 +1,70 @@
 package main
 
 import (
+	"anb-app/src/config"
+	"anb-app/src/health"
+	"anb-app/src/outbox"
+	"anb-app/src/pipeline"
 	"anb-app/src/queue"
+	"anb-app/src/task"
+	"anb-app/src/user"
 	"anb-app/src/video" // Importamos el paquete de video
+	"anb-app/src/webhook"
 	"bytes"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"mime"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 type TaskProcessor struct {
-	db         *gorm.DB
-	videoRepo  video.VideoRepository
-	s3Client   *s3.Client
-	bucketName string
+	db           *gorm.DB
+	videoRepo    video.VideoRepository
+	taskRepo     task.TaskRepository
+	s3Client     *s3.Client
+	bucketName   string
+	pipelineSpec *pipeline.Spec
+	workRoot     string
+	diskReserve  uint64
 }
 
-func NewTaskProcessor(db *gorm.DB, videoRepo video.VideoRepository, s3Client *s3.Client, bucketName string) *TaskProcessor {
+func NewTaskProcessor(db *gorm.DB, videoRepo video.VideoRepository, taskRepo task.TaskRepository, s3Client *s3.Client, bucketName string, pipelineSpec *pipeline.Spec, workRoot string, diskReserve uint64) *TaskProcessor {
 	return &TaskProcessor{
-		db:         db,
-		videoRepo:  videoRepo,
-		s3Client:   s3Client,
-		bucketName: bucketName,
+		db:           db,
+		videoRepo:    videoRepo,
+		taskRepo:     taskRepo,
+		s3Client:     s3Client,
+		bucketName:   bucketName,
+		pipelineSpec: pipelineSpec,
+		workRoot:     workRoot,
+		diskReserve:  diskReserve,
 	}
 }
 
-// Función de conexión directa a PostgreSQL usando variables de entorno
-func connectPostgreSQL() *gorm.DB {
-	// Leer variables de entorno
-	dbHost := os.Getenv("DB_HOST")
-	if dbHost == "" {
-		dbHost = "localhost"
-	}
-	dbPort := os.Getenv("DB_PORT")
-	if dbPort == "" {
-		dbPort = "5432"
-	}
-	dbUser := os.Getenv("DB_USER")
-	if dbUser == "" {
-		dbUser = "postgres"
-	}
-	dbPassword := os.Getenv("DB_PASSWORD")
-	if dbPassword == "" {
-		dbPassword = "postgres"
-	}
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		dbName = "anb_db"
-	}
-	dbSSLMode := os.Getenv("DB_SSLMODE")
-	if dbSSLMode == "" {
-		dbSSLMode = "disable"
-	}
-
-	// Crear DSN de conexión
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
-		dbHost, dbUser, dbPassword, dbName, dbPort, dbSSLMode)
-
-	log.Printf("Conectando a RDS: %s:%s/%s con SSL", dbHost, dbPort, dbName)
+// connectPostgreSQL abre la conexión con el pool del worker
+func connectPostgreSQL(cfg config.Database) *gorm.DB {
+	log.Printf("Conectando a RDS: %s:%d/%s (sslmode=%s)", cfg.Host, cfg.Port, cfg.Name, cfg.SSLMode)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(postgres.Open(cfg.DSN()+" TimeZone=UTC"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 
@@ -101,8 +92,8 @@ func connectPostgreSQL() *gorm.DB {
 }
 
 // Helper to download file from S3 to local path
-func (p *TaskProcessor) downloadFromS3(s3Key, localPath string) error {
-	result, err := p.s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+func (p *TaskProcessor) downloadFromS3(ctx context.Context, s3Key, localPath string) error {
+	result, err := p.s3Client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(p.bucketName),
 		Key:    aws.String(s3Key),
 	})
@@ -117,7 +108,8 @@ func (p *TaskProcessor) downloadFromS3(s3Key, localPath string) error {
 	}
 	defer file.Close()
 
-	_, err = io.Copy(file, result.Body)
+	written, err := io.Copy(file, result.Body)
+	bytesDownloaded.Add(float64(written))
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -126,185 +118,351 @@ func (p *TaskProcessor) downloadFromS3(s3Key, localPath string) error {
 }
 
 // Helper to upload file from local path to S3
-func (p *TaskProcessor) uploadToS3(localPath, s3Key string) error {
+func (p *TaskProcessor) uploadToS3(ctx context.Context, localPath, s3Key string) error {
 	file, err := os.Open(localPath)
 	if err != nil {
 		return fmt.Errorf("failed to open local file: %w", err)
 	}
 	defer file.Close()
 
-	_, err = p.s3Client.PutObject(context.TODO(), &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(p.bucketName),
 		Key:    aws.String(s3Key),
 		Body:   file,
-	})
+	}
+	// Content-Type explícito para que los navegadores muestren miniaturas y previews
+	if contentType := mime.TypeByExtension(filepath.Ext(s3Key)); contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	_, err = p.s3Client.PutObject(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
+	if info, err := file.Stat(); err == nil {
+		bytesUploaded.Add(float64(info.Size()))
+	}
 
 	return nil
 }
 
-func (p *TaskProcessor) processVideo(videoRecord *video.Video) error {
-	log.Printf("Processing video '%s'...", videoRecord.Title)
+// overlayMetadata reúne los datos del jugador y del video para los textos superpuestos
+func (p *TaskProcessor) overlayMetadata(ctx context.Context, videoRecord *video.Video) pipeline.Metadata {
+	meta := pipeline.Metadata{Title: videoRecord.Title}
+
+	var owner user.User
+	if err := p.db.WithContext(ctx).First(&owner, videoRecord.UserID).Error; err != nil {
+		slog.WarnContext(ctx, "Could not load video owner for overlays", "error", err)
+		return meta
+	}
+	meta.PlayerName = strings.TrimSpace(owner.FirstName + " " + owner.LastName)
+	meta.City = owner.City
+	return meta
+}
+
+func (p *TaskProcessor) processVideo(ctx context.Context, videoRecord *video.Video, tracker *jobTracker) error {
+	slog.InfoContext(ctx, "Processing video", "title", videoRecord.Title)
 
 	// video.OriginalURL is now S3 key (e.g., "originals/123.mp4")
 	s3Key := videoRecord.OriginalURL
 	baseName := strings.TrimSuffix(filepath.Base(s3Key), filepath.Ext(s3Key))
 
-	// Create temp directory
-	tempDir := "/tmp/video-processing"
-	os.MkdirAll(tempDir, os.ModePerm)
+	// Pre-flight: make sure the original and its intermediates fit on disk
+	if err := p.ensureDiskSpace(ctx, p.workRoot, s3Key); err != nil {
+		return err
+	}
+
+	// Create a temp directory exclusive to this task
+	tempDir, err := createTaskWorkDir(p.workRoot, videoRecord.ID)
+	if err != nil {
+		return err
+	}
 	defer os.RemoveAll(tempDir) // Clean up at the end
 
 	// Step 1: Download original video from S3
-	log.Println("Step 1: Downloading original video from S3...")
+	slog.InfoContext(ctx, "Step 1: Downloading original video from S3", "s3_key", s3Key)
 	tempOriginalPath := filepath.Join(tempDir, baseName+"_original.mp4")
-	if err := p.downloadFromS3(s3Key, tempOriginalPath); err != nil {
+	if err := p.downloadFromS3(ctx, s3Key, tempOriginalPath); err != nil {
 		return fmt.Errorf("failed to download original video: %w", err)
 	}
 
-	// Step 2: Process with FFmpeg
-	log.Println("Step 2: Trimming and scaling user video...")
-	introVideoPath := "/app/intro/anb.mp4"
-	tempProcessedPath := filepath.Join(tempDir, baseName+"_processed.mp4")
-	concatListPath := filepath.Join(tempDir, baseName+"_list.txt")
-	finalOutputPath := filepath.Join(tempDir, baseName+"_final.mp4")
-
-	cmd1 := exec.Command("ffmpeg", "-y", "-i", tempOriginalPath, "-t", "30", "-vf", "scale=1280:720,setdar=16/9", "-preset", "fast", tempProcessedPath)
-	if err := runFFmpegCommand(cmd1); err != nil {
-		return fmt.Errorf("ffmpeg trim/scale failed: %w", err)
-	}
-
-	// Step 3: Create concatenation list
-	log.Println("Step 3: Creating concatenation list...")
-	concatContent := fmt.Sprintf("file '%s'\nfile '%s'\nfile '%s'", introVideoPath, tempProcessedPath, introVideoPath)
-	if err := os.WriteFile(concatListPath, []byte(concatContent), 0644); err != nil {
-		return fmt.Errorf("failed to create concat list: %w", err)
+	// Step 2: Validate the upload and make sure it has an audio track
+	slog.InfoContext(ctx, "Step 2: Probing original video")
+	tracker.setStep(ctx, "probe", progressProbe)
+	inputPath, hasAudio, err := prepareInput(ctx, tempOriginalPath, tempDir, baseName)
+	if err != nil {
+		return err
 	}
 
-	// Step 4: Concatenate videos
-	log.Println("Step 4: Concatenating videos...")
-	cmd2 := exec.Command("ffmpeg", "-y", "-f", "concat", "-safe", "0", "-i", concatListPath, "-c", "copy", finalOutputPath)
-	if err := runFFmpegCommand(cmd2); err != nil {
-		return fmt.Errorf("ffmpeg concat failed: %w", err)
+	// Step 3: Run the processing pipeline
+	slog.InfoContext(ctx, "Step 3: Running pipeline", "pipeline", p.pipelineSpec.Ref())
+	result, err := runPipeline(ctx, p.pipelineSpec, inputPath, tempDir, baseName, p.overlayMetadata(ctx, videoRecord), hasAudio, tracker.pipelineProgress(ctx))
+	if err != nil {
+		return err
 	}
 
-	// Step 5: Upload processed video to S3
-	log.Println("Step 5: Uploading processed video to S3...")
+	// Step 4: Upload processed video to S3
+	slog.InfoContext(ctx, "Step 4: Uploading processed video to S3")
+	tracker.setStep(ctx, "upload", progressUpload)
 	processedS3Key := fmt.Sprintf("processed/%s.mp4", baseName)
-	if err := p.uploadToS3(finalOutputPath, processedS3Key); err != nil {
+	if err := p.uploadToS3(ctx, result.FinalPath, processedS3Key); err != nil {
 		return fmt.Errorf("failed to upload processed video: %w", err)
 	}
 
-	// Step 6: Update database with S3 key
-	log.Println("Step 6: Updating database...")
-	videoRecord.Status = "processed"
+	// Step 5: Generate thumbnail and preview from the user clip (without intro)
+	slog.InfoContext(ctx, "Step 5: Generating thumbnail and preview")
+	tracker.setStep(ctx, "thumbnails", progressThumbnails)
+	assets := p.generateMediaAssets(ctx, result.ClipPath, tempDir, baseName)
+
+	// Step 6: Update database with S3 keys
+	slog.InfoContext(ctx, "Step 6: Updating database")
+	// Se trabaja sobre una copia: si no se puede guardar, el manejo del error parte
+	// del video tal como estaba
+	processed := *videoRecord
+	processed.Status = "processed"
 	now := time.Now()
-	videoRecord.ProcessedAt = &now
-	videoRecord.ProcessedURL = processedS3Key // Store S3 key
-	if err := p.videoRepo.Update(videoRecord); err != nil {
+	processed.ProcessedAt = &now
+	processed.ProcessedURL = processedS3Key // Store S3 key
+	processed.ThumbnailURL = assets.ThumbnailKey
+	processed.PreviewURL = assets.PreviewKey
+	processed.PipelineVersion = p.pipelineSpec.Ref()
+	processed.FailureReason = ""
+	if result.Loudness != nil && !result.Loudness.IsSilent() {
+		loudness := result.Loudness.Integrated
+		processed.LoudnessLUFS = &loudness
+	}
+	processedEvent, err := video.NewWebhookEvent(webhook.EventVideoProcessed, &processed)
+	if err != nil {
+		return err
+	}
+	if err := p.videoRepo.UpdateProcessing(&processed, processedEvent); err != nil {
 		return fmt.Errorf("failed to update video record %d: %w", videoRecord.ID, err)
 	}
+	*videoRecord = processed
 
-	log.Printf("Successfully processed video ID: %d", videoRecord.ID)
+	slog.InfoContext(ctx, "Successfully processed video")
 	return nil
 }
 
 func (p *TaskProcessor) HandleProcessVideoTask(ctx context.Context, task *queue.Task) error {
-	log.Printf("--- WORKER: Processing task for video ID: %d ---", task.Payload.VideoID)
+	// Todos los logs de la tarea llevan su ID, el del video y el número de intento
+	ctx = withLogAttrs(ctx, "task_id", trackingID(task), "message_id", task.ID, "video_id", task.Payload.VideoID, "attempt", task.Attempt)
+	slog.InfoContext(ctx, "Processing task")
+
+	jobsInFlight.Inc()
+	start := time.Now()
+	defer func() {
+		jobsInFlight.Dec()
+		jobDuration.Observe(time.Since(start).Seconds())
+	}()
 
 	videoRecord, err := p.videoRepo.FindByID(task.Payload.VideoID)
-	if err != nil || videoRecord == nil {
-		log.Printf("ERROR: Video %d not found", task.Payload.VideoID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Video not found", "error", err)
+		tasksFailed.WithLabelValues("error").Inc()
 		return fmt.Errorf("video not found: %w", err)
 	}
+	if videoRecord == nil {
+		// El dueño lo retiró: no hay nada que procesar y reintentar no cambia eso
+		slog.WarnContext(ctx, "Video was deleted, skipping task")
+		tasksFailed.WithLabelValues("deleted").Inc()
+		return nil
+	}
 
-	processingErr := p.processVideo(videoRecord)
+	tracker := p.startJob(ctx, task, videoRecord)
+	processingErr := p.processVideo(ctx, videoRecord, tracker)
 
 	if processingErr != nil {
-		log.Printf("ERROR processing video ID %d: %v", task.Payload.VideoID, processingErr)
+		slog.ErrorContext(ctx, "Video processing failed", "error", processingErr)
 
-		// Marcar como fallido en la base de datos si ya se reintentó muchas veces
-		// SQS manejará los reintentos automáticamente
-		videoRecord.Status = "failed"
-		if updateErr := p.videoRepo.Update(videoRecord); updateErr != nil {
+		// El video se retiró mientras se procesaba: se descarta la tarea
+		if errors.Is(processingErr, gorm.ErrRecordNotFound) {
+			tasksFailed.WithLabelValues("deleted").Inc()
+			tracker.reject(ctx, "video was deleted")
+			return nil
+		}
+
+		// Sin espacio en disco el video no tiene la culpa: se devuelve a la cola tal cual
+		if errors.Is(processingErr, errInsufficientDisk) {
+			tasksFailed.WithLabelValues("insufficient_disk").Inc()
+			tracker.failAttempt(ctx, task, processingErr)
+			return processingErr
+		}
+
+		// Un clip rechazado (corrupto, silencioso) no mejora reintentando: se guarda
+		// el motivo y la tarea se da por terminada para que SQS no la vuelva a entregar
+		var rejected *rejectedError
+		isRejected := errors.As(processingErr, &rejected)
+		if isRejected {
+			videoRecord.FailureReason = rejected.reason
+		}
+		final := isRejected || isLastAttempt(task)
+
+		// Mientras SQS siga reintentando, un video ya procesado conserva su estado
+		videoRecord.Status = failedStatus(videoRecord, final)
+
+		// video.failed solo se emite cuando el video no se va a reintentar más
+		var messages []outbox.Message
+		if final {
+			failedEvent, err := video.NewWebhookEvent(webhook.EventVideoFailed, videoRecord)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, failedEvent)
+		}
+		if updateErr := p.videoRepo.UpdateProcessing(videoRecord, messages...); updateErr != nil {
 			return fmt.Errorf("task failed and could not update status: %w (original error: %v)", updateErr, processingErr)
 		}
 
+		if isRejected {
+			tasksFailed.WithLabelValues("rejected").Inc()
+			tracker.reject(ctx, rejected.reason)
+			return nil
+		}
+		tasksFailed.WithLabelValues("error").Inc()
+		tracker.failAttempt(ctx, task, processingErr)
 		return processingErr
 	}
 
+	tasksSucceeded.Inc()
+	tracker.succeed(ctx)
 	return nil
 }
 
-func runFFmpegCommand(cmd *exec.Cmd) error {
+func runFFmpegCommand(ctx context.Context, step string, cmd *exec.Cmd) error {
+	_, err := runFFmpegOutput(ctx, step, cmd)
+	return err
+}
+
+// runFFmpegOutput ejecuta el comando y devuelve su stderr, donde FFmpeg escribe
+// tanto los errores como las mediciones de filtros como loudnorm. La duración se
+// registra en el histograma del paso indicado.
+func runFFmpegOutput(ctx context.Context, step string, cmd *exec.Cmd) (string, error) {
 	var out bytes.Buffer
 	var stderr bytes.Buffer
-	cmd.Stdout = &out
+	if cmd.Stdout == nil {
+		cmd.Stdout = &out
+	}
 	cmd.Stderr = &stderr
+	start := time.Now()
 	err := cmd.Run()
+	observeStep(step, time.Since(start).Seconds(), err)
 	if err != nil {
-		log.Printf("FFmpeg command failed: %s\n", err)
-		log.Printf("FFmpeg stderr: %s\n", stderr.String())
-		return err
+		slog.ErrorContext(ctx, "FFmpeg command failed", "step", step, "error", err, "stderr", stderr.String())
+		return stderr.String(), err
 	}
-	return nil
+	return stderr.String(), nil
+}
+
+// runFFmpegProgress ejecuta ffmpeg con -progress e informa la fracción completada
+// respecto a la duración esperada de la salida. Sin onProgress equivale a runFFmpegOutput.
+func runFFmpegProgress(ctx context.Context, step string, args []string, expected time.Duration, onProgress func(float64)) (string, error) {
+	if onProgress == nil {
+		return runFFmpegOutput(ctx, step, exec.CommandContext(ctx, "ffmpeg", args...))
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", append(pipeline.ProgressArgs(), args...)...)
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+
+	scanned := make(chan struct{})
+	go func() {
+		defer close(scanned)
+		pipeline.ScanProgress(reader, func(progress pipeline.Progress) {
+			onProgress(progress.Fraction(expected))
+		})
+		// Vaciar el resto para que ffmpeg no se bloquee si el escáner terminó antes
+		io.Copy(io.Discard, reader)
+	}()
+
+	stderr, err := runFFmpegOutput(ctx, step, cmd)
+	writer.Close()
+	<-scanned
+	return stderr, err
 }
 
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "archivo YAML opcional con la configuración")
+	printConfig := flag.Bool("print-config", false, "mostrar la configuración efectiva (sin secretos) y salir")
+	flag.Parse()
+
+	// Variables de entorno > .env > YAML > valores por defecto
+	var cfg config.Worker
+	sources, err := config.Load(&cfg, *configFile)
+	if *printConfig {
+		config.Print(os.Stdout, &cfg, sources)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	setupLogging()
 	log.Println("Conectando worker a PostgreSQL...")
 
 	// Conexión a PostgreSQL
-	db := connectPostgreSQL()
+	db := connectPostgreSQL(cfg.Database)
 	log.Println("Worker conectado a PostgreSQL exitosamente")
 
 	// Initialize S3 client
-	s3Bucket := os.Getenv("S3_BUCKET_NAME")
-	if s3Bucket == "" {
-		log.Fatal("S3_BUCKET_NAME environment variable is required")
-	}
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(awsRegion))
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(cfg.AWS.Region))
 	if err != nil {
 		log.Fatalf("Failed to load AWS config: %v", err)
 	}
-	s3Client := s3.NewFromConfig(cfg)
-	log.Printf("S3 Client initialized: bucket=%s, region=%s", s3Bucket, awsRegion)
+	s3Client := s3.NewFromConfig(awsCfg)
+	log.Printf("S3 Client initialized: bucket=%s, region=%s", cfg.AWS.S3Bucket, cfg.AWS.Region)
 
 	// Inicializar SQS Consumer
-	sqsQueueURL := os.Getenv("SQS_QUEUE_URL")
-	if sqsQueueURL == "" {
-		log.Fatal("SQS_QUEUE_URL environment variable is required")
-	}
-
-	sqsConsumer, err := queue.NewSQSConsumer(context.Background(), sqsQueueURL, awsRegion)
+	sqsConsumer, err := queue.NewSQSConsumer(context.Background(), cfg.AWS.SQSQueueURL, cfg.AWS.Region)
 	if err != nil {
 		log.Fatalf("Failed to initialize SQS consumer: %v", err)
 	}
 	defer sqsConsumer.Close()
 
-	log.Printf("SQS Consumer initialized: queue=%s", sqsQueueURL)
+	log.Printf("SQS Consumer initialized: queue=%s", cfg.AWS.SQSQueueURL)
+
+	pipelineSpec, err := loadPipelineSpec(cfg.PipelineSpecPath)
+	if err != nil {
+		log.Fatalf("Failed to load processing pipeline: %v", err)
+	}
+	if err := checkPipelineAssets(pipelineSpec); err != nil {
+		log.Fatalf("Processing pipeline %s is not runnable: %v", pipelineSpec.Ref(), err)
+	}
+	log.Printf("Processing pipeline loaded: %s (%d steps)", pipelineSpec.Ref(), len(pipelineSpec.Steps))
 
 	videoRepo := video.NewVideoRepository(db)
-	processor := NewTaskProcessor(db, videoRepo, s3Client, s3Bucket)
+	// Limpiar directorios de trabajo que dejó una ejecución anterior interrumpida
+	root := cfg.TempDir
+	if err := prepareWorkRoot(root); err != nil {
+		log.Fatalf("Failed to prepare work directory: %v", err)
+	}
+
+	diskReserve := uint64(cfg.DiskReserveMB) << 20
+	processor := NewTaskProcessor(db, videoRepo, task.NewTaskRepository(db), s3Client, cfg.AWS.S3Bucket, pipelineSpec, root, diskReserve)
 
 	log.Println(" ANB Worker is running and connected to PostgreSQL...")
 	log.Println(" Waiting for video processing tasks from SQS...")
 
 	// Iniciar servidor HTTP para health checks en un goroutine
-	go startHealthCheckServer()
+	healthChecker := health.NewChecker(health.DefaultTimeout, health.DefaultCacheTTL,
+		health.Postgres(db),
+		health.Dependency{Name: "s3", Check: processor.pingBucket},
+		health.Dependency{Name: "sqs", Check: sqsConsumer.Ping},
+	)
+	go startHealthCheckServer(healthChecker, cfg.HealthCheckPort)
 
 	// Loop infinito para recibir y procesar mensajes de SQS
 	for {
 		ctx := context.Background()
 
 		// Recibir tarea de SQS (long polling de 20 segundos)
+		receiveStart := time.Now()
 		task, err := sqsConsumer.ReceiveTask(ctx)
+		queueReceiveDuration.Observe(time.Since(receiveStart).Seconds())
 		if err != nil {
 			log.Printf("Error receiving task from SQS: %v", err)
 			time.Sleep(5 * time.Second) // Esperar antes de reintentar
@@ -316,6 +474,14 @@ func main() {
 			continue
 		}
 
+		tasksReceived.Inc()
+		if task.Attempt > 1 {
+			tasksRetried.Inc()
+		}
+		if !task.EnqueuedAt.IsZero() {
+			queueWait.Observe(time.Since(task.EnqueuedAt).Seconds())
+		}
+
 		// Procesar la tarea
 		log.Printf("Processing task: %s for video ID: %d", task.ID, task.Payload.VideoID)
 
@@ -327,6 +493,10 @@ func main() {
 			if err := sqsConsumer.FailTask(ctx, task); err != nil {
 				log.Printf("Error marking task as failed: %v", err)
 			}
+			// Sin disco no tiene sentido pedir otra tarea enseguida; otra instancia puede tomarla
+			if errors.Is(processErr, errInsufficientDisk) {
+				time.Sleep(insufficientDiskBackoff)
+			}
 		} else {
 			log.Printf("Task %s completed successfully", task.ID)
 			// Eliminar el mensaje de SQS
@@ -337,20 +507,18 @@ func main() {
 	}
 }
 
-// startHealthCheckServer inicia un servidor HTTP simple para health checks del ALB
-func startHealthCheckServer() {
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-
-	port := os.Getenv("HEALTH_CHECK_PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	log.Printf("Health check server listening on port %s", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+// startHealthCheckServer inicia el servidor HTTP de health checks del ALB
+// (/livez, /readyz y /health como alias de readiness) y expone las métricas
+// de Prometheus en /metrics
+func startHealthCheckServer(checker *health.Checker, port int) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", checker.LiveHandler())
+	mux.HandleFunc("/readyz", checker.ReadyHandler())
+	mux.HandleFunc("/health", checker.ReadyHandler())
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Health check server listening on port %d", port)
+	if err := http.ListenAndServe(":"+strconv.Itoa(port), mux); err != nil {
 		log.Fatalf("Failed to start health check server: %v", err)
 	}
 }
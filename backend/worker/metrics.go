@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Métricas del worker expuestas en /metrics del servidor de health checks
+var (
+	tasksReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "anb_worker_tasks_received_total",
+		Help: "Tareas de procesamiento recibidas de la cola.",
+	})
+	tasksRetried = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "anb_worker_tasks_retried_total",
+		Help: "Tareas recibidas de nuevo tras un intento fallido.",
+	})
+	tasksSucceeded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "anb_worker_tasks_succeeded_total",
+		Help: "Tareas procesadas correctamente.",
+	})
+	tasksFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "anb_worker_tasks_failed_total",
+		Help: "Tareas fallidas, por motivo (error, rejected, insufficient_disk, deleted).",
+	}, []string{"reason"})
+
+	jobsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "anb_worker_jobs_in_flight",
+		Help: "Tareas que se están procesando en este momento.",
+	})
+
+	stepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "anb_worker_ffmpeg_step_duration_seconds",
+		Help:    "Duración de cada paso de FFmpeg/ffprobe.",
+		Buckets: []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"step", "outcome"})
+	jobDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "anb_worker_job_duration_seconds",
+		Help:    "Duración total del procesamiento de un video.",
+		Buckets: []float64{5, 10, 20, 30, 60, 90, 120, 180, 300, 600},
+	})
+
+	queueReceiveDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "anb_worker_queue_receive_duration_seconds",
+		Help:    "Duración de cada llamada de recepción (long polling) a la cola.",
+		Buckets: []float64{0.05, 0.1, 0.5, 1, 5, 10, 20, 25},
+	})
+	queueWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "anb_worker_queue_wait_seconds",
+		Help:    "Tiempo entre que la tarea se encoló y el worker la recibió.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600},
+	})
+
+	bytesDownloaded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "anb_worker_s3_downloaded_bytes_total",
+		Help: "Bytes descargados de S3.",
+	})
+	bytesUploaded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "anb_worker_s3_uploaded_bytes_total",
+		Help: "Bytes subidos a S3.",
+	})
+)
+
+// observeStep registra la duración de un paso con su resultado
+func observeStep(step string, seconds float64, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	stepDuration.WithLabelValues(step, outcome).Observe(seconds)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"anb-app/src/pipeline"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pipelineResult contiene los archivos producidos al ejecutar un pipeline
+type pipelineResult struct {
+	// FinalPath es la salida del último paso (lo que se publica)
+	FinalPath string
+	// ClipPath es el clip del usuario antes de añadir intro y cierre
+	ClipPath string
+	// Loudness es la medición del audio antes de normalizarlo (nil si no se midió)
+	Loudness *pipeline.Loudness
+}
+
+// loadPipelineSpec carga el pipeline indicado en PIPELINE_SPEC_PATH o el embebido por defecto
+func loadPipelineSpec(path string) (*pipeline.Spec, error) {
+	if path != "" {
+		return pipeline.Load(path)
+	}
+	return pipeline.Default()
+}
+
+// checkPipelineAssets verifica al arrancar que existan intro, logos y fuentes
+func checkPipelineAssets(spec *pipeline.Spec) error {
+	for _, asset := range spec.Assets() {
+		if _, err := os.Stat(asset); err != nil {
+			return fmt.Errorf("pipeline asset %s is not available: %w", asset, err)
+		}
+	}
+	return nil
+}
+
+// runPipeline ejecuta los pasos en orden sobre inputPath. Cada paso escribe un archivo
+// nuevo en workDir y se reintenta de forma independiente según la especificación.
+// hasAudio indica si el clip original traía audio propio (y no silencio insertado).
+// report recibe el paso en curso y el avance del pipeline completo entre 0 y 1.
+func runPipeline(ctx context.Context, spec *pipeline.Spec, inputPath, workDir, baseName string, meta pipeline.Metadata, hasAudio bool, report func(step string, fraction float64)) (*pipelineResult, error) {
+	current := inputPath
+	result := &pipelineResult{ClipPath: inputPath}
+
+	for i, step := range spec.Steps {
+		// Los intermedios van en Matroska, que admite casi cualquier codec con copia de streams
+		ext := ".mkv"
+		if i == len(spec.Steps)-1 {
+			ext = ".mp4"
+		}
+		output := filepath.Join(workDir, fmt.Sprintf("%s_%02d_%s%s", baseName, i+1, step.Type, ext))
+
+		slog.InfoContext(ctx, "Running pipeline step", "pipeline", spec.Ref(), "step", i+1, "steps", len(spec.Steps), "label", step.Label())
+		job := pipeline.Job{Input: current, Output: output}
+		for t, text := range step.Texts {
+			textFile := fmt.Sprintf("%s.text%d.txt", output, t+1)
+			if err := os.WriteFile(textFile, []byte(pipeline.RenderText(text.Text, meta)), 0644); err != nil {
+				return nil, fmt.Errorf("failed to write overlay text: %w", err)
+			}
+			job.TextFiles = append(job.TextFiles, textFile)
+		}
+
+		stepIndex := float64(i)
+		stepCount := float64(len(spec.Steps))
+		onProgress := func(fraction float64) {
+			report(step.Label(), (stepIndex+fraction)/stepCount)
+		}
+		onProgress(0)
+		expected := expectedStepDuration(ctx, step, current)
+
+		err := withRetry(ctx, spec, step, func() error {
+			if step.Type == pipeline.StepNormalizeAudio {
+				loudness, err := runNormalizeAudio(ctx, step, job, hasAudio, expected, onProgress)
+				result.Loudness = loudness
+				return err
+			}
+			return runStep(ctx, step, job, expected, onProgress)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("pipeline step %d (%s) failed: %w", i+1, step.Label(), err)
+		}
+
+		current = output
+		if step.Type != pipeline.StepConcat {
+			result.ClipPath = output
+		}
+	}
+
+	result.FinalPath = current
+	return result, nil
+}
+
+func runStep(ctx context.Context, step pipeline.Step, job pipeline.Job, expected time.Duration, onProgress func(float64)) error {
+	args, err := pipeline.BuildArgs(step, job)
+	if err != nil {
+		return err
+	}
+	_, err = runFFmpegProgress(ctx, step.Type, args, expected, onProgress)
+	return err
+}
+
+// expectedStepDuration estima cuánto durará la salida del paso para calcular el
+// porcentaje. Si no se puede medir devuelve 0 y solo se informa al terminar el paso.
+func expectedStepDuration(ctx context.Context, step pipeline.Step, input string) time.Duration {
+	duration := func(path string) float64 {
+		if path == "" {
+			return 0
+		}
+		info, err := probeMedia(ctx, path)
+		if err != nil {
+			return 0
+		}
+		return info.Duration
+	}
+	return pipeline.ExpectedDuration(step, duration(input), duration(step.Intro), duration(step.Outro))
+}
+
+// withRetry ejecuta un paso hasta agotar sus intentos. Los rechazos no se reintentan.
+func withRetry(ctx context.Context, spec *pipeline.Spec, step pipeline.Step, run func() error) error {
+	maxAttempts := spec.MaxAttempts(step)
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil {
+			return nil
+		}
+		var rejected *rejectedError
+		if errors.As(err, &rejected) {
+			return err
+		}
+		if attempt >= maxAttempts {
+			return fmt.Errorf("after %d attempts: %w", attempt, err)
+		}
+
+		backoff := spec.RetryBackoff(attempt)
+		slog.WarnContext(ctx, "Pipeline step failed, retrying",
+			"label", step.Label(), "step_attempt", attempt, "max_attempts", maxAttempts, "backoff", backoff.String(), "error", err)
+		// Si se cancela la tarea (p. ej. al apagar el worker) no se espera el backoff
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"anb-app/src/pipeline"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	spec := &pipeline.Spec{Retries: 2, RetryBackoffSeconds: 60}
+	step := pipeline.Step{Type: "scale"}
+
+	t.Run("RejectedError_DoesNotRetry", func(t *testing.T) {
+		calls := 0
+		err := withRetry(ctx, spec, step, func() error {
+			calls++
+			return &rejectedError{reason: "corrupt"}
+		})
+
+		var rejected *rejectedError
+		assert.ErrorAs(t, err, &rejected)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("CancelledContext_StopsWaitingForBackoff", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		calls := 0
+		start := time.Now()
+		err := withRetry(cancelled, spec, step, func() error {
+			calls++
+			cancel()
+			return errors.New("ffmpeg crashed")
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"anb-app/src/queue"
+	"anb-app/src/task"
+	"anb-app/src/video"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Tramos del porcentaje total que corresponden a cada fase del procesamiento
+const (
+	progressDownload   = 0
+	progressProbe      = 5
+	progressPipeline   = 10
+	progressUpload     = 85
+	progressThumbnails = 92
+	progressDone       = 100
+)
+
+// Intervalo mínimo entre escrituras de progreso dentro de un mismo paso
+const progressSaveInterval = time.Second
+
+// jobTracker publica el estado de la tarea en processing_jobs. Los errores al
+// guardar solo se registran: el seguimiento nunca hace fallar el procesamiento.
+type jobTracker struct {
+	repo      task.TaskRepository
+	job       *task.ProcessingJob
+	lastSaved time.Time
+}
+
+// startJob marca la tarea como en proceso, creando el registro si el mensaje se
+// encoló antes de existir processing_jobs.
+func (p *TaskProcessor) startJob(ctx context.Context, t *queue.Task, videoRecord *video.Video) *jobTracker {
+	tracker := &jobTracker{repo: p.taskRepo}
+
+	taskID := trackingID(t)
+	job, err := p.taskRepo.FindByTaskID(taskID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load processing job", "error", err)
+		return tracker
+	}
+	if job == nil {
+		job, err = p.taskRepo.Create(&task.ProcessingJob{
+			TaskID:  taskID,
+			VideoID: videoRecord.ID,
+			UserID:  videoRecord.UserID,
+			Status:  task.StatusQueued,
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "Could not create processing job", "error", err)
+			return tracker
+		}
+	}
+
+	now := time.Now()
+	job.Status = task.StatusProcessing
+	job.Attempts = t.Attempt
+	job.Error = ""
+	job.Step = "download"
+	job.Progress = progressDownload
+	job.FinishedAt = nil
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	tracker.job = job
+	tracker.save(ctx)
+	return tracker
+}
+
+// setStep actualiza el paso y el porcentaje. Dentro de un mismo paso las escrituras
+// se espacian para no saturar la base de datos con cada bloque de -progress.
+func (t *jobTracker) setStep(ctx context.Context, step string, progress float64) {
+	if t.job == nil {
+		return
+	}
+	progress = min(max(progress, t.job.Progress), progressDone)
+	sameStep := step == t.job.Step
+	t.job.Step = step
+	t.job.Progress = progress
+	if sameStep && time.Since(t.lastSaved) < progressSaveInterval {
+		return
+	}
+	t.save(ctx)
+}
+
+// succeed marca la tarea como completada
+func (t *jobTracker) succeed(ctx context.Context) {
+	t.finish(ctx, task.StatusSucceeded, "")
+}
+
+// reject marca la tarea como fallida sin reintentos (clip corrupto, silencioso...)
+func (t *jobTracker) reject(ctx context.Context, reason string) {
+	t.finish(ctx, task.StatusFailed, reason)
+}
+
+// failAttempt registra un intento fallido: la tarea queda en retrying salvo que la
+// cola ya no vaya a entregarla de nuevo
+func (t *jobTracker) failAttempt(ctx context.Context, qt *queue.Task, err error) {
+	status := task.StatusRetrying
+	if isLastAttempt(qt) {
+		status = task.StatusFailed
+	}
+	t.finish(ctx, status, err.Error())
+}
+
+// finish deja la tarea en su estado final (o en retrying si la cola la volverá a entregar)
+func (t *jobTracker) finish(ctx context.Context, status string, errMsg string) {
+	if t.job == nil {
+		return
+	}
+	t.job.Status = status
+	t.job.Error = errMsg
+	if status == task.StatusSucceeded {
+		t.job.Step = "done"
+		t.job.Progress = progressDone
+	}
+	if status == task.StatusSucceeded || status == task.StatusFailed {
+		now := time.Now()
+		t.job.FinishedAt = &now
+	}
+	t.save(ctx)
+}
+
+func (t *jobTracker) save(ctx context.Context) {
+	t.lastSaved = time.Now()
+	if err := t.repo.Update(t.job); err != nil {
+		slog.WarnContext(ctx, "Could not update processing job", "error", err)
+	}
+}
+
+// pipelineProgress traduce el avance del pipeline (0-1) al tramo que le corresponde
+func (t *jobTracker) pipelineProgress(ctx context.Context) func(step string, fraction float64) {
+	return func(step string, fraction float64) {
+		t.setStep(ctx, step, progressPipeline+fraction*(progressUpload-progressPipeline))
+	}
+}
+
+// trackingID devuelve el ID de processing_jobs de la tarea. Los mensajes encolados
+// antes de existir esa tabla no lo traen y se identifican por el ID del mensaje.
+func trackingID(t *queue.Task) string {
+	if t.Payload.TaskID != "" {
+		return t.Payload.TaskID
+	}
+	return t.ID
+}
+
+// isLastAttempt indica si la cola ya no volverá a entregar la tarea
+func isLastAttempt(t *queue.Task) bool {
+	return t.MaxRetry > 0 && t.Attempt > t.MaxRetry
+}
+
+// failedStatus decide el estado de un video cuyo procesamiento falló. Uno que ya tenía
+// una versión procesada (p. ej. reencolado con otro pipeline) sigue visible con ella
+// mientras queden reintentos; solo un rechazo o el último intento lo marcan fallido.
+func failedStatus(v *video.Video, final bool) string {
+	if v.ProcessedURL != "" && !final {
+		return v.Status
+	}
+	return "failed"
+}
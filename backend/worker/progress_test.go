@@ -0,0 +1,119 @@
+package main
+
+import (
+	"anb-app/src/queue"
+	"anb-app/src/task"
+	"anb-app/src/video"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTaskRepository guarda los trabajos en memoria y cuenta las escrituras
+type fakeTaskRepository struct {
+	jobs    map[string]*task.ProcessingJob
+	updates int
+}
+
+func newFakeTaskRepository() *fakeTaskRepository {
+	return &fakeTaskRepository{jobs: make(map[string]*task.ProcessingJob)}
+}
+
+func (r *fakeTaskRepository) Create(job *task.ProcessingJob) (*task.ProcessingJob, error) {
+	r.jobs[job.TaskID] = job
+	return job, nil
+}
+
+func (r *fakeTaskRepository) FindByTaskID(taskID string) (*task.ProcessingJob, error) {
+	return r.jobs[taskID], nil
+}
+
+func (r *fakeTaskRepository) Update(job *task.ProcessingJob) error {
+	r.updates++
+	r.jobs[job.TaskID] = job
+	return nil
+}
+
+func TestJobTracker(t *testing.T) {
+	ctx := context.Background()
+	videoRecord := &video.Video{ID: 4, UserID: 2}
+
+	t.Run("StartJob_MarksProcessing", func(t *testing.T) {
+		repo := newFakeTaskRepository()
+		repo.jobs["abc"] = &task.ProcessingJob{TaskID: "abc", VideoID: 4, UserID: 2, Status: task.StatusQueued}
+		processor := &TaskProcessor{taskRepo: repo}
+
+		processor.startJob(ctx, &queue.Task{ID: "msg-1", Attempt: 2, Payload: queue.TaskPayload{VideoID: 4, TaskID: "abc"}}, videoRecord)
+
+		job := repo.jobs["abc"]
+		assert.Equal(t, task.StatusProcessing, job.Status)
+		assert.Equal(t, 2, job.Attempts)
+		assert.Equal(t, "download", job.Step)
+		assert.NotNil(t, job.StartedAt)
+	})
+
+	t.Run("StartJob_CreatesJobForLegacyMessages", func(t *testing.T) {
+		repo := newFakeTaskRepository()
+		processor := &TaskProcessor{taskRepo: repo}
+
+		processor.startJob(ctx, &queue.Task{ID: "msg-1", Attempt: 1, Payload: queue.TaskPayload{VideoID: 4}}, videoRecord)
+
+		job := repo.jobs["msg-1"]
+		assert.NotNil(t, job)
+		assert.Equal(t, uint(2), job.UserID)
+		assert.Equal(t, task.StatusProcessing, job.Status)
+	})
+
+	t.Run("SetStep_ThrottlesWritesWithinAStep", func(t *testing.T) {
+		repo := newFakeTaskRepository()
+		processor := &TaskProcessor{taskRepo: repo}
+		tracker := processor.startJob(ctx, &queue.Task{ID: "msg-1", Attempt: 1}, videoRecord)
+		repo.updates = 0
+
+		report := tracker.pipelineProgress(ctx)
+		report("scale", 0.1)
+		report("scale", 0.2)
+		report("scale", 0.3)
+
+		assert.Equal(t, 1, repo.updates)
+		assert.Equal(t, "scale", repo.jobs["msg-1"].Step)
+		assert.InDelta(t, progressPipeline+0.3*(progressUpload-progressPipeline), repo.jobs["msg-1"].Progress, 0.001)
+	})
+
+	t.Run("FailAttempt_RetryingUntilLastAttempt", func(t *testing.T) {
+		repo := newFakeTaskRepository()
+		processor := &TaskProcessor{taskRepo: repo}
+
+		first := &queue.Task{ID: "msg-1", Attempt: 1, MaxRetry: 1}
+		processor.startJob(ctx, first, videoRecord).failAttempt(ctx, first, errors.New("ffmpeg crashed"))
+		assert.Equal(t, task.StatusRetrying, repo.jobs["msg-1"].Status)
+		assert.Nil(t, repo.jobs["msg-1"].FinishedAt)
+
+		last := &queue.Task{ID: "msg-1", Attempt: 2, MaxRetry: 1}
+		processor.startJob(ctx, last, videoRecord).failAttempt(ctx, last, errors.New("ffmpeg crashed"))
+		assert.Equal(t, task.StatusFailed, repo.jobs["msg-1"].Status)
+		assert.Equal(t, "ffmpeg crashed", repo.jobs["msg-1"].Error)
+		assert.NotNil(t, repo.jobs["msg-1"].FinishedAt)
+	})
+
+	t.Run("FailedStatus_KeepsProcessedVideoUntilFinal", func(t *testing.T) {
+		processed := &video.Video{Status: "processed", ProcessedURL: "processed/1.mp4"}
+		assert.Equal(t, "processed", failedStatus(processed, false))
+		assert.Equal(t, "failed", failedStatus(processed, true))
+
+		uploaded := &video.Video{Status: "uploaded"}
+		assert.Equal(t, "failed", failedStatus(uploaded, false))
+	})
+
+	t.Run("Succeed_CompletesProgress", func(t *testing.T) {
+		repo := newFakeTaskRepository()
+		processor := &TaskProcessor{taskRepo: repo}
+
+		processor.startJob(ctx, &queue.Task{ID: "msg-1", Attempt: 1}, videoRecord).succeed(ctx)
+
+		assert.Equal(t, task.StatusSucceeded, repo.jobs["msg-1"].Status)
+		assert.Equal(t, float64(progressDone), repo.jobs["msg-1"].Progress)
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+)
+
+const (
+	// Segundo del clip del usuario del que se extrae el poster
+	thumbnailOffset = "1"
+	// Inicio y duración de la vista previa animada
+	previewOffset   = "2"
+	previewDuration = "3"
+)
+
+// mediaAssets agrupa las claves de S3 de los recursos derivados de un video
+type mediaAssets struct {
+	ThumbnailKey string
+	PreviewKey   string
+}
+
+// generateThumbnail extrae un fotograma del clip procesado como imagen JPEG
+func generateThumbnail(ctx context.Context, sourcePath, outputPath string) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-ss", thumbnailOffset, "-i", sourcePath,
+		"-frames:v", "1", "-vf", "scale=640:-2", "-q:v", "3", outputPath)
+	return runFFmpegCommand(ctx, "thumbnail", cmd)
+}
+
+// generatePreview genera un MP4 corto, sin audio y de baja resolución para las tarjetas del frontend
+func generatePreview(ctx context.Context, sourcePath, outputPath string) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-ss", previewOffset, "-i", sourcePath,
+		"-t", previewDuration, "-vf", "scale=480:-2", "-an",
+		"-c:v", "libx264", "-preset", "veryfast", "-movflags", "+faststart", outputPath)
+	return runFFmpegCommand(ctx, "preview", cmd)
+}
+
+// generateMediaAssets crea el poster y la vista previa a partir del clip del usuario
+// (sin la intro) y los sube junto al video procesado. Los errores no son fatales:
+// un video sin miniatura sigue siendo publicable, así que solo se registran.
+func (p *TaskProcessor) generateMediaAssets(ctx context.Context, sourcePath, tempDir, baseName string) mediaAssets {
+	var assets mediaAssets
+
+	thumbnailPath := filepath.Join(tempDir, baseName+"_thumb.jpg")
+	if err := generateThumbnail(ctx, sourcePath, thumbnailPath); err != nil {
+		slog.WarnContext(ctx, "Thumbnail generation failed", "error", err)
+	} else {
+		key := fmt.Sprintf("processed/%s_thumb.jpg", baseName)
+		if err := p.uploadToS3(ctx, thumbnailPath, key); err != nil {
+			slog.WarnContext(ctx, "Failed to upload thumbnail", "s3_key", key, "error", err)
+		} else {
+			assets.ThumbnailKey = key
+		}
+	}
+
+	previewPath := filepath.Join(tempDir, baseName+"_preview.mp4")
+	if err := generatePreview(ctx, sourcePath, previewPath); err != nil {
+		slog.WarnContext(ctx, "Preview generation failed", "error", err)
+	} else {
+		key := fmt.Sprintf("processed/%s_preview.mp4", baseName)
+		if err := p.uploadToS3(ctx, previewPath, key); err != nil {
+			slog.WarnContext(ctx, "Failed to upload preview", "s3_key", key, "error", err)
+		} else {
+			assets.PreviewKey = key
+		}
+	}
+
+	return assets
+}
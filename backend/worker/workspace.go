@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// Prefijo de los directorios por tarea; la limpieza al arrancar solo toca estos
+	taskDirPrefix = "task-"
+	// El original, los intermedios del pipeline y la salida final ocupan varias veces
+	// el tamaño del archivo subido
+	diskUsageFactor = 4
+	// Pausa antes de aceptar otra tarea tras rechazar una por falta de disco
+	insufficientDiskBackoff = 30 * time.Second
+)
+
+// errInsufficientDisk indica que el worker no tiene espacio para la tarea ahora mismo.
+// No es culpa del video, así que la tarea se devuelve a la cola sin marcarlo como fallido.
+var errInsufficientDisk = errors.New("insufficient disk space")
+
+// prepareWorkRoot crea el directorio base y borra los directorios de tareas que quedaron
+// de una ejecución anterior que terminó abruptamente. Se llama al arrancar, cuando este
+// worker todavía no tiene tareas en curso.
+func prepareWorkRoot(root string) error {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("failed to create work root %s: %w", root, err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return fmt.Errorf("failed to list work root %s: %w", root, err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), taskDirPrefix) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+			log.Printf("Warning: could not remove orphaned work directory %s: %v", entry.Name(), err)
+			continue
+		}
+		removed++
+	}
+	if removed > 0 {
+		log.Printf("Removed %d orphaned work directories from %s", removed, root)
+	}
+	return nil
+}
+
+// createTaskWorkDir crea un directorio exclusivo para la tarea, de modo que tareas
+// concurrentes no se pisen los archivos ni se borren entre sí.
+func createTaskWorkDir(root string, videoID uint) (string, error) {
+	dir, err := os.MkdirTemp(root, fmt.Sprintf("%svideo%d-", taskDirPrefix, videoID))
+	if err != nil {
+		return "", fmt.Errorf("failed to create task work directory: %w", err)
+	}
+	return dir, nil
+}
+
+// freeDiskBytes devuelve el espacio disponible para usuarios no privilegiados en path
+func freeDiskBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+// objectSize consulta el tamaño del objeto en S3 sin descargarlo
+func (p *TaskProcessor) objectSize(ctx context.Context, s3Key string) (int64, error) {
+	head, err := p.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(p.bucketName),
+		Key:    aws.String(s3Key),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat S3 object %s: %w", s3Key, err)
+	}
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+// pingBucket comprueba que el bucket siga accesible, para el readiness del worker
+func (p *TaskProcessor) pingBucket(ctx context.Context) error {
+	_, err := p.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(p.bucketName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to access bucket %s: %w", p.bucketName, err)
+	}
+	return nil
+}
+
+// ensureDiskSpace comprueba antes de descargar que haya espacio para procesar el objeto
+func (p *TaskProcessor) ensureDiskSpace(ctx context.Context, root, s3Key string) error {
+	size, err := p.objectSize(ctx, s3Key)
+	if err != nil {
+		return err
+	}
+
+	free, err := freeDiskBytes(root)
+	if err != nil {
+		return fmt.Errorf("failed to check free disk space in %s: %w", root, err)
+	}
+
+	required := uint64(size)*diskUsageFactor + p.diskReserve
+	if free < required {
+		return fmt.Errorf("%w: need %d MB for %s, only %d MB free", errInsufficientDisk, required>>20, s3Key, free>>20)
+	}
+	return nil
+}
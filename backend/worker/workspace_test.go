@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspace(t *testing.T) {
+	t.Run("CreateTaskWorkDir_IsolatesTasks", func(t *testing.T) {
+		root := t.TempDir()
+
+		first, err := createTaskWorkDir(root, 7)
+		assert.NoError(t, err)
+		second, err := createTaskWorkDir(root, 7)
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, root, filepath.Dir(first))
+		assert.Contains(t, filepath.Base(first), "task-video7-")
+	})
+
+	t.Run("PrepareWorkRoot_RemovesOnlyTaskDirs", func(t *testing.T) {
+		root := t.TempDir()
+		orphan, _ := createTaskWorkDir(root, 1)
+		os.WriteFile(filepath.Join(orphan, "partial.mkv"), []byte("data"), 0o644)
+		other := filepath.Join(root, "keep-me")
+		os.Mkdir(other, 0o755)
+
+		err := prepareWorkRoot(root)
+
+		assert.NoError(t, err)
+		assert.NoDirExists(t, orphan)
+		assert.DirExists(t, other)
+	})
+
+	t.Run("PrepareWorkRoot_CreatesMissingRoot", func(t *testing.T) {
+		root := filepath.Join(t.TempDir(), "nested", "work")
+
+		err := prepareWorkRoot(root)
+
+		assert.NoError(t, err)
+		assert.DirExists(t, root)
+	})
+
+	t.Run("FreeDiskBytes", func(t *testing.T) {
+		free, err := freeDiskBytes(t.TempDir())
+
+		assert.NoError(t, err)
+		assert.Greater(t, free, uint64(0))
+	})
+}
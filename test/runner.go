@@ -148,10 +148,12 @@ func waitVideoIDByTitle(ctx context.Context, apiBase, token, upTitle string, tim
 		resp, err := client.Do(req)
 		if err == nil && resp.StatusCode == http.StatusOK {
 
-			var items []map[string]any
-			if err := json.NewDecoder(resp.Body).Decode(&items); err == nil {
+			var page struct {
+				Items []map[string]any `json:"items"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&page); err == nil {
 
-				for _, it := range items {
+				for _, it := range page.Items {
 					title, _ := it["title"].(string)
 
 					if title == upTitle {
@@ -204,12 +206,14 @@ func getAnyVideoID(ctx context.Context, apiBase, token string) (string, error) {
 		return "", fmt.Errorf("GET /videos → HTTP %d: %s", resp.StatusCode, string(b))
 	}
 
-	var items []map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return "", err
 	}
 
-	for _, it := range items {
+	for _, it := range page.Items {
 		if v, ok := it["id"]; ok {
 			return fmt.Sprint(v), nil
 		}
@@ -435,15 +439,6 @@ func main() {
 		"-duration", "1m",
 	), "GET /videos/:id/download")
 
-	goRunLoadtest(*loadtestPath, withCommon(
-		"-url", *apiBase+"/videos/"+videoID+"/mark-processed",
-		"-method", "POST",
-		"-headers", headersAuth,
-		"-concurrency", "5",
-		"-rate", "5",
-		"-duration", "30s",
-	), "POST mark-processed")
-
 	goRunLoadtest(*loadtestPath, withCommon(
 		"-url", *apiBase+"/public/videos/"+videoID+"/vote",
 		"-method", "POST",